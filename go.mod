@@ -6,7 +6,11 @@ go 1.23.0
 toolchain go1.24.2
 
 require (
+	github.com/andybalholm/brotli v1.2.0
 	github.com/joho/godotenv v1.5.1
+	github.com/klauspost/compress v1.18.0
+	github.com/mattn/go-sqlite3 v1.14.33
+	golang.org/x/text v0.28.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
@@ -0,0 +1,119 @@
+package checkpoint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/saturnines/nexus-core/pkg/errors"
+	"github.com/saturnines/nexus-core/pkg/pagination"
+)
+
+// FileStore keeps one JSON file per key in a directory.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore returns a FileStore rooted at dir, creating it if needed.
+func NewFileStore(dir string) (*FileStore, error) {
+	if dir == "" {
+		return nil, errors.WrapError(
+			fmt.Errorf("directory cannot be empty"),
+			errors.ErrConfiguration,
+			"create file state store",
+		)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, errors.WrapError(err, errors.ErrCheckpoint, "create state directory")
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+// path escapes key so any pipeline name maps to a single file in dir.
+func (s *FileStore) path(key string) string {
+	return filepath.Join(s.dir, url.PathEscape(key)+".json")
+}
+
+// Load reads the state file for key.
+func (s *FileStore) Load(_ context.Context, key string) (*pagination.State, error) {
+	data, err := os.ReadFile(s.path(key))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrCheckpoint, "read state file")
+	}
+
+	var state pagination.State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, errors.WrapError(err, errors.ErrCheckpoint, "decode state file")
+	}
+	return &state, nil
+}
+
+// Save writes the state to a temp file and renames it into place, so a crash
+// mid-write never leaves a truncated checkpoint.
+func (s *FileStore) Save(_ context.Context, key string, state pagination.State) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrCheckpoint, "encode state")
+	}
+	return s.writeFile(s.path(key), data)
+}
+
+// Delete removes the state file for key.
+func (s *FileStore) Delete(_ context.Context, key string) error {
+	if err := os.Remove(s.path(key)); err != nil && !os.IsNotExist(err) {
+		return errors.WrapError(err, errors.ErrCheckpoint, "delete state file")
+	}
+	return nil
+}
+
+func (s *FileStore) watermarkPath(key string) string {
+	return filepath.Join(s.dir, url.PathEscape(key)+".watermark")
+}
+
+// LoadWatermark reads the watermark file for key.
+func (s *FileStore) LoadWatermark(_ context.Context, key string) (string, error) {
+	data, err := os.ReadFile(s.watermarkPath(key))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", errors.WrapError(err, errors.ErrCheckpoint, "read watermark file")
+	}
+	return string(data), nil
+}
+
+// SaveWatermark writes the watermark file for key.
+func (s *FileStore) SaveWatermark(_ context.Context, key string, value string) error {
+	return s.writeFile(s.watermarkPath(key), []byte(value))
+}
+
+// writeFile writes data to a temp file in dir and renames it to path.
+func (s *FileStore) writeFile(path string, data []byte) error {
+	tmp, err := os.CreateTemp(s.dir, ".checkpoint-*")
+	if err != nil {
+		return errors.WrapError(err, errors.ErrCheckpoint, "create temp state file")
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return errors.WrapError(err, errors.ErrCheckpoint, "write state file")
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return errors.WrapError(err, errors.ErrCheckpoint, "sync state file")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.WrapError(err, errors.ErrCheckpoint, "close state file")
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return errors.WrapError(err, errors.ErrCheckpoint, "commit state file")
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+// Package sqlite implements checkpoint.StateStore on a SQLite table.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/saturnines/nexus-core/pkg/errors"
+	"github.com/saturnines/nexus-core/pkg/pagination"
+)
+
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS nexus_checkpoints (
+	key TEXT PRIMARY KEY,
+	state TEXT NOT NULL,
+	updated_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`,
+	`CREATE TABLE IF NOT EXISTS nexus_watermarks (
+	key TEXT PRIMARY KEY,
+	value TEXT NOT NULL,
+	updated_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`,
+}
+
+// Store keeps pager state in the nexus_checkpoints table and incremental
+// watermarks in nexus_watermarks.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens the database at dsn and creates the checkpoint tables.
+func Open(dsn string) (*Store, error) {
+	if dsn == "" {
+		return nil, errors.WrapError(
+			fmt.Errorf("dsn cannot be empty"),
+			errors.ErrConfiguration,
+			"open sqlite state store",
+		)
+	}
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrCheckpoint, "open sqlite state store")
+	}
+	db.SetMaxOpenConns(1)
+
+	s, err := New(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// New uses an existing database handle and creates the checkpoint tables.
+func New(db *sql.DB) (*Store, error) {
+	for _, stmt := range schema {
+		if _, err := db.Exec(stmt); err != nil {
+			return nil, errors.WrapError(err, errors.ErrCheckpoint, "create checkpoint table")
+		}
+	}
+	return &Store{db: db}, nil
+}
+
+// Load returns the saved state for key.
+func (s *Store) Load(ctx context.Context, key string) (*pagination.State, error) {
+	var raw string
+	err := s.db.QueryRowContext(ctx, `SELECT state FROM nexus_checkpoints WHERE key = ?`, key).Scan(&raw)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrCheckpoint, "load checkpoint")
+	}
+
+	var state pagination.State
+	if err := json.Unmarshal([]byte(raw), &state); err != nil {
+		return nil, errors.WrapError(err, errors.ErrCheckpoint, "decode checkpoint")
+	}
+	return &state, nil
+}
+
+// Save upserts the state for key.
+func (s *Store) Save(ctx context.Context, key string, state pagination.State) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrCheckpoint, "encode checkpoint")
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO nexus_checkpoints (key, state, updated_at) VALUES (?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT (key) DO UPDATE SET state = excluded.state, updated_at = excluded.updated_at`,
+		key, string(data),
+	)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrCheckpoint, "save checkpoint")
+	}
+	return nil
+}
+
+// Delete removes the state for key.
+func (s *Store) Delete(ctx context.Context, key string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM nexus_checkpoints WHERE key = ?`, key); err != nil {
+		return errors.WrapError(err, errors.ErrCheckpoint, "delete checkpoint")
+	}
+	return nil
+}
+
+// LoadWatermark returns the saved watermark for key.
+func (s *Store) LoadWatermark(ctx context.Context, key string) (string, error) {
+	var value string
+	err := s.db.QueryRowContext(ctx, `SELECT value FROM nexus_watermarks WHERE key = ?`, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", errors.WrapError(err, errors.ErrCheckpoint, "load watermark")
+	}
+	return value, nil
+}
+
+// SaveWatermark upserts the watermark for key.
+func (s *Store) SaveWatermark(ctx context.Context, key string, value string) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO nexus_watermarks (key, value, updated_at) VALUES (?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`,
+		key, value,
+	)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrCheckpoint, "save watermark")
+	}
+	return nil
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
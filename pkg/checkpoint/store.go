@@ -0,0 +1,28 @@
+// Package checkpoint persists pagination state so an interrupted extraction
+// can resume from the last committed page, and the watermarks used by
+// incremental syncs.
+package checkpoint
+
+import (
+	"context"
+
+	"github.com/saturnines/nexus-core/pkg/pagination"
+)
+
+// StateStore saves pager state under a key, typically the pipeline name.
+type StateStore interface {
+	// Load returns the saved state for key, or nil if there is none.
+	Load(ctx context.Context, key string) (*pagination.State, error)
+	// Save replaces the state for key.
+	Save(ctx context.Context, key string, state pagination.State) error
+	// Delete removes the state for key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// WatermarkStore saves the high-watermark of an incremental sync.
+type WatermarkStore interface {
+	// LoadWatermark returns the saved watermark for key, or "" if there is none.
+	LoadWatermark(ctx context.Context, key string) (string, error)
+	// SaveWatermark replaces the watermark for key.
+	SaveWatermark(ctx context.Context, key string, value string) error
+}
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"unicode/utf8"
 )
 
 // ConfigLoader defines the interface for loading configs
@@ -338,7 +339,7 @@ func (d *PipelineDefaults) SetDefaults(config interface{}) {
 
 	// Set default HTTP method if not specified
 	if pipeline.Source.Method == "" {
-		if pipeline.Source.Type == SourceTypeGraphQL {
+		if pipeline.Source.Type == SourceTypeGraphQL || pipeline.Source.SOAP != nil {
 			pipeline.Source.Method = "POST"
 		} else {
 			pipeline.Source.Method = "GET"
@@ -417,7 +418,7 @@ func (v *RequiredFieldValidator) Validate(config interface{}) []ValidationError
 		errors = append(errors, ValidationError{Field: "source.endpoint", Message: "is required"})
 	}
 
-	if len(pipeline.Source.ResponseMapping.Fields) == 0 {
+	if len(pipeline.Source.ResponseMapping.Fields) == 0 && pipeline.Source.ResponseMapping.Mode != MappingModePassthrough {
 		errors = append(errors, ValidationError{Field: "response_mapping.fields", Message: "at least one field is required"})
 	}
 
@@ -450,6 +451,14 @@ func (v *PaginationValidator) Validate(config interface{}) []ValidationError {
 		return errors
 	}
 
+	if pipeline.Pagination.Concurrency < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "pagination.concurrency",
+			Message: "cannot be negative",
+			Value:   pipeline.Pagination.Concurrency,
+		})
+	}
+
 	switch pipeline.Pagination.Type {
 	case PaginationTypePage:
 		if pipeline.Pagination.PageParam == "" {
@@ -492,10 +501,10 @@ func (v *PaginationValidator) Validate(config interface{}) []ValidationError {
 			})
 		}
 	case PaginationTypeLink:
-		if pipeline.Pagination.NextLinkPath == "" {
+		if pipeline.Pagination.NextLinkPath == "" && !pipeline.Pagination.LinkHeader {
 			errors = append(errors, ValidationError{
 				Field:   "pagination.next_link_path",
-				Message: "is required for link pagination",
+				Message: "either next_link_path or link_header is required for link pagination",
 			})
 		}
 	default:
@@ -643,11 +652,24 @@ func (v *SchemaFieldMappingValidator) Validate(config interface{}) []ValidationE
 
 	var errors []ValidationError
 
+	// Passthrough records carry whatever keys the API returns
+	if pipeline.Source.ResponseMapping.Mode == MappingModePassthrough {
+		return nil
+	}
+
 	// Create a map of response field names for easy lookup
 	fields := make(map[string]struct{})
 	for _, field := range pipeline.Source.ResponseMapping.Fields {
 		fields[field.Name] = struct{}{}
 	}
+	if ex := pipeline.Source.ResponseMapping.Explode; ex != nil {
+		for _, field := range ex.Fields {
+			fields[field.Name] = struct{}{}
+		}
+		if ex.IndexField != "" {
+			fields[ex.IndexField] = struct{}{}
+		}
+	}
 
 	// Check that all schema fields have corresponding response fields
 	for _, schema := range pipeline.Destination.Schema {
@@ -663,6 +685,253 @@ func (v *SchemaFieldMappingValidator) Validate(config interface{}) []ValidationE
 	return errors
 }
 
+// ResponseMappingValidator validates response mapping options
+type ResponseMappingValidator struct{}
+
+// Validate checks the REST and GraphQL response mappings
+func (v *ResponseMappingValidator) Validate(config interface{}) []ValidationError {
+	pipeline, ok := config.(*Pipeline)
+	if !ok {
+		return []ValidationError{{Field: "config", Message: "not a Pipeline"}}
+	}
+
+	var errors []ValidationError
+
+	type mapping struct {
+		field string
+		m     *ResponseMapping
+	}
+	mappings := []mapping{{"source.response_mapping", &pipeline.Source.ResponseMapping}}
+	if pipeline.Source.GraphQLConfig != nil {
+		mappings = append(mappings, mapping{"source.graphql.response_mapping", &pipeline.Source.GraphQLConfig.ResponseMapping})
+	}
+
+	for _, rm := range mappings {
+		switch rm.m.NumberMode {
+		case "", NumberModeFloat, NumberModeExact:
+		default:
+			errors = append(errors, ValidationError{
+				Field:   rm.field + ".number_mode",
+				Message: "must be float or exact",
+				Value:   rm.m.NumberMode,
+			})
+		}
+
+		errors = append(errors, validateFieldPolicies(rm.field, rm.m.Fields)...)
+
+		if rm.m.Streaming && rm.field != "source.response_mapping" {
+			errors = append(errors, ValidationError{Field: rm.field + ".streaming", Message: "is only supported for REST sources"})
+		}
+
+		switch rm.m.ResponseFormat {
+		case "", ResponseFormatJSON:
+		case ResponseFormatXML:
+			if rm.field != "source.response_mapping" {
+				errors = append(errors, ValidationError{Field: rm.field + ".response_format", Message: "is only supported for REST sources"})
+			}
+			if rm.m.Streaming {
+				errors = append(errors, ValidationError{Field: rm.field + ".streaming", Message: "is not supported with response_format xml"})
+			}
+		case ResponseFormatNDJSON, ResponseFormatCSV, ResponseFormatTSV:
+			if rm.field != "source.response_mapping" {
+				errors = append(errors, ValidationError{Field: rm.field + ".response_format", Message: "is only supported for REST sources"})
+			}
+			if rm.m.RootPath != "" {
+				errors = append(errors, ValidationError{
+					Field:   rm.field + ".root_path",
+					Message: fmt.Sprintf("is not supported with response_format %s", rm.m.ResponseFormat),
+				})
+			}
+		default:
+			errors = append(errors, ValidationError{
+				Field:   rm.field + ".response_format",
+				Message: "must be json, ndjson, csv, tsv or xml",
+				Value:   rm.m.ResponseFormat,
+			})
+		}
+
+		if rm.m.CSV != nil {
+			errors = append(errors, validateCSVFormat(rm.field, rm.m)...)
+		}
+		if rm.m.XML != nil {
+			// SOAP sources read XML without setting response_format.
+			soap := rm.field == "source.response_mapping" && pipeline.Source.SOAP != nil && rm.m.ResponseFormat == ""
+			if rm.m.ResponseFormat != ResponseFormatXML && !soap {
+				errors = append(errors, ValidationError{Field: rm.field + ".xml", Message: "requires response_format xml"})
+			}
+		}
+
+		switch rm.m.Mode {
+		case "", MappingModeFields:
+			if rm.m.Flatten != nil {
+				errors = append(errors, ValidationError{Field: rm.field + ".flatten", Message: "requires mode passthrough"})
+			}
+			if len(rm.m.Exclude) > 0 {
+				errors = append(errors, ValidationError{Field: rm.field + ".exclude", Message: "requires mode passthrough"})
+			}
+		case MappingModePassthrough:
+		default:
+			errors = append(errors, ValidationError{
+				Field:   rm.field + ".mode",
+				Message: "must be fields or passthrough",
+				Value:   rm.m.Mode,
+			})
+		}
+
+		if fl := rm.m.Flatten; fl != nil {
+			if fl.MaxDepth < 0 {
+				errors = append(errors, ValidationError{
+					Field:   rm.field + ".flatten.max_depth",
+					Message: "cannot be negative",
+					Value:   fl.MaxDepth,
+				})
+			}
+			switch fl.Arrays {
+			case "", FlattenArraysKeep, FlattenArraysJSON, FlattenArraysIndex, FlattenArraysExplode:
+			default:
+				errors = append(errors, ValidationError{
+					Field:   rm.field + ".flatten.arrays",
+					Message: "must be keep, json, index or explode",
+					Value:   fl.Arrays,
+				})
+			}
+		}
+
+		if ex := rm.m.Explode; ex != nil {
+			if ex.Path == "" {
+				errors = append(errors, ValidationError{
+					Field:   rm.field + ".explode.path",
+					Message: "is required",
+				})
+			}
+			switch ex.OnEmpty {
+			case "", ExplodeEmptyDrop, ExplodeEmptyKeep:
+			default:
+				errors = append(errors, ValidationError{
+					Field:   rm.field + ".explode.on_empty",
+					Message: "must be drop or keep",
+					Value:   ex.OnEmpty,
+				})
+			}
+			errors = append(errors, validateFieldPolicies(rm.field+".explode", ex.Fields)...)
+		}
+	}
+
+	return errors
+}
+
+// SOAPValidator validates the SOAP settings of a REST source
+type SOAPValidator struct{}
+
+// Validate checks that a SOAP source has a body and a POST method
+func (v *SOAPValidator) Validate(config interface{}) []ValidationError {
+	pipeline, ok := config.(*Pipeline)
+	if !ok {
+		return []ValidationError{{Field: "config", Message: "not a Pipeline"}}
+	}
+
+	soap := pipeline.Source.SOAP
+	if soap == nil {
+		return nil
+	}
+
+	var errors []ValidationError
+	if pipeline.Source.Type != SourceTypeREST {
+		errors = append(errors, ValidationError{Field: "source.soap", Message: "is only supported for REST sources"})
+	}
+	if strings.TrimSpace(soap.Body) == "" {
+		errors = append(errors, ValidationError{Field: "source.soap.body", Message: "is required"})
+	}
+	switch soap.Version {
+	case "", SOAPVersion11, SOAPVersion12:
+	default:
+		errors = append(errors, ValidationError{Field: "source.soap.version", Message: "must be 1.1 or 1.2", Value: soap.Version})
+	}
+	if m := pipeline.Source.Method; m != "" && !strings.EqualFold(m, "POST") {
+		errors = append(errors, ValidationError{Field: "source.method", Message: "must be POST for SOAP sources", Value: m})
+	}
+	switch pipeline.Source.ResponseMapping.ResponseFormat {
+	case "", ResponseFormatXML:
+	default:
+		errors = append(errors, ValidationError{
+			Field:   "source.response_mapping.response_format",
+			Message: "must be xml for SOAP sources",
+			Value:   pipeline.Source.ResponseMapping.ResponseFormat,
+		})
+	}
+	return errors
+}
+
+// validateCSVFormat checks the csv options of a response mapping.
+func validateCSVFormat(field string, m *ResponseMapping) []ValidationError {
+	var errors []ValidationError
+	field += ".csv"
+	if m.ResponseFormat != ResponseFormatCSV && m.ResponseFormat != ResponseFormatTSV {
+		return append(errors, ValidationError{Field: field, Message: "requires response_format csv or tsv"})
+	}
+
+	c := m.CSV
+	switch c.Header {
+	case "", CSVHeaderAuto, CSVHeaderFirstRow, CSVHeaderNone:
+	default:
+		errors = append(errors, ValidationError{Field: field + ".header", Message: "must be auto, first_row or none", Value: c.Header})
+	}
+	if len(c.Columns) > 0 && c.Header == CSVHeaderFirstRow {
+		errors = append(errors, ValidationError{Field: field + ".columns", Message: "cannot be used with header first_row"})
+	}
+	if c.SkipRows < 0 {
+		errors = append(errors, ValidationError{Field: field + ".skip_rows", Message: "must not be negative", Value: c.SkipRows})
+	}
+
+	chars := []struct {
+		name, value string
+	}{{"delimiter", c.Delimiter}, {"quote", c.Quote}, {"comment", c.Comment}}
+	for _, ch := range chars {
+		if ch.value == "" || (ch.name == "quote" && ch.value == "none") {
+			continue
+		}
+		if utf8.RuneCountInString(ch.value) != 1 || ch.value == "\n" || ch.value == "\r" {
+			errors = append(errors, ValidationError{Field: field + "." + ch.name, Message: "must be a single character", Value: ch.value})
+		}
+	}
+	if c.Delimiter != "" && (c.Delimiter == c.Quote || c.Delimiter == c.Comment) {
+		errors = append(errors, ValidationError{Field: field + ".delimiter", Message: "must differ from quote and comment", Value: c.Delimiter})
+	}
+	return errors
+}
+
+// validateFieldPolicies checks the on_missing and on_null settings of fields.
+func validateFieldPolicies(field string, fields []Field) []ValidationError {
+	var errors []ValidationError
+	for i, f := range fields {
+		prefix := fmt.Sprintf("%s.fields[%d]", field, i)
+		policies := []struct {
+			name   string
+			policy FieldPolicy
+		}{{"on_missing", f.OnMissing}, {"on_null", f.OnNull}}
+		for _, p := range policies {
+			switch p.policy {
+			case "", FieldPolicyOmit, FieldPolicyNull, FieldPolicyError:
+			case FieldPolicyDefault:
+				if f.DefaultValue == nil {
+					errors = append(errors, ValidationError{
+						Field:   prefix + ".default_value",
+						Message: fmt.Sprintf("is required when %s is default", p.name),
+					})
+				}
+			default:
+				errors = append(errors, ValidationError{
+					Field:   prefix + "." + p.name,
+					Message: "must be omit, null, default or error",
+					Value:   p.policy,
+				})
+			}
+		}
+	}
+
+	return errors
+}
+
 // RetryConfigValidator validates retry configuration
 type RetryConfigValidator struct{}
 
@@ -705,5 +974,163 @@ func (v *RetryConfigValidator) Validate(config interface{}) []ValidationError {
 		})
 	}
 
+	for i, method := range pipeline.RetryConfig.RetryMethods {
+		if strings.TrimSpace(method) == "" {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("retry_config.retry_methods[%d]", i),
+				Message: "cannot be empty",
+			})
+		}
+	}
+
+	return errors
+}
+
+// IncrementalValidator validates incremental sync configuration
+type IncrementalValidator struct{}
+
+// Validate checks that the cursor field and where to send it are set
+func (v *IncrementalValidator) Validate(config interface{}) []ValidationError {
+	pipeline, ok := config.(*Pipeline)
+	if !ok {
+		return []ValidationError{{Field: "config", Message: "not a Pipeline"}}
+	}
+
+	var errors []ValidationError
+
+	inc := pipeline.Incremental
+	if inc == nil {
+		return errors
+	}
+
+	if inc.CursorField == "" {
+		errors = append(errors, ValidationError{
+			Field:   "incremental.cursor_field",
+			Message: "is required",
+		})
+	}
+
+	switch pipeline.Source.Type {
+	case SourceTypeGraphQL:
+		if inc.Variable == "" {
+			errors = append(errors, ValidationError{
+				Field:   "incremental.variable",
+				Message: "is required for GraphQL sources",
+			})
+		}
+	default:
+		if inc.Param == "" {
+			errors = append(errors, ValidationError{
+				Field:   "incremental.param",
+				Message: "is required for REST sources",
+			})
+		}
+	}
+
+	return errors
+}
+
+// RateLimitValidator validates rate limit configuration
+type RateLimitValidator struct{}
+
+// Validate checks that rate limit values are not negative
+func (v *RateLimitValidator) Validate(config interface{}) []ValidationError {
+	pipeline, ok := config.(*Pipeline)
+	if !ok {
+		return []ValidationError{{Field: "config", Message: "not a Pipeline"}}
+	}
+
+	var errors []ValidationError
+
+	rl := pipeline.RateLimit
+	if rl == nil {
+		return errors
+	}
+
+	if rl.RequestsPerSecond < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "rate_limit.requests_per_second",
+			Message: "cannot be negative",
+			Value:   rl.RequestsPerSecond,
+		})
+	}
+	if rl.RequestsPerMinute < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "rate_limit.requests_per_minute",
+			Message: "cannot be negative",
+			Value:   rl.RequestsPerMinute,
+		})
+	}
+	if rl.RequestsPerSecond > 0 && rl.RequestsPerMinute > 0 {
+		errors = append(errors, ValidationError{
+			Field:   "rate_limit",
+			Message: "set either requests_per_second or requests_per_minute, not both",
+		})
+	}
+	if rl.Burst < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "rate_limit.burst",
+			Message: "cannot be negative",
+			Value:   rl.Burst,
+		})
+	}
+	if rl.MaxWait < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "rate_limit.max_wait",
+			Message: "cannot be negative",
+			Value:   rl.MaxWait,
+		})
+	}
+
+	return errors
+}
+
+// ErrorPolicyValidator validates error policy configuration
+type ErrorPolicyValidator struct{}
+
+// Validate checks the mode and error budget
+func (v *ErrorPolicyValidator) Validate(config interface{}) []ValidationError {
+	pipeline, ok := config.(*Pipeline)
+	if !ok {
+		return []ValidationError{{Field: "config", Message: "not a Pipeline"}}
+	}
+
+	var errors []ValidationError
+
+	ep := pipeline.ErrorPolicy
+	if ep == nil {
+		return errors
+	}
+
+	switch ep.Mode {
+	case "", ErrorPolicyFailFast, ErrorPolicySkip:
+	default:
+		errors = append(errors, ValidationError{
+			Field:   "error_policy.mode",
+			Message: "must be fail_fast or skip",
+			Value:   ep.Mode,
+		})
+	}
+	if ep.MaxErrors < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "error_policy.max_errors",
+			Message: "cannot be negative",
+			Value:   ep.MaxErrors,
+		})
+	}
+	if ep.MaxErrorPercent < 0 || ep.MaxErrorPercent > 100 {
+		errors = append(errors, ValidationError{
+			Field:   "error_policy.max_error_percent",
+			Message: "must be between 0 and 100",
+			Value:   ep.MaxErrorPercent,
+		})
+	}
+	if ep.DeadLetter != nil && ep.DeadLetter.Type == "" {
+		errors = append(errors, ValidationError{
+			Field:   "error_policy.dead_letter.type",
+			Message: "is required",
+		})
+	}
+
 	return errors
 }
@@ -338,6 +338,41 @@ destination:
 	if errorMsg := err.Error(); !strings.Contains(errorMsg, "username") {
 		t.Errorf("Error should mention non-existent field 'username', got: %s", errorMsg)
 	}
+
+	// Exploded fields and the index column can be mapped too
+	explodeYaml := `
+name: test-pipeline
+source:
+  type: rest
+  endpoint: https://api.example.com/orders
+  response_mapping:
+    fields:
+      - name: id
+        path: id
+    explode:
+      path: line_items
+      index_field: line
+      fields:
+        - name: sku
+          path: sku
+destination:
+  type: postgres
+  table: order_lines
+  schema:
+    - name: order_id
+      type: integer
+      source: id
+    - name: line
+      type: integer
+      source: line
+    - name: sku
+      type: string
+      source: sku
+`
+
+	if _, err := loader.Parse([]byte(explodeYaml)); err != nil {
+		t.Errorf("Failed to parse mapping with exploded fields: %v", err)
+	}
 }
 
 // test invalid YAML syntax
@@ -429,3 +464,299 @@ destination:
 		t.Error("Error should mention non-existent field 'email'")
 	}
 }
+
+// link pagination needs either a body path or the Link header
+func TestPipelineLoader_LinkPagination(t *testing.T) {
+	base := `
+name: test-pipeline
+source:
+  type: rest
+  endpoint: https://api.example.com/data
+  response_mapping:
+    fields:
+      - name: id
+        path: id
+pagination:
+  type: link
+`
+	testCases := []struct {
+		name      string
+		extra     string
+		expectErr bool
+	}{
+		{"next_link_path", "  next_link_path: nextRecordsUrl\n", false},
+		{"link_header", "  link_header: true\n", false},
+		{"neither", "", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			loader := NewPipelineLoader(&EnvExpander{}, &PipelineDefaults{}, &PaginationValidator{})
+			_, err := loader.Parse([]byte(base + tc.extra))
+			if tc.expectErr {
+				if err == nil || !strings.Contains(err.Error(), "pagination.next_link_path") {
+					t.Errorf("Expected next_link_path validation error, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// incremental needs a cursor field and a param (REST) or variable (GraphQL)
+func TestPipelineLoader_Incremental(t *testing.T) {
+	base := `
+name: test-pipeline
+source:
+  type: rest
+  endpoint: https://api.example.com/data
+  response_mapping:
+    fields:
+      - name: id
+        path: id
+incremental:
+`
+	testCases := []struct {
+		name       string
+		extra      string
+		errorField string
+	}{
+		{"valid", "  cursor_field: updated_at\n  param: updated_since\n", ""},
+		{"missing cursor field", "  param: updated_since\n", "incremental.cursor_field"},
+		{"missing param", "  cursor_field: updated_at\n", "incremental.param"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			loader := NewPipelineLoader(&EnvExpander{}, &PipelineDefaults{}, &IncrementalValidator{})
+			_, err := loader.Parse([]byte(base + tc.extra))
+			if tc.errorField == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.errorField) {
+				t.Errorf("Expected error mentioning %q, got: %v", tc.errorField, err)
+			}
+		})
+	}
+}
+
+func TestPipelineLoader_RateLimit(t *testing.T) {
+	base := `
+name: test-pipeline
+source:
+  type: rest
+  endpoint: https://api.example.com/data
+  response_mapping:
+    fields:
+      - name: id
+        path: id
+rate_limit:
+`
+	testCases := []struct {
+		name       string
+		extra      string
+		errorField string
+	}{
+		{"per second", "  requests_per_second: 5\n  burst: 10\n", ""},
+		{"headers only", "  max_wait: 30\n", ""},
+		{"both rates", "  requests_per_second: 5\n  requests_per_minute: 100\n", "rate_limit"},
+		{"negative burst", "  burst: -1\n", "rate_limit.burst"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			loader := NewPipelineLoader(&EnvExpander{}, &PipelineDefaults{}, &RateLimitValidator{})
+			_, err := loader.Parse([]byte(base + tc.extra))
+			if tc.errorField == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.errorField) {
+				t.Errorf("Expected error mentioning %q, got: %v", tc.errorField, err)
+			}
+		})
+	}
+}
+
+func TestPipelineLoader_ErrorPolicy(t *testing.T) {
+	base := `
+name: test-pipeline
+source:
+  type: rest
+  endpoint: https://api.example.com/data
+  response_mapping:
+    fields:
+      - name: id
+        path: id
+error_policy:
+`
+	testCases := []struct {
+		name       string
+		extra      string
+		errorField string
+	}{
+		{"skip with budget", "  mode: skip\n  max_errors: 10\n  max_error_percent: 5\n", ""},
+		{"dead letter", "  mode: skip\n  dead_letter:\n    type: jsonl\n    dsn: rejects.jsonl\n", ""},
+		{"unknown mode", "  mode: ignore\n", "error_policy.mode"},
+		{"percent over 100", "  mode: skip\n  max_error_percent: 150\n", "error_policy.max_error_percent"},
+		{"dead letter without type", "  mode: skip\n  dead_letter:\n    dsn: rejects.jsonl\n", "error_policy.dead_letter.type"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			loader := NewPipelineLoader(&EnvExpander{}, &PipelineDefaults{}, &ErrorPolicyValidator{})
+			_, err := loader.Parse([]byte(base + tc.extra))
+			if tc.errorField == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.errorField) {
+				t.Errorf("Expected error mentioning %q, got: %v", tc.errorField, err)
+			}
+		})
+	}
+}
+
+func TestPipelineLoader_SOAP(t *testing.T) {
+	base := `
+name: test-pipeline
+source:
+  type: rest
+  endpoint: https://erp.example.com/OrderService
+  response_mapping:
+    root_path: Envelope.Body.GetOrdersResponse.Order
+    fields:
+      - name: id
+        path: "@id"
+`
+	testCases := []struct {
+		name       string
+		extra      string
+		errorField string
+	}{
+		{"valid", "  soap:\n    version: \"1.2\"\n    action: urn:GetOrders\n    body: <GetOrders/>\n", ""},
+		{"missing body", "  soap:\n    action: urn:GetOrders\n", "source.soap.body"},
+		{"unknown version", "  soap:\n    version: \"2.0\"\n    body: <GetOrders/>\n", "source.soap.version"},
+		{"GET method", "  method: GET\n  soap:\n    body: <GetOrders/>\n", "source.method"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			loader := NewPipelineLoader(&EnvExpander{}, &PipelineDefaults{}, &SOAPValidator{})
+			_, err := loader.Parse([]byte(base + tc.extra))
+			if tc.errorField == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.errorField) {
+				t.Errorf("Expected error mentioning %q, got: %v", tc.errorField, err)
+			}
+		})
+	}
+}
+
+func TestPipelineLoader_ResponseMapping(t *testing.T) {
+	base := `
+name: test-pipeline
+source:
+  type: rest
+  endpoint: https://api.example.com/data
+  response_mapping:
+    fields:
+      - name: id
+        path: id
+`
+	testCases := []struct {
+		name       string
+		extra      string
+		errorField string
+	}{
+		{"default number mode", "", ""},
+		{"exact numbers", "    number_mode: exact\n", ""},
+		{"unknown number mode", "    number_mode: bigint\n", "source.response_mapping.number_mode"},
+		{"field policies", "        on_missing: error\n        on_null: null\n", ""},
+		{"default policy with value", "        on_null: default\n        default_value: 0\n", ""},
+		{"default policy without value", "        on_missing: default\n", "source.response_mapping.fields[0].default_value"},
+		{"unknown field policy", "        on_null: skip\n", "source.response_mapping.fields[0].on_null"},
+		{"explode", "    explode:\n      path: line_items\n      index_field: line\n      on_empty: keep\n      fields:\n        - name: sku\n          path: sku\n", ""},
+		{"explode without path", "    explode:\n      fields:\n        - name: sku\n          path: sku\n", "source.response_mapping.explode.path"},
+		{"unknown explode on_empty", "    explode:\n      path: line_items\n      on_empty: skip\n", "source.response_mapping.explode.on_empty"},
+		{"explode field policy", "    explode:\n      path: line_items\n      fields:\n        - name: sku\n          path: sku\n          on_null: blank\n", "source.response_mapping.explode.fields[0].on_null"},
+		{"passthrough", "    mode: passthrough\n    exclude: [secret]\n    flatten:\n      separator: __\n      max_depth: 2\n      arrays: index\n", ""},
+		{"unknown mode", "    mode: raw\n", "source.response_mapping.mode"},
+		{"flatten without passthrough", "    flatten:\n      arrays: json\n", "source.response_mapping.flatten"},
+		{"exclude without passthrough", "    exclude: [secret]\n", "source.response_mapping.exclude"},
+		{"negative flatten depth", "    mode: passthrough\n    flatten:\n      max_depth: -1\n", "source.response_mapping.flatten.max_depth"},
+		{"unknown flatten arrays", "    mode: passthrough\n    flatten:\n      arrays: csv\n", "source.response_mapping.flatten.arrays"},
+		{"streaming", "    streaming: true\n", ""},
+		{"ndjson", "    response_format: ndjson\n", ""},
+		{"ndjson with root path", "    response_format: ndjson\n    root_path: data\n", "source.response_mapping.root_path"},
+		{"unknown response format", "    response_format: yaml\n", "source.response_mapping.response_format"},
+		{"csv", "    response_format: csv\n    csv:\n      header: none\n      columns: [id]\n      delimiter: \";\"\n      quote: none\n      skip_rows: 2\n", ""},
+		{"tsv with root path", "    response_format: tsv\n    root_path: data\n", "source.response_mapping.root_path"},
+		{"csv options without csv format", "    csv:\n      delimiter: \";\"\n", "source.response_mapping.csv"},
+		{"csv unknown header", "    response_format: csv\n    csv:\n      header: maybe\n", "source.response_mapping.csv.header"},
+		{"csv long delimiter", "    response_format: csv\n    csv:\n      delimiter: \"||\"\n", "source.response_mapping.csv.delimiter"},
+		{"csv delimiter equals quote", "    response_format: csv\n    csv:\n      delimiter: \"'\"\n      quote: \"'\"\n", "source.response_mapping.csv.delimiter"},
+		{"csv columns with header row", "    response_format: csv\n    csv:\n      header: first_row\n      columns: [id]\n", "source.response_mapping.csv.columns"},
+		{"xml", "    response_format: xml\n    root_path: Orders.Order\n    xml:\n      arrays: [Line]\n", ""},
+		{"xml streaming", "    response_format: xml\n    streaming: true\n", "source.response_mapping.streaming"},
+		{"xml options without xml format", "    xml:\n      attribute_prefix: _\n", "source.response_mapping.xml"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			loader := NewPipelineLoader(&EnvExpander{}, &PipelineDefaults{}, &ResponseMappingValidator{})
+			_, err := loader.Parse([]byte(base + tc.extra))
+			if tc.errorField == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.errorField) {
+				t.Errorf("Expected error mentioning %q, got: %v", tc.errorField, err)
+			}
+		})
+	}
+}
+
+func TestPipelineLoader_PassthroughWithoutFields(t *testing.T) {
+	yamlData := `
+name: test-pipeline
+source:
+  type: rest
+  endpoint: https://api.example.com/accounts
+  response_mapping:
+    mode: passthrough
+destination:
+  type: postgres
+  table: accounts
+  schema:
+    - name: account_id
+      type: string
+      source: Id
+`
+	loader := NewPipelineLoader(
+		&EnvExpander{},
+		&PipelineDefaults{},
+		&RequiredFieldValidator{},
+		&SchemaFieldMappingValidator{},
+		&ResponseMappingValidator{},
+	)
+	if _, err := loader.Parse([]byte(yamlData)); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
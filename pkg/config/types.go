@@ -12,6 +12,11 @@ type Pipeline struct {
 	PaginationRef string                 `yaml:"pagination_ref,omitempty"` // Reference to a pagination config
 	Destination   Destination            `yaml:"destination"`              // Required destination configuration
 	RetryConfig   *RetryConfig           `yaml:"retry_config,omitempty"`   // Optional retry configuration
+	RateLimit     *RateLimit             `yaml:"rate_limit,omitempty"`     // Optional client-side throttling
+	Incremental   *Incremental           `yaml:"incremental,omitempty"`    // Optional high-watermark sync
+	Children      []Child                `yaml:"children,omitempty"`       // Optional per-record sub-resources
+	ErrorPolicy   *ErrorPolicy           `yaml:"error_policy,omitempty"`   // Optional handling of bad records
+	Strict        bool                   `yaml:"strict,omitempty"`         // Fail a page when a field path resolves on none of its items
 	References    map[string]interface{} `yaml:"references,omitempty"`     // Reusable configuration blocks
 }
 
@@ -31,6 +36,63 @@ type RetryConfig struct {
 	InitialBackoff    float64 `yaml:"initial_backoff,omitempty"`    // Initial backoff in seconds
 	BackoffMultiplier float64 `yaml:"backoff_multiplier,omitempty"` // Multiplier for exponential backoff
 	RetryableStatuses []int   `yaml:"retryable_statuses,omitempty"` // HTTP status codes to retry
+
+	RetryableErrorCodes    []string `yaml:"retryable_error_codes,omitempty"`    // In-body API error codes to retry
+	RetryableGraphQLErrors []string `yaml:"retryable_graphql_errors,omitempty"` // GraphQL error codes (or message substrings) to retry
+
+	RetryMethods       []string `yaml:"retry_methods,omitempty"`        // Methods to retry (default: idempotent methods)
+	RetryNonIdempotent bool     `yaml:"retry_non_idempotent,omitempty"` // Retry every method, including POST and PATCH
+	IdempotencyKey     bool     `yaml:"idempotency_key,omitempty"`      // Send an Idempotency-Key on REST POSTs and retry them
+}
+
+// RateLimit throttles outgoing requests with a token bucket. Rate limit
+// headers from the API slow requests down further unless IgnoreHeaders is set.
+type RateLimit struct {
+	RequestsPerSecond float64 `yaml:"requests_per_second,omitempty"` // Sustained rate
+	RequestsPerMinute float64 `yaml:"requests_per_minute,omitempty"` // Alternative to requests_per_second
+	Burst             int     `yaml:"burst,omitempty"`               // Requests allowed at once (default 1)
+	MaxWait           float64 `yaml:"max_wait,omitempty"`            // Cap in seconds on header-driven pauses (default 300)
+	IgnoreHeaders     bool    `yaml:"ignore_headers,omitempty"`      // Don't adapt to Retry-After / RateLimit headers
+}
+
+// Incremental configures high-watermark syncs. The largest CursorField value
+// seen in a completed run is sent in Param (REST) or Variable (GraphQL) on
+// the next run.
+type Incremental struct {
+	CursorField  string `yaml:"cursor_field"`            // Extracted field to track, e.g. updated_at
+	Param        string `yaml:"param,omitempty"`         // REST query parameter, e.g. created[gte]
+	Variable     string `yaml:"variable,omitempty"`      // GraphQL variable, e.g. updatedSince
+	InitialValue string `yaml:"initial_value,omitempty"` // Sent on the first run, if set
+}
+
+// ErrorPolicy decides what happens to items that cannot be mapped. With
+// fail_fast (the default) the first bad item aborts the run. With skip, bad
+// items are dropped and written to DeadLetter, and the run only fails once
+// MaxErrors items or MaxErrorPercent of all items have been dropped.
+type ErrorPolicy struct {
+	Mode            ErrorPolicyMode `yaml:"mode,omitempty"`              // fail_fast (default) or skip
+	MaxErrors       int             `yaml:"max_errors,omitempty"`        // Dropped items allowed per run, 0 = unlimited
+	MaxErrorPercent float64         `yaml:"max_error_percent,omitempty"` // Dropped share of items allowed per run, 0 = unlimited
+	DeadLetter      *Destination    `yaml:"dead_letter,omitempty"`       // Where dropped items are written
+}
+
+// ErrorPolicyMode defines how bad items are handled
+type ErrorPolicyMode string
+
+const (
+	ErrorPolicyFailFast ErrorPolicyMode = "fail_fast"
+	ErrorPolicySkip     ErrorPolicyMode = "skip"
+)
+
+// Child fetches a sub-resource once per parent record. The endpoint, headers,
+// query params and GraphQL variables may reference the parent record with
+// {{parent.<field>}}.
+type Child struct {
+	Name        string      `yaml:"name"`                  // Parent field the child records are attached under
+	Source      Source      `yaml:"source"`                // Child source; auth defaults to the parent's
+	Pagination  *Pagination `yaml:"pagination,omitempty"`  // Optional per-child pagination
+	Concurrency int         `yaml:"concurrency,omitempty"` // Child requests in flight per page (default 4)
+	ParentKeys  []string    `yaml:"parent_keys,omitempty"` // Parent fields copied onto child records
 }
 
 // Source represents API config
@@ -43,11 +105,29 @@ type Source struct {
 	Auth            *Auth             `yaml:"auth,omitempty"`         // Direct authentication configuration
 	AuthRef         string            `yaml:"auth_ref,omitempty"`     // Reference to an auth config
 	ResponseMapping ResponseMapping   `yaml:"response_mapping"`       // Required response mapping
+	SOAP            *SOAPSource       `yaml:"soap,omitempty"`         // REST only: send a SOAP envelope and read XML
 
 	// GraphQL
 	GraphQLConfig *GraphQLSource `yaml:"graphql,omitempty"`
 }
 
+// SOAPSource wraps a REST source's requests in a SOAP envelope. Requests
+// are POSTed and responses default to response_format xml.
+type SOAPSource struct {
+	Version SOAPVersion `yaml:"version,omitempty"` // 1.1 (default) or 1.2
+	Action  string      `yaml:"action,omitempty"`  // SOAPAction of the operation
+	Header  string      `yaml:"header,omitempty"`  // XML placed in the envelope header, e.g. WS-Security
+	Body    string      `yaml:"body"`              // Required XML placed in the envelope body
+}
+
+// SOAPVersion selects the envelope namespace and how the action is sent.
+type SOAPVersion string
+
+const (
+	SOAPVersion11 SOAPVersion = "1.1"
+	SOAPVersion12 SOAPVersion = "1.2"
+)
+
 // SourceType defines currently supported api types
 type SourceType string
 
@@ -111,9 +191,115 @@ type ResponseMapping struct {
 	Fields          []Field           `yaml:"fields"`                    // Fields to extract
 	Transformations map[string]string `yaml:"transformations,omitempty"` // Field transformations
 	ErrorPath       string            `yaml:"error_path,omitempty"`      // Path to error message in response
+	ErrorCodePath   string            `yaml:"error_code_path,omitempty"` // Path to error code (defaults to error_path)
 	SuccessPath     string            `yaml:"success_path,omitempty"`    // Path to success flag in response
+	NumberMode      NumberMode        `yaml:"number_mode,omitempty"`     // How JSON numbers are decoded: float (default) or exact
+	Explode         *Explode          `yaml:"explode,omitempty"`         // Optional fan-out of a nested array into one record per element
+	Mode            MappingMode       `yaml:"mode,omitempty"`            // fields (default) or passthrough
+	Flatten         *Flatten          `yaml:"flatten,omitempty"`         // Passthrough only: merge nested objects into prefixed keys
+	Exclude         []string          `yaml:"exclude,omitempty"`         // Passthrough only: keys left out of the record
+	Streaming       bool              `yaml:"streaming,omitempty"`       // REST only: decode items one at a time instead of buffering the body
+	ResponseFormat  ResponseFormat    `yaml:"response_format,omitempty"` // REST only: body format, json (default), ndjson, csv, tsv or xml
+	CSV             *CSVFormat        `yaml:"csv,omitempty"`             // Options for csv and tsv bodies
+	XML             *XMLFormat        `yaml:"xml,omitempty"`             // Options for xml bodies
+}
+
+// ResponseFormat defines how a REST response body is decoded into items.
+type ResponseFormat string
+
+const (
+	ResponseFormatJSON   ResponseFormat = "json"   // one JSON document
+	ResponseFormatNDJSON ResponseFormat = "ndjson" // one JSON item per line (JSON Lines)
+	ResponseFormatCSV    ResponseFormat = "csv"    // delimited rows, one item per row
+	ResponseFormatTSV    ResponseFormat = "tsv"    // csv with a tab delimiter
+	ResponseFormatXML    ResponseFormat = "xml"    // one XML document, see XML
+)
+
+// XMLFormat describes how an XML body is turned into the maps and arrays
+// paths select from. Elements become keys, attributes become keys with
+// AttributePrefix, and repeated elements become arrays.
+type XMLFormat struct {
+	AttributePrefix string            `yaml:"attribute_prefix,omitempty"` // Default "@"
+	TextKey         string            `yaml:"text_key,omitempty"`         // Key for the text of elements with attributes or children, default "#text"
+	Namespaces      map[string]string `yaml:"namespaces,omitempty"`       // Prefix to namespace URI; names in these namespaces are keyed "prefix:name"
+	Arrays          []string          `yaml:"arrays,omitempty"`           // Element names always decoded as arrays, even when they occur once
+}
+
+// CSVFormat describes a delimited body. Each row becomes an item keyed by
+// column name, so Field.Path selects a column by its name.
+type CSVFormat struct {
+	Header     CSVHeader `yaml:"header,omitempty"`      // first_row (default; auto with Columns), auto or none
+	Columns    []string  `yaml:"columns,omitempty"`     // Column names when the body has no header row
+	Delimiter  string    `yaml:"delimiter,omitempty"`   // Single character, default "," (tab for tsv)
+	Quote      string    `yaml:"quote,omitempty"`       // Single character, default '"'; "none" disables quoting
+	Comment    string    `yaml:"comment,omitempty"`     // Lines starting with this character are skipped
+	SkipRows   int       `yaml:"skip_rows,omitempty"`   // Lines skipped before the header, e.g. a report title
+	NullValues []string  `yaml:"null_values,omitempty"` // Cell values read as null, e.g. "" or "N/A"
 }
 
+// CSVHeader defines whether the first row of a csv body names the columns.
+type CSVHeader string
+
+const (
+	CSVHeaderAuto     CSVHeader = "auto"      // a header if it names a column from Columns or a field path
+	CSVHeaderFirstRow CSVHeader = "first_row" // the first row is the header
+	CSVHeaderNone     CSVHeader = "none"      // no header; names come from Columns
+)
+
+// MappingMode defines how items become records. In passthrough mode the
+// whole item is emitted and Fields only add or override keys.
+type MappingMode string
+
+const (
+	MappingModeFields      MappingMode = "fields"
+	MappingModePassthrough MappingMode = "passthrough"
+)
+
+// Flatten turns nested objects into top-level keys, e.g. address.city
+// becomes address_city.
+type Flatten struct {
+	Separator string        `yaml:"separator,omitempty"` // Joins key segments (default "_")
+	MaxDepth  int           `yaml:"max_depth,omitempty"` // Nesting levels merged; deeper values are kept whole (0 = no limit)
+	Arrays    FlattenArrays `yaml:"arrays,omitempty"`    // How arrays are handled (default keep)
+}
+
+// FlattenArrays defines how Flatten handles arrays.
+type FlattenArrays string
+
+const (
+	FlattenArraysKeep    FlattenArrays = "keep"    // leave arrays as values
+	FlattenArraysJSON    FlattenArrays = "json"    // encode arrays as JSON strings
+	FlattenArraysIndex   FlattenArrays = "index"   // one key per element, e.g. tags_0
+	FlattenArraysExplode FlattenArrays = "explode" // one record per element
+)
+
+// Explode turns each item into one record per element of a nested array.
+// Every record carries the item's Fields plus the element's own Fields.
+type Explode struct {
+	Path       string       `yaml:"path"`                  // Path to the array within each item, e.g. line_items
+	Fields     []Field      `yaml:"fields"`                // Fields read from each element
+	IndexField string       `yaml:"index_field,omitempty"` // Optional field holding the element's position
+	OnEmpty    ExplodeEmpty `yaml:"on_empty,omitempty"`    // What to do when the array is empty or missing (default drop)
+}
+
+// ExplodeEmpty defines how items with an empty nested array are handled.
+type ExplodeEmpty string
+
+const (
+	ExplodeEmptyDrop ExplodeEmpty = "drop" // emit no record for the item
+	ExplodeEmptyKeep ExplodeEmpty = "keep" // emit one record with null element fields
+)
+
+// NumberMode defines how JSON numbers in responses are decoded. In exact
+// mode numbers are kept as json.Number, so 64-bit IDs and decimal amounts
+// reach the destination unchanged.
+type NumberMode string
+
+const (
+	NumberModeFloat NumberMode = "float"
+	NumberModeExact NumberMode = "exact"
+)
+
 // Field defines a specific field to extract
 type Field struct {
 	Name         string          `yaml:"name"`                    // Name of extracted field
@@ -121,11 +307,26 @@ type Field struct {
 	Type         string          `yaml:"type,omitempty"`          // Data type for conversion
 	DefaultValue interface{}     `yaml:"default_value,omitempty"` // Default value if field is missing
 	Transform    *FieldTransform `yaml:"transform,omitempty"`     // NEW: Transform configuration
+	OnMissing    FieldPolicy     `yaml:"on_missing,omitempty"`    // What to emit when the path does not resolve
+	OnNull       FieldPolicy     `yaml:"on_null,omitempty"`       // What to emit when the value is JSON null
 }
 
+// FieldPolicy defines what a field yields when its value is missing or
+// null. When unset the field gets DefaultValue if one is configured and is
+// omitted otherwise.
+type FieldPolicy string
+
+const (
+	FieldPolicyOmit    FieldPolicy = "omit"    // leave the field out of the record
+	FieldPolicyNull    FieldPolicy = "null"    // emit the field with a nil value
+	FieldPolicyDefault FieldPolicy = "default" // emit DefaultValue
+	FieldPolicyError   FieldPolicy = "error"   // reject the item
+)
+
 // Pagination defines different pagination types
 type Pagination struct {
-	Type PaginationType `yaml:"type"` // Pagination type (page, offset, cursor, link)
+	Type        PaginationType `yaml:"type"`                  // Pagination type (page, offset, cursor, link)
+	Concurrency int            `yaml:"concurrency,omitempty"` // Parallel page requests once totals are known (page, offset)
 
 	// Page-based pagination (if Type="page")
 	PageParam      string `yaml:"page_param,omitempty"`
@@ -161,9 +362,11 @@ const (
 
 // Destination defines the data storage destination
 type Destination struct {
-	Type   DestinationType `yaml:"type"`   // Destination type (postgres, mongodb, etc.)
-	Table  string          `yaml:"table"`  // Table/collection name
-	Schema []Schema        `yaml:"schema"` // Schema definitions
+	Type      DestinationType `yaml:"type"`                 // Destination type (postgres, mongodb, etc.)
+	DSN       string          `yaml:"dsn,omitempty"`        // Connection string for the destination
+	Table     string          `yaml:"table"`                // Table/collection name
+	Schema    []Schema        `yaml:"schema"`               // Schema definitions
+	BatchSize int             `yaml:"batch_size,omitempty"` // Records written per batch
 }
 
 // DestinationType defines supported destination types
@@ -172,6 +375,8 @@ type DestinationType string
 const (
 	DestinationPostgres DestinationType = "postgres"
 	DestinationMongoDB  DestinationType = "mongodb"
+	DestinationSQLite   DestinationType = "sqlite"
+	DestinationJSONL    DestinationType = "jsonl"
 )
 
 // Schema defines the database schema fields
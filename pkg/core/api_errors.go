@@ -0,0 +1,291 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/errors"
+	"github.com/saturnines/nexus-core/pkg/pagination"
+)
+
+// fetch sends req and buffers the response body. A 200 response whose body
+// reports an API error (see checkAPIError) is retried per RetryConfig when
+// its error code is retryable; otherwise the *errors.APIError is returned.
+// GraphQL errors listed in RetryableGraphQLErrors are retried the same way;
+// once attempts run out the response is returned so the usual ErrGraphQL is
+// reported. Non-200 responses are returned as-is for the caller to classify.
+func (c *Connector) fetch(req *http.Request) (*http.Response, []byte, error) {
+	req, err := rewind(req)
+	if err != nil {
+		return nil, nil, errors.WrapError(err, errors.ErrHTTPRequest, "rewind request body")
+	}
+	ctx, _ := withAttemptCounter(req.Context())
+	req = req.WithContext(ctx)
+
+	for attempt := 0; ; attempt++ {
+		countAttempt(ctx, 0)
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return nil, nil, doError(err)
+		}
+
+		body, err := readAndBuffer(resp)
+		if err != nil {
+			return nil, nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return resp, body, nil
+		}
+		// XML is decoded once, here; resp carries the document to the API
+		// error check, extraction and the pager. Malformed bodies are left
+		// to the extractor.
+		if e, ok := c.extractor.(*XMLExtractor); ok {
+			if doc, err := e.decode(body); err == nil {
+				resp.Body = pagination.NewDecodedBody(doc)
+			}
+		}
+
+		retry := c.cfg.RetryConfig
+		exhausted := retry == nil || attempt >= retry.MaxAttempts-1
+
+		var hint time.Duration
+		if apiErr := c.checkAPIError(resp, body); apiErr != nil {
+			if !apiErr.Retryable || exhausted {
+				annotateAPIError(apiErr, resp, body)
+				return nil, nil, apiErr
+			}
+		} else {
+			retryable, d := c.checkGraphQLRetry(body)
+			if !retryable || exhausted {
+				return resp, body, nil
+			}
+			hint = d
+		}
+
+		delay := backoffDelay(retry, attempt, rand.Float64())
+		if hint > delay {
+			delay = capWait(c.cfg.RateLimit, hint)
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, nil, errors.WrapError(req.Context().Err(), errors.ErrHTTPRequest, "retry API error")
+		case <-time.After(delay):
+		}
+
+		if req, err = cloneForRetry(req); err != nil {
+			return nil, nil, errors.WrapError(err, errors.ErrHTTPRequest, "clone request for retry")
+		}
+	}
+}
+
+// doError wraps an error from the HTTP client. Because DecodingTransport
+// wraps http.Transport, the client reports a timeout as a canceled
+// request, so timeouts are named explicitly.
+func doError(err error) error {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return errors.WrapError(err, errors.ErrHTTPRequest, "http do: timeout")
+	}
+	return errors.WrapError(err, errors.ErrHTTPRequest, "http do")
+}
+
+// statusError describes a non-200 response. It is retryable if the status
+// is listed in RetryableStatuses, or is a 408, 429 or 5xx when none are.
+func (c *Connector) statusError(resp *http.Response, body []byte) *errors.APIError {
+	apiErr := errors.NewResponseError(resp, body)
+	if resp.Request != nil {
+		apiErr.Attempt = attemptCount(resp.Request.Context())
+	}
+
+	if c.cfg.RetryConfig != nil && len(c.cfg.RetryConfig.RetryableStatuses) > 0 {
+		for _, s := range c.cfg.RetryConfig.RetryableStatuses {
+			if s == resp.StatusCode {
+				apiErr.Retryable = true
+			}
+		}
+	} else {
+		apiErr.Retryable = resp.StatusCode == http.StatusRequestTimeout ||
+			resp.StatusCode == http.StatusTooManyRequests ||
+			resp.StatusCode >= 500
+	}
+	return apiErr
+}
+
+// annotateAPIError adds request details to an error found in a 200 body.
+func annotateAPIError(apiErr *errors.APIError, resp *http.Response, body []byte) {
+	r := errors.NewResponseError(resp, body)
+	apiErr.StatusCode = r.StatusCode
+	apiErr.Status = r.Status
+	apiErr.Method = r.Method
+	apiErr.URL = r.URL
+	apiErr.Body = r.Body
+	apiErr.RequestID = r.RequestID
+	if resp.Request != nil {
+		apiErr.Attempt = attemptCount(resp.Request.Context())
+	}
+}
+
+// rewind gives req a fresh body. Pagers clone one base request for every
+// page, so a body such as a SOAP envelope may already have been read.
+func rewind(req *http.Request) (*http.Request, error) {
+	if req.GetBody == nil {
+		return req, nil
+	}
+	return cloneForRetry(req)
+}
+
+// cloneForRetry copies req with a fresh body so it can be sent again.
+func cloneForRetry(req *http.Request) (*http.Request, error) {
+	r2 := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r2.Body = body
+	}
+	return r2, nil
+}
+
+// responseMapping returns the mapping for the configured source type.
+func (c *Connector) responseMapping() config.ResponseMapping {
+	if c.cfg.Source.Type == config.SourceTypeGraphQL && c.cfg.Source.GraphQLConfig != nil {
+		return c.cfg.Source.GraphQLConfig.ResponseMapping
+	}
+	return c.cfg.Source.ResponseMapping
+}
+
+// checkAPIError evaluates ResponseMapping.SuccessPath and ErrorPath against
+// the whole response body. When SuccessPath resolves, it decides the outcome;
+// otherwise any non-empty value at ErrorPath is treated as a failure.
+func (c *Connector) checkAPIError(resp *http.Response, body []byte) *errors.APIError {
+	m := c.responseMapping()
+	if m.ErrorPath == "" && m.SuccessPath == "" {
+		return nil
+	}
+
+	var data interface{}
+	if doc, ok := decodedDoc(resp); ok {
+		data = doc
+	} else if err := json.Unmarshal(body, &data); err != nil {
+		// Leave malformed bodies to the extractor.
+		return nil
+	}
+	return c.checkAPIErrorDoc(data)
+}
+
+// checkAPIErrorDoc is checkAPIError for an already decoded body.
+func (c *Connector) checkAPIErrorDoc(data interface{}) *errors.APIError {
+	m := c.responseMapping()
+	if m.ErrorPath == "" && m.SuccessPath == "" {
+		return nil
+	}
+
+	var message string
+	hasError := false
+	if m.ErrorPath != "" {
+		if v, ok := ExtractFieldEnhanced(data, m.ErrorPath); ok && isErrorValue(v) {
+			hasError = true
+			message = stringifyValue(v)
+		}
+	}
+
+	failed := hasError
+	if m.SuccessPath != "" {
+		if v, ok := ExtractFieldEnhanced(data, m.SuccessPath); ok {
+			failed = !isTruthy(v)
+		}
+	}
+	if !failed {
+		return nil
+	}
+
+	if message == "" {
+		message = fmt.Sprintf("response reported failure at %q", m.SuccessPath)
+	}
+	code := message
+	if m.ErrorCodePath != "" {
+		if v, ok := ExtractFieldEnhanced(data, m.ErrorCodePath); ok && v != nil {
+			code = stringifyValue(v)
+		}
+	}
+
+	return &errors.APIError{
+		Message:   message,
+		Code:      code,
+		Retryable: c.isRetryableErrorCode(code),
+	}
+}
+
+func (c *Connector) isRetryableErrorCode(code string) bool {
+	if c.cfg.RetryConfig == nil {
+		return false
+	}
+	for _, rc := range c.cfg.RetryConfig.RetryableErrorCodes {
+		if strings.EqualFold(rc, code) {
+			return true
+		}
+	}
+	return false
+}
+
+// isErrorValue reports whether v at the error path signals an error.
+func isErrorValue(v interface{}) bool {
+	switch x := v.(type) {
+	case nil:
+		return false
+	case string:
+		return x != ""
+	case bool:
+		return x
+	case []interface{}:
+		return len(x) > 0
+	case map[string]interface{}:
+		return len(x) > 0
+	default:
+		return true
+	}
+}
+
+// isTruthy interprets a success flag.
+func isTruthy(v interface{}) bool {
+	switch x := v.(type) {
+	case bool:
+		return x
+	case string:
+		switch strings.ToLower(x) {
+		case "true", "ok", "success", "1", "yes":
+			return true
+		}
+		return false
+	case float64:
+		return x != 0
+	case json.Number:
+		f, err := x.Float64()
+		return err == nil && f != 0
+	default:
+		return v != nil
+	}
+}
+
+func stringifyValue(v interface{}) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case map[string]interface{}, []interface{}:
+		b, err := json.Marshal(x)
+		if err != nil {
+			return fmt.Sprintf("%v", x)
+		}
+		return string(b)
+	default:
+		return fmt.Sprintf("%v", x)
+	}
+}
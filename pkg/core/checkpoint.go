@@ -0,0 +1,64 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/saturnines/nexus-core/pkg/checkpoint"
+	"github.com/saturnines/nexus-core/pkg/errors"
+	"github.com/saturnines/nexus-core/pkg/pagination"
+)
+
+// WithCheckpoint saves the pager position to store after every page that was
+// fully handed to the caller, and resumes from it on the next run. key
+// defaults to the pipeline name. The checkpoint is deleted once pagination
+// completes, so the run after a successful one starts from the beginning.
+func WithCheckpoint(store checkpoint.StateStore, key string) ConnectorOption {
+	return func(c *Connector) {
+		c.stateStore = store
+		c.checkpointKey = key
+	}
+}
+
+func (c *Connector) stateKey() string {
+	if c.checkpointKey != "" {
+		return c.checkpointKey
+	}
+	return c.cfg.Name
+}
+
+// checkpointer returns pager as a Checkpointer, or nil when checkpoints are off.
+func (c *Connector) checkpointer(pager pagination.Pager) (pagination.Checkpointer, error) {
+	if c.stateStore == nil {
+		return nil, nil
+	}
+	cp, ok := pager.(pagination.Checkpointer)
+	if !ok {
+		return nil, errors.WrapError(
+			fmt.Errorf("pager %T cannot be checkpointed", pager),
+			errors.ErrConfiguration,
+			"enable checkpoints",
+		)
+	}
+	return cp, nil
+}
+
+// restoreCheckpoint moves cp to the last committed position, if any.
+func (c *Connector) restoreCheckpoint(ctx context.Context, cp pagination.Checkpointer) error {
+	state, err := c.stateStore.Load(ctx, c.stateKey())
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		return nil
+	}
+	return cp.Restore(*state)
+}
+
+func (c *Connector) saveCheckpoint(ctx context.Context, cp pagination.Checkpointer) error {
+	return c.stateStore.Save(ctx, c.stateKey(), cp.Snapshot())
+}
+
+func (c *Connector) clearCheckpoint(ctx context.Context) error {
+	return c.stateStore.Delete(ctx, c.stateKey())
+}
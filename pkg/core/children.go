@@ -0,0 +1,387 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/saturnines/nexus-core/pkg/auth"
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/errors"
+)
+
+// defaultChildConcurrency bounds child requests per parent page.
+const defaultChildConcurrency = 4
+
+// parentTemplate matches {{parent.<path>}} in child source values.
+var parentTemplate = regexp.MustCompile(`\{\{\s*parent\.([^}\s]+)\s*\}\}`)
+
+// childFetcher runs one configured child for every record of a parent page.
+type childFetcher struct {
+	cfg         config.Child
+	client      *http.Client
+	authHandler auth.Handler
+	extractor   Extractor
+	parentKeys  []string // parent fields copied onto child records
+}
+
+// compileChildren validates cfg.Children and prepares their extractors and
+// auth once, so per-record work is limited to templating and fetching.
+func (c *Connector) compileChildren() ([]*childFetcher, error) {
+	seen := make(map[string]bool)
+	fetchers := make([]*childFetcher, 0, len(c.cfg.Children))
+
+	for _, child := range c.cfg.Children {
+		if child.Name == "" {
+			return nil, errors.WrapError(
+				fmt.Errorf("child name cannot be empty"),
+				errors.ErrConfiguration,
+				"compile children",
+			)
+		}
+		if seen[child.Name] {
+			return nil, errors.WrapError(
+				fmt.Errorf("duplicate child %q", child.Name),
+				errors.ErrConfiguration,
+				"compile children",
+			)
+		}
+		seen[child.Name] = true
+
+		if child.Source.Type == "" {
+			child.Source.Type = c.cfg.Source.Type
+		}
+		if child.Source.Type == config.SourceTypeGraphQL && child.Source.GraphQLConfig == nil {
+			return nil, errors.WrapError(
+				fmt.Errorf("graphql config missing"),
+				errors.ErrConfiguration,
+				fmt.Sprintf("compile child %q", child.Name),
+			)
+		}
+
+		f := &childFetcher{
+			cfg:         child,
+			client:      c.client,
+			authHandler: c.authHandler,
+		}
+
+		// Children inherit the parent's auth unless they set their own.
+		if child.Source.Auth != nil {
+			client := *c.client
+			h, err := setupAuth(child.Source.Auth, &client)
+			if err != nil {
+				return nil, err
+			}
+			f.client = &client
+			f.authHandler = h
+		}
+
+		extractor, err := newExtractorFor(child.Source, c.transforms)
+		if err != nil {
+			return nil, errors.WrapError(err, errors.ErrConfiguration, fmt.Sprintf("compile child %q", child.Name))
+		}
+		f.extractor = extractor
+		f.parentKeys = childParentKeys(child)
+
+		fetchers = append(fetchers, f)
+	}
+	return fetchers, nil
+}
+
+// childParentKeys lists ParentKeys followed by every parent field the
+// child's templates reference, without duplicates.
+func childParentKeys(child config.Child) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	add := func(k string) {
+		if !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+
+	for _, k := range child.ParentKeys {
+		add(k)
+	}
+	collect := func(s string) {
+		for _, m := range parentTemplate.FindAllStringSubmatch(s, -1) {
+			add(m[1])
+		}
+	}
+
+	src := child.Source
+	collect(src.Endpoint)
+	for _, v := range src.Headers {
+		collect(v)
+	}
+	for _, v := range src.QueryParams {
+		collect(v)
+	}
+	if g := src.GraphQLConfig; g != nil {
+		collect(g.Endpoint)
+		for _, v := range g.Headers {
+			collect(v)
+		}
+		walkStrings(g.Variables, collect)
+	}
+	return keys
+}
+
+func walkStrings(v interface{}, fn func(string)) {
+	switch x := v.(type) {
+	case string:
+		fn(x)
+	case map[string]interface{}:
+		for _, e := range x {
+			walkStrings(e, fn)
+		}
+	case []interface{}:
+		for _, e := range x {
+			walkStrings(e, fn)
+		}
+	}
+}
+
+// fanOut fetches every child for each record in page and attaches the
+// results to the record under the child's name.
+func (c *Connector) fanOut(ctx context.Context, page []map[string]interface{}) error {
+	for _, f := range c.children {
+		if err := f.fetchAll(ctx, c, page); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchAll runs f for every parent in page with at most Concurrency requests
+// in flight. Results keep the page order; the first error cancels the rest.
+func (f *childFetcher) fetchAll(ctx context.Context, parent *Connector, page []map[string]interface{}) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	limit := f.cfg.Concurrency
+	if limit <= 0 {
+		limit = defaultChildConcurrency
+	}
+
+	results := make([][]interface{}, len(page))
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+
+	for i, record := range page {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, record map[string]interface{}) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			records, err := f.fetch(ctx, parent, record)
+			if err != nil {
+				once.Do(func() {
+					firstErr = errors.WrapError(
+						err,
+						errors.ErrExtraction,
+						fmt.Sprintf("child %q for record at index %d", f.cfg.Name, i),
+					)
+					cancel()
+				})
+				return
+			}
+			results[i] = records
+		}(i, record)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return errors.WrapError(err, errors.ErrHTTPRequest, fmt.Sprintf("child %q", f.cfg.Name))
+	}
+
+	for i, record := range page {
+		record[f.cfg.Name] = results[i]
+	}
+	return nil
+}
+
+// fetch renders the child source for one parent record and extracts every
+// page of it, attaching the parent keys to each child record.
+func (f *childFetcher) fetch(ctx context.Context, parent *Connector, record map[string]interface{}) ([]interface{}, error) {
+	src, err := renderChildSource(f.cfg.Source, record)
+	if err != nil {
+		return nil, err
+	}
+	builder, err := newRequestBuilder(src, f.authHandler)
+	if err != nil {
+		return nil, err
+	}
+
+	conn := &Connector{
+		builder:     builder,
+		client:      f.client,
+		extractor:   f.extractor,
+		authHandler: f.authHandler,
+		factory:     parent.factory,
+		transforms:  parent.transforms,
+		cfg: &config.Pipeline{
+			Name:        parent.cfg.Name + "." + f.cfg.Name,
+			Source:      src,
+			Pagination:  f.cfg.Pagination,
+			RetryConfig: parent.cfg.RetryConfig,
+			Strict:      parent.cfg.Strict,
+		},
+	}
+
+	children, err := conn.Extract(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]interface{}, len(children))
+	for i, child := range children {
+		for _, key := range f.parentKeys {
+			v, _ := ExtractFieldEnhanced(record, key)
+			child[parentKeyField(key)] = v
+		}
+		out[i] = child
+	}
+	return out, nil
+}
+
+// parentKeyField names the child field a parent key is copied to,
+// e.g. "owner.login" becomes "parent_owner_login".
+func parentKeyField(key string) string {
+	return "parent_" + strings.NewReplacer(".", "_", "[", "_", "]", "").Replace(key)
+}
+
+// renderChildSource substitutes {{parent.<path>}} in a copy of src. Values
+// in endpoints are escaped segment by segment, see escapePathValue.
+func renderChildSource(src config.Source, record map[string]interface{}) (config.Source, error) {
+	var err error
+	if src.Endpoint, err = renderParentString(src.Endpoint, record, escapePathValue); err != nil {
+		return src, err
+	}
+	if src.Headers, err = renderParentMap(src.Headers, record); err != nil {
+		return src, err
+	}
+	if src.QueryParams, err = renderParentMap(src.QueryParams, record); err != nil {
+		return src, err
+	}
+
+	if src.GraphQLConfig != nil {
+		g := *src.GraphQLConfig
+		if g.Endpoint, err = renderParentString(g.Endpoint, record, escapePathValue); err != nil {
+			return src, err
+		}
+		if g.Headers, err = renderParentMap(g.Headers, record); err != nil {
+			return src, err
+		}
+		vars, err := renderParentValue(g.Variables, record)
+		if err != nil {
+			return src, err
+		}
+		g.Variables, _ = vars.(map[string]interface{})
+		src.GraphQLConfig = &g
+	}
+	return src, nil
+}
+
+func renderParentMap(m map[string]string, record map[string]interface{}) (map[string]string, error) {
+	if m == nil {
+		return nil, nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		r, err := renderParentString(v, record, nil)
+		if err != nil {
+			return nil, err
+		}
+		out[k] = r
+	}
+	return out, nil
+}
+
+// renderParentString replaces each {{parent.<path>}} in s with the value
+// at path in record, passed through escape when it is non-nil. A missing or
+// null value is an error.
+func renderParentString(s string, record map[string]interface{}, escape func(string) string) (string, error) {
+	var err error
+	out := parentTemplate.ReplaceAllStringFunc(s, func(match string) string {
+		if err != nil {
+			return match
+		}
+		path := parentTemplate.FindStringSubmatch(match)[1]
+		v, ok := ExtractFieldEnhanced(record, path)
+		if !ok || v == nil {
+			err = fmt.Errorf("parent field %q not found", path)
+			return match
+		}
+		if escape != nil {
+			return escape(formatParam(v))
+		}
+		return formatParam(v)
+	})
+	return out, err
+}
+
+// escapePathValue path-escapes each "/"-separated segment of v, so "?" or
+// "#" cannot end the path early while "owner/repo" stays two segments.
+func escapePathValue(v string) string {
+	segments := strings.Split(v, "/")
+	for i, seg := range segments {
+		segments[i] = url.PathEscape(seg)
+	}
+	return strings.Join(segments, "/")
+}
+
+// renderParentValue renders GraphQL variables. A string that is exactly one
+// template keeps the parent value's JSON type.
+func renderParentValue(v interface{}, record map[string]interface{}) (interface{}, error) {
+	switch x := v.(type) {
+	case string:
+		if m := parentTemplate.FindStringSubmatch(x); m != nil && m[0] == strings.TrimSpace(x) {
+			val, ok := ExtractFieldEnhanced(record, m[1])
+			if !ok || val == nil {
+				return nil, fmt.Errorf("parent field %q not found", m[1])
+			}
+			return val, nil
+		}
+		return renderParentString(x, record, nil)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(x))
+		for k, e := range x {
+			r, err := renderParentValue(e, record)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = r
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(x))
+		for i, e := range x {
+			r, err := renderParentValue(e, record)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = r
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
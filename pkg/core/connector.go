@@ -3,16 +3,24 @@ package core
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"iter"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/saturnines/nexus-core/pkg/auth"
+	"github.com/saturnines/nexus-core/pkg/checkpoint"
 	"github.com/saturnines/nexus-core/pkg/config"
 	"github.com/saturnines/nexus-core/pkg/errors"
+	"github.com/saturnines/nexus-core/pkg/jsonpath"
 	"github.com/saturnines/nexus-core/pkg/pagination"
+	"github.com/saturnines/nexus-core/pkg/sink"
+	"github.com/saturnines/nexus-core/pkg/transform"
 	"github.com/saturnines/nexus-core/pkg/transport/graphql"
 	"github.com/saturnines/nexus-core/pkg/transport/rest"
 )
@@ -25,6 +33,19 @@ type Connector struct {
 	cfg         *config.Pipeline
 	authHandler auth.Handler
 	factory     *pagination.Factory
+	transforms  *transform.Registry
+	children    []*childFetcher
+
+	stateStore    checkpoint.StateStore
+	checkpointKey string
+
+	watermarkStore checkpoint.WatermarkStore
+	watermarkKey   string
+
+	mu        sync.Mutex
+	watermark string
+
+	deadLetter sink.Sink
 }
 
 // ConnectorOption customises Connector.
@@ -32,7 +53,10 @@ type ConnectorOption func(*Connector)
 
 // NewConnector builds a Connector based on cfg.Source.Type.
 func NewConnector(cfg *config.Pipeline, opts ...ConnectorOption) (*Connector, error) {
-	transport := http.DefaultTransport
+	var transport http.RoundTripper = NewDecodingTransport(http.DefaultTransport)
+	if cfg.RateLimit != nil {
+		transport = NewRateLimitTransport(transport, cfg.RateLimit)
+	}
 	if cfg.RetryConfig != nil {
 		transport = NewRetryTransport(transport, cfg.RetryConfig)
 	}
@@ -41,35 +65,89 @@ func NewConnector(cfg *config.Pipeline, opts ...ConnectorOption) (*Connector, er
 		Timeout:   30 * time.Second,
 	}
 
-	var authHandler auth.Handler
-	if cfg.Source.Auth != nil {
-		h, err := auth.CreateHandler(cfg.Source.Auth)
-		if err != nil {
-			return nil, errors.WrapError(err, errors.ErrAuthentication, "auth handler")
-		}
-		if oauth2Auth, ok := h.(*auth.OAuth2Auth); ok {
-			httpClient.Transport = auth.NewOAuth2RoundTripper(httpClient.Transport, oauth2Auth)
-		} else {
-			authHandler = h
-		}
+	authHandler, err := setupAuth(cfg.Source.Auth, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateIncremental(cfg); err != nil {
+		return nil, err
+	}
+	if err := validatePaginationPaths(cfg.Pagination); err != nil {
+		return nil, err
 	}
 
-	var builder RequestBuilder
-	var extractor Extractor
+	builder, err := newRequestBuilder(cfg.Source, authHandler)
+	if err != nil {
+		return nil, err
+	}
 
-	switch cfg.Source.Type {
+	conn := &Connector{
+		builder:     builder,
+		client:      httpClient,
+		authHandler: authHandler,
+		cfg:         cfg,
+		factory:     pagination.DefaultFactory,
+		transforms:  transform.DefaultRegistry,
+	}
+	for _, o := range opts {
+		o(conn)
+	}
+
+	if err := conn.validateErrorPolicy(); err != nil {
+		return nil, err
+	}
+
+	// Built after options so an injected transform registry is used
+	// when compiling field transforms.
+	extractor, err := conn.newExtractor()
+	if err != nil {
+		return nil, err
+	}
+	conn.extractor = extractor
+
+	if conn.children, err = conn.compileChildren(); err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+// setupAuth creates the handler for a, wrapping client's transport instead
+// for OAuth2 so tokens refresh transparently. It returns a nil handler when
+// no per-request auth is needed.
+func setupAuth(a *config.Auth, client *http.Client) (auth.Handler, error) {
+	if a == nil {
+		return nil, nil
+	}
+	h, err := auth.CreateHandler(a)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrAuthentication, "auth handler")
+	}
+	if oauth2Auth, ok := h.(*auth.OAuth2Auth); ok {
+		client.Transport = auth.NewOAuth2RoundTripper(client.Transport, oauth2Auth)
+		return nil, nil
+	}
+	return h, nil
+}
+
+// newRequestBuilder builds the RequestBuilder for src.Type.
+func newRequestBuilder(src config.Source, authHandler auth.Handler) (RequestBuilder, error) {
+	switch src.Type {
 	case config.SourceTypeREST:
-		builder = rest.NewBuilder(
-			cfg.Source.Endpoint,
-			cfg.Source.Method,
-			cfg.Source.Headers,
-			cfg.Source.QueryParams,
+		b := rest.NewBuilder(
+			src.Endpoint,
+			src.Method,
+			src.Headers,
+			src.QueryParams,
 			authHandler,
 		)
-		extractor = NewRestExtractor(cfg.Source.ResponseMapping)
+		if s := src.SOAP; s != nil {
+			b.SOAP = &rest.SOAP{Version: string(s.Version), Action: s.Action, Header: s.Header, Body: s.Body}
+		}
+		return b, nil
 
 	case config.SourceTypeGraphQL:
-		g := cfg.Source.GraphQLConfig
+		g := src.GraphQLConfig
 		if g == nil {
 			return nil, errors.WrapError(
 				fmt.Errorf("graphql config missing"),
@@ -77,160 +155,424 @@ func NewConnector(cfg *config.Pipeline, opts ...ConnectorOption) (*Connector, er
 				"create GraphQL connector",
 			)
 		}
-		builder = graphql.NewBuilder(
+		return graphql.NewBuilder(
 			g.Endpoint,
 			g.Query,
 			g.Variables,
 			g.Headers,
 			authHandler,
-		)
-		extractor = NewGraphQLExtractor(g)
+		), nil
 
 	default:
 		return nil, errors.WrapError(
-			fmt.Errorf("unsupported source type: %s", cfg.Source.Type),
+			fmt.Errorf("unsupported source type: %s", src.Type),
 			errors.ErrConfiguration,
 			"create connector",
 		)
 	}
+}
 
-	conn := &Connector{
-		builder:     builder,
-		client:      httpClient,
-		extractor:   extractor,
-		authHandler: authHandler,
-		cfg:         cfg,
-		factory:     pagination.DefaultFactory,
+// newExtractor builds the Extractor for cfg.Source.Type.
+func (c *Connector) newExtractor() (Extractor, error) {
+	return newExtractorFor(c.cfg.Source, c.transforms)
+}
+
+func newExtractorFor(src config.Source, registry *transform.Registry) (Extractor, error) {
+	if src.Type == config.SourceTypeGraphQL {
+		return NewGraphQLExtractor(src.GraphQLConfig, registry)
 	}
-	for _, o := range opts {
-		o(conn)
+	// SOAP sources read XML unless told otherwise.
+	format := src.ResponseMapping.ResponseFormat
+	if format == config.ResponseFormatXML || format == "" && src.SOAP != nil {
+		return NewXMLExtractor(src.ResponseMapping, registry)
 	}
-	return conn, nil
+	return NewRestExtractor(src.ResponseMapping, registry)
 }
 
-// Extract either makes a single request or paginates
+// Extract either makes a single request or paginates, collecting every
+// record in memory. Use Stream for large datasets. If the run fails after
+// some records were extracted, those records are returned together with an
+// *errors.PartialError wrapping the cause.
 func (c *Connector) Extract(ctx context.Context) ([]map[string]interface{}, error) {
+	var all []map[string]interface{}
+	for record, err := range c.Stream(ctx) {
+		if err != nil {
+			return all, err
+		}
+		all = append(all, record)
+	}
+	return all, nil
+}
+
+// Stream yields records page by page as pagination advances, so only one
+// page is held in memory at a time. A non-nil error is always the last value
+// yielded. Breaking out of the loop stops pagination. For incremental
+// pipelines the new watermark is committed only once every record was yielded.
+func (c *Connector) Stream(ctx context.Context) iter.Seq2[map[string]interface{}, error] {
+	return func(yield func(map[string]interface{}, error) bool) {
+		builder, wm, err := c.beginIncremental(ctx)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		budget := c.newErrorBudget()
+		defer budget.close()
+
+		completed := true
+		var childErr error
+		pages, records := 0, 0
+		err = c.eachPage(ctx, builder, budget, func(page []map[string]interface{}, partial bool) bool {
+			if len(c.children) > 0 {
+				if childErr = c.fanOut(ctx, page); childErr != nil {
+					return false
+				}
+			}
+			for _, record := range page {
+				wm.observe(record)
+				records++
+				if !yield(record, nil) {
+					completed = false
+					return false
+				}
+			}
+			if !partial {
+				pages++
+			}
+			return true
+		})
+		if err == nil {
+			err = childErr
+		}
+		if err == nil && completed {
+			err = budget.finish()
+		}
+		if err != nil {
+			if records > 0 {
+				err = &errors.PartialError{Records: records, Pages: pages, Err: err}
+			}
+			yield(nil, err)
+			return
+		}
+		if completed {
+			if err := c.commitIncremental(ctx, wm); err != nil {
+				yield(nil, err)
+			}
+		}
+	}
+}
+
+// eachPage fetches and maps the pages requested through builder in order,
+// handing each one to fn.
+// It stops early when fn returns false. Items rejected while mapping are
+// reported to budget before the page is handed on. When streaming, a page
+// is handed on in several batches and partial is true for all but the last.
+func (c *Connector) eachPage(ctx context.Context, builder RequestBuilder, budget *errorBudget, fn func(records []map[string]interface{}, partial bool) bool) error {
+	pageNo := 0
+	handle := func(page extractedPage) (bool, error) {
+		if err := budget.record(ctx, pageNo, page); err != nil {
+			return false, err
+		}
+		return fn(page.records, page.partial), nil
+	}
+	deliver := func(page extractedPage) (bool, error) {
+		pageNo++
+		return handle(page)
+	}
+	stream := c.streaming()
+
 	if c.cfg.Pagination == nil {
-		req, err := c.builder.Build(ctx)
+		req, err := builder.Build(ctx)
 		if err != nil {
-			return nil, errors.WrapError(err, errors.ErrHTTPRequest, "build request")
+			return errors.WrapError(err, errors.ErrHTTPRequest, "build request")
 		}
 		if c.authHandler != nil {
 			if err := c.authHandler.ApplyAuth(req); err != nil {
-				return nil, c.handleAuthError(err)
+				return c.handleAuthError(err)
 			}
 		}
-		resp, err := c.client.Do(req)
+		send := c.fetch
+		if stream {
+			send = c.fetchStream
+		}
+		resp, body, err := send(req)
 		if err != nil {
-			return nil, errors.WrapError(err, errors.ErrHTTPRequest, "http do")
+			return err
 		}
-		defer resp.Body.Close()
 
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.WrapError(
-				fmt.Errorf("API returned status %d", resp.StatusCode),
+			return errors.WrapError(
+				c.statusError(resp, body),
 				errors.ErrHTTPResponse,
 				"unexpected status code",
 			)
 		}
 
-		body, err := io.ReadAll(resp.Body)
+		if stream {
+			pageNo++
+			_, _, err := c.streamRetry(req, resp, handle)
+			return err
+		}
+		page, err := c.extractFromBytes(resp, body)
 		if err != nil {
-			return nil, errors.WrapError(err, errors.ErrHTTPResponse, "read response body")
+			return err
 		}
+		_, err = deliver(page)
+		return err
+	}
 
-		return c.extractFromBytes(body)
+	pager, err := c.createPager(ctx, builder)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrPagination, "create pager")
 	}
 
-	pager, err := c.createPager(ctx)
+	cp, err := c.checkpointer(pager)
 	if err != nil {
-		return nil, errors.WrapError(err, errors.ErrPagination, "create pager")
+		return err
+	}
+	if cp != nil {
+		if err := c.restoreCheckpoint(ctx, cp); err != nil {
+			return err
+		}
 	}
 
-	var all []map[string]interface{}
 	for {
+		if err := ctx.Err(); err != nil {
+			return errors.WrapError(err, errors.ErrPagination, "context done")
+		}
+
+		if t, ok := pager.(pagination.Throttler); ok {
+			if err := sleepCtx(ctx, capWait(c.cfg.RateLimit, t.Delay())); err != nil {
+				return errors.WrapError(err, errors.ErrPagination, "context done")
+			}
+		}
+
 		req, err := pager.NextRequest()
 		if err != nil {
-			return nil, errors.WrapError(err, errors.ErrPagination, "next request")
+			return errors.WrapError(err, errors.ErrPagination, "next request")
 		}
 		if req == nil {
-			break
-		}
-		if c.authHandler != nil {
-			if err := c.authHandler.ApplyAuth(req); err != nil {
-				return nil, c.handleAuthError(err)
+			if cp != nil {
+				return c.clearCheckpoint(ctx)
 			}
+			return nil
 		}
 
-		resp, err := c.client.Do(req)
+		// API errors in the body are caught here, before the pager advances.
+		resp, bytes, err := c.fetchPage(req, stream)
 		if err != nil {
-			return nil, errors.WrapError(err, errors.ErrHTTPRequest, "http do")
+			return err
 		}
 
-		bytes, err := readAndBuffer(resp)
-		if err != nil {
-			return nil, err
+		if stream {
+			// The pager reads the skeleton decoded along with the items.
+			pageNo++
+			decoded, stopped, err := c.streamRetry(req, resp, handle)
+			if err != nil || stopped {
+				return err
+			}
+			if err := pager.UpdateState(decoded); err != nil {
+				return errors.WrapError(err, errors.ErrPagination, "update state")
+			}
+		} else {
+			page, err := c.extractFromBytes(resp, bytes)
+			if err != nil {
+				return err
+			}
+			if err := pager.UpdateState(c.pagerResponse(resp, bytes, page)); err != nil {
+				return errors.WrapError(err, errors.ErrPagination, "update state")
+			}
+			if more, err := deliver(page); err != nil || !more {
+				return err
+			}
 		}
 
-		// catch non200 and non 429 here
-		if resp.StatusCode != http.StatusOK {
-			// map 429 → ErrPagination, everything else → ErrHTTPResponse
-			errType := errors.ErrHTTPResponse
-			if resp.StatusCode == http.StatusTooManyRequests {
-				errType = errors.ErrPagination
+		// Only commit pages the caller consumed in full.
+		if cp != nil {
+			if err := c.saveCheckpoint(ctx, cp); err != nil {
+				return err
 			}
-
-			return nil, errors.WrapError(
-				fmt.Errorf("API returned status %d", resp.StatusCode),
-				errType,
-				"unexpected status code",
-			)
 		}
 
-		buffered := c.createBufferedResponse(resp, bytes)
-		if err := pager.UpdateState(buffered); err != nil {
-			return nil, errors.WrapError(err, errors.ErrPagination, "update state")
+		if c.cfg.Pagination.Concurrency > 1 {
+			if planner, ok := pager.(pagination.Planner); ok {
+				planned, err := planner.Plan()
+				if err != nil {
+					return errors.WrapError(err, errors.ErrPagination, "plan pages")
+				}
+				if len(planned) > 0 {
+					if stopped, err := c.fetchPlanned(ctx, planned, deliver); err != nil || stopped {
+						return err
+					}
+				}
+			}
 		}
+	}
+}
 
-		page, err := c.extractFromBytes(bytes)
-		if err != nil {
-			return nil, err
+// fetchPage applies auth, sends a paginated request and returns the buffered
+// body of a 200 response. With stream set the body is left unread instead.
+func (c *Connector) fetchPage(req *http.Request, stream bool) (*http.Response, []byte, error) {
+	if c.authHandler != nil {
+		if err := c.authHandler.ApplyAuth(req); err != nil {
+			return nil, nil, c.handleAuthError(err)
 		}
-		all = append(all, page...)
 	}
 
-	return all, nil
+	send := c.fetch
+	if stream {
+		send = c.fetchStream
+	}
+	resp, body, err := send(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Every non-200 status, 429 included, is an ErrHTTPResponse; statusError
+	// marks 429 retryable so callers can back off and try again.
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, errors.WrapError(
+			c.statusError(resp, body),
+			errors.ErrHTTPResponse,
+			"unexpected status code",
+		)
+	}
+	return resp, body, nil
 }
 
-func (c *Connector) extractFromBytes(b []byte) ([]map[string]interface{}, error) {
+// extractFromBytes maps every item in b, the body of resp. Items that fail
+// are collected in the page's rejected list; unless the error policy skips
+// bad items, mapping stops at the first one.
+func (c *Connector) extractFromBytes(resp *http.Response, b []byte) (extractedPage, error) {
+	var page extractedPage
 	if c.cfg.Source.Type == config.SourceTypeGraphQL {
 		if err := errors.CheckGraphQLErrors(b); err != nil {
-			return nil, err
+			return page, err
 		}
 	}
 
-	items, err := c.extractor.Items(b)
+	if e, ok := c.extractor.(*RestExtractor); ok && e.lineDelimited() {
+		items, lines := e.decodeLines(b)
+		page, err := c.extractItems(items, 0, lines)
+		page.decoded = items
+		return page, err
+	}
+
+	var items []interface{}
+	var err error
+	e, isXML := c.extractor.(*XMLExtractor)
+	if doc, ok := decodedDoc(resp); ok && isXML {
+		items, err = e.items(doc)
+	} else {
+		items, err = c.extractor.Items(b)
+	}
 	if err != nil {
-		return nil, err
+		return page, err
 	}
+	return c.extractItems(items, 0, nil)
+}
 
-	var results []map[string]interface{}
-	for i, item := range items {
-		m, ok := item.(map[string]interface{})
-		if !ok {
-			return nil, errors.WrapError(
-				fmt.Errorf("item at index %d is not a map: %v", i, item),
+// extractItems maps items, which start at index offset of their page. For
+// line-delimited bodies lines holds each item's line number, which errors
+// report instead of the index.
+func (c *Connector) extractItems(items []interface{}, offset int, lines []int) (extractedPage, error) {
+	var page extractedPage
+	page.items = len(items)
+
+	if err := c.checkStrict(items); err != nil {
+		return page, err
+	}
+
+	skip := c.skipBadItems()
+	for j, item := range items {
+		r := rejectedItem{index: offset + j, raw: item}
+		if lines != nil {
+			r.line = lines[j]
+		}
+		switch it := item.(type) {
+		case map[string]interface{}:
+			mapped, err := c.mapItem(it)
+			if err == nil {
+				page.records = append(page.records, mapped...)
+				continue
+			}
+			r.reason = errors.WrapError(err, errors.ErrExtraction, "map item at "+r.position())
+		case badLine:
+			r.raw, r.reason = it.text, it.error()
+		default:
+			r.reason = errors.WrapError(
+				fmt.Errorf("item at %s is not a map: %v", r.position(), item),
 				errors.ErrExtraction,
 				"validate item type",
 			)
 		}
-		mapped, err := c.extractor.Map(m)
-		if err != nil {
-			return nil, errors.WrapError(err, errors.ErrExtraction, fmt.Sprintf("map item at index %d", i))
+		page.rejected = append(page.rejected, r)
+		if !skip {
+			return page, nil
 		}
-		results = append(results, mapped)
 	}
-	return results, nil
+	return page, nil
+}
+
+// recordsExtractor is implemented by extractors that can map one item to
+// several records, e.g. when exploding a nested array.
+type recordsExtractor interface {
+	mapRecords(item interface{}) ([]map[string]interface{}, error)
+}
+
+// mapItem maps item to its records.
+func (c *Connector) mapItem(item interface{}) ([]map[string]interface{}, error) {
+	if re, ok := c.extractor.(recordsExtractor); ok {
+		return re.mapRecords(item)
+	}
+	mapped, err := c.extractor.Map(item)
+	if err != nil {
+		return nil, err
+	}
+	return []map[string]interface{}{mapped}, nil
+}
+
+// strictExtractor is implemented by extractors that can report field paths
+// a page never resolved.
+type strictExtractor interface {
+	unresolved(items []interface{}) []fieldMapping
+}
+
+// checkStrict fails a non-empty page in strict mode when some field's path
+// resolves on none of its items, which usually means the path is wrong.
+func (c *Connector) checkStrict(items []interface{}) error {
+	se, ok := c.extractor.(strictExtractor)
+	if !c.cfg.Strict || !ok || len(items) == 0 {
+		return nil
+	}
+	missing := se.unresolved(items)
+	if len(missing) == 0 {
+		return nil
+	}
+	names := make([]string, len(missing))
+	for i, f := range missing {
+		names[i] = fmt.Sprintf("%s (%s)", f.Name, f.Path)
+	}
+	return errors.WrapError(
+		fmt.Errorf("field paths resolved on none of %d items: %s", len(items), strings.Join(names, ", ")),
+		errors.ErrExtraction,
+		"strict field check",
+	)
+}
+
+// sleepCtx waits for d or until ctx is done.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
 
 func readAndBuffer(resp *http.Response) ([]byte, error) {
@@ -243,7 +585,7 @@ func readAndBuffer(resp *http.Response) ([]byte, error) {
 	return b, nil
 }
 
-func (c *Connector) createPager(ctx context.Context) (pagination.Pager, error) {
+func (c *Connector) createPager(ctx context.Context, builder RequestBuilder) (pagination.Pager, error) {
 	if c.cfg.Pagination == nil {
 		return nil, nil
 	}
@@ -252,19 +594,25 @@ func (c *Connector) createPager(ctx context.Context) (pagination.Pager, error) {
 	if c.cfg.Source.Type == config.SourceTypeGraphQL &&
 		c.cfg.Pagination.Type == config.PaginationTypeCursor {
 
-		gqlBuilder, ok := c.builder.(*graphql.Builder)
+		gqlBuilder, ok := builder.(*graphql.Builder)
 		if !ok {
 			return nil, fmt.Errorf("expected GraphQL builder for GraphQL source")
 		}
 
-		// Parse the paths as is
-		cursorPath := strings.Split(c.cfg.Pagination.CursorPath, ".")
-		hasNextPath := strings.Split(c.cfg.Pagination.HasMorePath, ".")
+		// Paths are resolved like root_path, relative to "data"
+		cursorPath, err := jsonpath.Compile(graphQLPath(c.cfg.Pagination.CursorPath))
+		if err != nil {
+			return nil, errors.WrapError(err, errors.ErrConfiguration, "pagination cursor_path")
+		}
+		hasNextPath, err := jsonpath.Compile(graphQLPath(c.cfg.Pagination.HasMorePath))
+		if err != nil {
+			return nil, errors.WrapError(err, errors.ErrConfiguration, "pagination has_more_path")
+		}
 
 		// Create GraphQL client wrapper
 		gqlClient := graphql.NewClient(c.client)
 
-		return graphql.NewPager(
+		return graphql.NewPagerWithPaths(
 			ctx,
 			gqlBuilder,
 			gqlClient,
@@ -275,7 +623,7 @@ func (c *Connector) createPager(ctx context.Context) (pagination.Pager, error) {
 	}
 
 	// For REST sources default use the existing factory approach
-	req, err := c.builder.Build(ctx)
+	req, err := builder.Build(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -284,6 +632,30 @@ func (c *Connector) createPager(ctx context.Context) (pagination.Pager, error) {
 	return c.factory.CreatePager(string(c.cfg.Pagination.Type), c.client, req, opts)
 }
 
+// validatePaginationPaths compiles every configured pagination path so
+// syntax errors surface when the connector is created.
+func validatePaginationPaths(p *config.Pagination) error {
+	if p == nil {
+		return nil
+	}
+	paths := []struct{ name, path string }{
+		{"cursor_path", p.CursorPath},
+		{"has_more_path", p.HasMorePath},
+		{"total_pages_path", p.TotalPagesPath},
+		{"total_count_path", p.TotalCountPath},
+		{"next_link_path", p.NextLinkPath},
+	}
+	for _, f := range paths {
+		if f.path == "" {
+			continue
+		}
+		if _, err := jsonpath.Compile(f.path); err != nil {
+			return errors.WrapError(err, errors.ErrConfiguration, fmt.Sprintf("pagination %s", f.name))
+		}
+	}
+	return nil
+}
+
 func (c *Connector) paginationConfigToPagerOptions() map[string]interface{} {
 	p := c.cfg.Pagination
 	opts := make(map[string]interface{})
@@ -308,6 +680,9 @@ func (c *Connector) paginationConfigToPagerOptions() map[string]interface{} {
 	case config.PaginationTypeCursor:
 		opts["cursorParam"] = p.CursorParam
 		opts["nextPath"] = p.CursorPath
+
+	case config.PaginationTypeLink:
+		opts["nextLinkPath"] = p.NextLinkPath
 	}
 
 	return opts
@@ -321,7 +696,133 @@ func (c *Connector) handleAuthError(err error) error {
 	return errors.WrapError(err, errors.ErrAuthentication, "authentication failed")
 }
 
+// pagerResponse returns resp with body b, extracted as page, for the pager.
+// Bodies that are not a JSON document are handed over decoded, so
+// pagination paths work on them as they do on JSON.
+func (c *Connector) pagerResponse(resp *http.Response, b []byte, page extractedPage) *http.Response {
+	switch e := c.extractor.(type) {
+	case *RestExtractor:
+		if e.lineDelimited() {
+			return c.createResponse(resp, pagination.NewDecodedBody(e.lineSkeleton(b, page.decoded)))
+		}
+	case *XMLExtractor:
+		if doc, ok := decodedDoc(resp); ok {
+			return c.createResponse(resp, pagination.NewDecodedBody(c.typedPagerValues(doc)))
+		}
+	}
+	return c.createBufferedResponse(resp, b)
+}
+
+// decodedDoc returns the document fetch decoded an XML body into.
+func decodedDoc(resp *http.Response) (interface{}, bool) {
+	db, ok := resp.Body.(*pagination.DecodedBody)
+	if !ok {
+		return nil, false
+	}
+	return db.Doc, true
+}
+
+// typedPagerValues returns doc with the text at the has_more, total_pages
+// and total_count paths parsed, so pagers read XML flags and counts as they
+// read JSON ones. Only the maps along those paths are copied.
+func (c *Connector) typedPagerValues(doc interface{}) interface{} {
+	p := c.cfg.Pagination
+	if p == nil {
+		return doc
+	}
+	paths := []struct {
+		path  string
+		parse func(string) (interface{}, bool)
+	}{
+		{p.HasMorePath, parseBoolText},
+		{p.TotalPagesPath, parseIntText},
+		{p.TotalCountPath, parseIntText},
+	}
+	for _, pp := range paths {
+		if pp.path == "" {
+			continue
+		}
+		compiled, err := jsonpath.Compile(pp.path)
+		if err != nil {
+			continue
+		}
+		if names, ok := compiled.Names(); ok {
+			doc = replaceText(doc, names, pp.parse)
+		}
+	}
+	return doc
+}
+
+// replaceText returns v with the string at the member names replaced by
+// what parse makes of it. Maps along the way are copied; v is unchanged.
+func replaceText(v interface{}, names []string, parse func(string) (interface{}, bool)) interface{} {
+	if len(names) == 0 {
+		if s, ok := v.(string); ok {
+			if typed, ok := parse(s); ok {
+				return typed
+			}
+		}
+		return v
+	}
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+	child, ok := obj[names[0]]
+	if !ok {
+		return v
+	}
+	out := make(map[string]interface{}, len(obj))
+	for k, val := range obj {
+		out[k] = val
+	}
+	out[names[0]] = replaceText(child, names[1:], parse)
+	return out
+}
+
+// typedText returns a copy of v in which "true" and "false" become bools
+// and integers become json.Number, which keeps their digits. csv rows carry
+// every value as text.
+func typedText(v interface{}) interface{} {
+	switch x := v.(type) {
+	case string:
+		if n, ok := parseIntText(x); ok {
+			return n
+		}
+		if s := strings.TrimSpace(x); strings.EqualFold(s, "true") || strings.EqualFold(s, "false") {
+			return strings.EqualFold(s, "true")
+		}
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(x))
+		for k, val := range x {
+			out[k] = typedText(val)
+		}
+		return out
+	}
+	return v
+}
+
+// parseBoolText parses a flag such as "true" or "1".
+func parseBoolText(s string) (interface{}, bool) {
+	b, err := strconv.ParseBool(strings.TrimSpace(s))
+	return b, err == nil
+}
+
+// parseIntText parses an integer into a json.Number.
+func parseIntText(s string) (interface{}, bool) {
+	s = strings.TrimSpace(s)
+	if _, err := strconv.ParseInt(s, 10, 64); err != nil {
+		return nil, false
+	}
+	return json.Number(s), true
+}
+
 func (c *Connector) createBufferedResponse(orig *http.Response, b []byte) *http.Response {
+	return c.createResponse(orig, io.NopCloser(bytes.NewReader(b)))
+}
+
+// createResponse copies orig with body in place of its own.
+func (c *Connector) createResponse(orig *http.Response, body io.ReadCloser) *http.Response {
 	return &http.Response{
 		Status:           orig.Status,
 		StatusCode:       orig.StatusCode,
@@ -329,7 +830,7 @@ func (c *Connector) createBufferedResponse(orig *http.Response, b []byte) *http.
 		ProtoMajor:       orig.ProtoMajor,
 		ProtoMinor:       orig.ProtoMinor,
 		Header:           orig.Header,
-		Body:             io.NopCloser(bytes.NewReader(b)),
+		Body:             body,
 		ContentLength:    orig.ContentLength,
 		TransferEncoding: orig.TransferEncoding,
 		Close:            orig.Close,
@@ -347,6 +848,14 @@ func WithTimeout(timeout time.Duration) ConnectorOption {
 	}
 }
 
+// WithTransformRegistry compiles field transforms against r instead of
+// transform.DefaultRegistry.
+func WithTransformRegistry(r *transform.Registry) ConnectorOption {
+	return func(c *Connector) {
+		c.transforms = r
+	}
+}
+
 // WithCustomHTTPClient replaces the HTTP client entirely.
 func WithCustomHTTPClient(client *http.Client) ConnectorOption {
 	return func(c *Connector) {
@@ -0,0 +1,328 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/errors"
+)
+
+// csvFormat holds the resolved options for csv and tsv bodies.
+type csvFormat struct {
+	header  config.CSVHeader
+	columns []string
+	comma   rune
+	quote   rune // 0 when quoting is disabled
+	comment rune // 0 when unset
+	skip    int
+	nulls   map[string]bool
+	known   map[string]bool // column names selected by field paths
+}
+
+// newCSVFormat resolves the csv options of m. fields are the compiled field
+// mappings; the columns their paths name help to recognise a header row.
+func newCSVFormat(m config.ResponseMapping, fields []fieldMapping) (*csvFormat, error) {
+	var opts config.CSVFormat
+	if m.CSV != nil {
+		opts = *m.CSV
+	}
+
+	f := &csvFormat{
+		header:  opts.Header,
+		columns: opts.Columns,
+		comma:   ',',
+		quote:   '"',
+		skip:    opts.SkipRows,
+		nulls:   make(map[string]bool, len(opts.NullValues)),
+		known:   make(map[string]bool),
+	}
+	if f.header == "" {
+		f.header = config.CSVHeaderFirstRow
+		if len(opts.Columns) > 0 {
+			f.header = config.CSVHeaderAuto
+		}
+	}
+	// TSV exports rarely quote, and their text often holds bare quotes.
+	if m.ResponseFormat == config.ResponseFormatTSV {
+		f.comma, f.quote = '\t', 0
+	}
+
+	chars := []struct {
+		name  string
+		value string
+		dst   *rune
+	}{
+		{"delimiter", opts.Delimiter, &f.comma},
+		{"quote", opts.Quote, &f.quote},
+		{"comment", opts.Comment, &f.comment},
+	}
+	for _, ch := range chars {
+		switch {
+		case ch.value == "":
+		case ch.name == "quote" && ch.value == "none":
+			*ch.dst = 0
+		case utf8.RuneCountInString(ch.value) == 1 && ch.value != "\n" && ch.value != "\r":
+			*ch.dst, _ = utf8.DecodeRuneInString(ch.value)
+		default:
+			return nil, errors.WrapError(
+				fmt.Errorf("csv %s %q must be a single character", ch.name, ch.value),
+				errors.ErrConfiguration,
+				"csv format",
+			)
+		}
+	}
+
+	if f.comma == f.quote || f.comma == f.comment {
+		return nil, errors.WrapError(
+			fmt.Errorf("csv delimiter %q must differ from quote and comment", f.comma),
+			errors.ErrConfiguration,
+			"csv format",
+		)
+	}
+
+	for _, v := range opts.NullValues {
+		f.nulls[v] = true
+	}
+	for _, fm := range fields {
+		if fm.path == nil {
+			continue
+		}
+		if names, ok := fm.path.Names(); ok && len(names) > 0 {
+			f.known[names[0]] = true
+		}
+	}
+	for _, c := range opts.Columns {
+		f.known[c] = true
+	}
+	return f, nil
+}
+
+// read decodes the rows of a delimited body into items keyed by column
+// name, handing each to emit with the line it starts on. Malformed rows and
+// rows with the wrong number of cells are emitted as badLine items.
+func (f *csvFormat) read(r io.Reader, emit func(item interface{}, line int) error) error {
+	br := bufio.NewReader(r)
+	if bom, _ := br.Peek(3); string(bom) == "\ufeff" {
+		br.Discard(3)
+	}
+	for i := 0; i < f.skip; i++ {
+		if _, err := br.ReadString('\n'); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+
+	next := f.quotedRows(br)
+	if f.quote == 0 {
+		next = f.plainRows(br)
+	}
+
+	var names []string
+	for {
+		row, err := next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if row.err != nil {
+			if err := emit(badLine{line: row.line, text: row.text, err: row.err}, row.line); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if names == nil {
+			if f.isHeader(row.cells) {
+				names = make([]string, len(row.cells))
+				for i, c := range row.cells {
+					names[i] = strings.TrimSpace(c)
+				}
+				continue
+			}
+			names = f.columnNames(len(row.cells))
+		}
+
+		var item interface{}
+		if len(row.cells) != len(names) {
+			item = badLine{line: row.line, text: row.text, err: fmt.Errorf("row has %d cells, expected %d", len(row.cells), len(names))}
+		} else {
+			m := make(map[string]interface{}, len(names))
+			for i, name := range names {
+				if c := row.cells[i]; !f.nulls[c] {
+					m[name] = c
+				} else {
+					m[name] = nil
+				}
+			}
+			item = m
+		}
+		if err := emit(item, row.line); err != nil {
+			return err
+		}
+	}
+}
+
+// isHeader reports whether the first row names the columns. In auto mode it
+// does when it holds a column that a field path or Columns names.
+func (f *csvFormat) isHeader(cells []string) bool {
+	switch f.header {
+	case config.CSVHeaderFirstRow:
+		return true
+	case config.CSVHeaderNone:
+		return false
+	}
+
+	for _, c := range cells {
+		if f.known[strings.TrimSpace(c)] {
+			return true
+		}
+	}
+	return false
+}
+
+// columnNames names the columns of a body without a header: Columns when
+// configured, otherwise column_1, column_2, ...
+func (f *csvFormat) columnNames(n int) []string {
+	if len(f.columns) > 0 {
+		return f.columns
+	}
+	names := make([]string, n)
+	for i := range names {
+		names[i] = fmt.Sprintf("column_%d", i+1)
+	}
+	return names
+}
+
+// csvRow is one row of a delimited body. err is set when the row is
+// malformed; text holds its raw lines either way.
+type csvRow struct {
+	cells []string
+	line  int
+	text  string
+	err   error
+}
+
+// quotedRows returns a function reading the rows of br with encoding/csv.
+// Quoted cells may span lines, and a doubled quote inside one stands for
+// the quote itself. Blank lines and comment lines are skipped; io.EOF marks
+// the end of the body.
+func (f *csvFormat) quotedRows(br *bufio.Reader) func() (csvRow, error) {
+	// encoding/csv only knows '"' as the quote, so a custom quote character
+	// and '"' trade places while parsing.
+	swap := func(r rune) rune { return r }
+	var in io.Reader = br
+	if f.quote != '"' {
+		swap = func(r rune) rune {
+			switch r {
+			case f.quote:
+				return '"'
+			case '"':
+				return f.quote
+			}
+			return r
+		}
+		in = transform.NewReader(br, runes.Map(swap))
+	}
+
+	text := &rowText{r: in, line: 1}
+	cr := csv.NewReader(text)
+	cr.Comma = swap(f.comma)
+	cr.Comment = swap(f.comment)
+	cr.FieldsPerRecord = -1
+
+	return func() (csvRow, error) {
+		for {
+			cells, err := cr.Read()
+			if err == io.EOF {
+				return csvRow{}, err
+			}
+			var line int
+			if perr, ok := err.(*csv.ParseError); ok {
+				line = perr.StartLine
+				err = perr.Err
+			} else if err != nil {
+				return csvRow{}, err
+			} else {
+				line, _ = cr.FieldPos(0)
+			}
+			raw := strings.Map(swap, text.take(line, cr.InputOffset()))
+
+			if err == nil && len(cells) == 1 && strings.TrimSpace(cells[0]) == "" {
+				continue
+			}
+			for i, c := range cells {
+				cells[i] = strings.Map(swap, c)
+			}
+			return csvRow{cells: cells, line: f.skip + line, text: raw, err: err}, nil
+		}
+	}
+}
+
+// plainRows returns a function reading the rows of a body without quoting:
+// each line is split at the delimiter. Blank lines and comment lines are
+// skipped; io.EOF marks the end of the body.
+func (f *csvFormat) plainRows(br *bufio.Reader) func() (csvRow, error) {
+	line := f.skip
+	return func() (csvRow, error) {
+		for {
+			s, err := br.ReadString('\n')
+			if err != nil && (err != io.EOF || s == "") {
+				return csvRow{}, err
+			}
+			line++
+			s = strings.TrimSuffix(strings.TrimSuffix(s, "\n"), "\r")
+			if strings.TrimSpace(s) == "" {
+				continue
+			}
+			if r, _ := utf8.DecodeRuneInString(s); f.comment != 0 && r == f.comment {
+				continue
+			}
+			return csvRow{cells: strings.Split(s, string(f.comma)), line: line, text: s}, nil
+		}
+	}
+}
+
+// rowText keeps the input a csv.Reader has consumed but not yet attributed
+// to a row, so rows can report their raw text.
+type rowText struct {
+	r    io.Reader
+	buf  []byte
+	base int64 // input offset of buf[0]
+	line int   // line of buf[0]
+}
+
+func (t *rowText) Read(p []byte) (int, error) {
+	n, err := t.r.Read(p)
+	t.buf = append(t.buf, p[:n]...)
+	return n, err
+}
+
+// take returns the text of the row that starts on line and ends at input
+// offset end, without the blank and comment lines skipped before it.
+func (t *rowText) take(line int, end int64) string {
+	chunk := t.buf[:end-t.base]
+	t.buf = t.buf[end-t.base:]
+	t.base = end
+
+	for ; t.line < line; t.line++ {
+		i := bytes.IndexByte(chunk, '\n')
+		if i < 0 {
+			break
+		}
+		chunk = chunk[i+1:]
+	}
+	t.line += bytes.Count(chunk, []byte{'\n'})
+	return strings.TrimRight(string(chunk), "\r\n")
+}
@@ -0,0 +1,285 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/transform"
+
+	"github.com/saturnines/nexus-core/pkg/errors"
+)
+
+// acceptEncoding lists the content codings DecodingTransport can decode.
+const acceptEncoding = "gzip, deflate, br, zstd"
+
+// DefaultMaxDecodedSize caps how large a compressed body may grow when
+// DecodingTransport.MaxSize is not set.
+const DefaultMaxDecodedSize = 1 << 30
+
+// DecodingTransport hands extractors plain UTF-8 bodies. It decodes gzip,
+// deflate, brotli and zstd Content-Encodings, unwraps bodies that are
+// gzip files themselves (such as application/gzip exports), and converts
+// a non-UTF-8 charset named in Content-Type to UTF-8. It sits next to the
+// network, beneath RateLimitTransport and RetryTransport, so retried error
+// bodies are decoded too.
+type DecodingTransport struct {
+	Base http.RoundTripper
+	// MaxSize caps the decompressed size of a body, so a small response
+	// cannot expand without bound. Zero means DefaultMaxDecodedSize.
+	MaxSize int64
+}
+
+// NewDecodingTransport creates a new decoding transport
+func NewDecodingTransport(base http.RoundTripper) *DecodingTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &DecodingTransport{Base: base}
+}
+
+// RoundTrip advertises the supported codings unless the request sets its
+// own Accept-Encoding, then decodes the response body as it is read.
+// A 2xx body that cannot be decoded fails the request. Other responses
+// keep their status whatever their body holds, so RetryTransport and the
+// connector's status errors still see it.
+func (t *DecodingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Accept-Encoding") == "" {
+		// RoundTrippers must not modify the caller's request.
+		r2 := new(http.Request)
+		*r2 = *req
+		r2.Header = req.Header.Clone()
+		r2.Header.Set("Accept-Encoding", acceptEncoding)
+		req = r2
+	}
+
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil || resp.Body == nil || req.Method == http.MethodHead {
+		return resp, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return t.decodeErrorBody(resp), nil
+	}
+	if err := decodeBody(resp, t.maxSize()); err != nil {
+		resp.Body.Close()
+		return nil, errors.WrapError(err, errors.ErrHTTPResponse, "decode response body")
+	}
+	return resp, nil
+}
+
+func (t *DecodingTransport) maxSize() int64 {
+	if t.MaxSize > 0 {
+		return t.MaxSize
+	}
+	return DefaultMaxDecodedSize
+}
+
+// decodeErrorBody decodes a non-2xx body up front. When the body is
+// corrupt, too large or uses an unknown coding, resp is returned with the
+// body as received instead.
+func (t *DecodingTransport) decodeErrorBody(resp *http.Response) *http.Response {
+	// A body cut short still carries the status, so read errors are ignored.
+	raw, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	orig := *resp
+	orig.Header = resp.Header.Clone()
+	resp.Body = io.NopCloser(bytes.NewReader(raw))
+	if err := decodeBody(resp, t.maxSize()); err == nil {
+		decoded, err := io.ReadAll(resp.Body)
+		if err == nil {
+			resp.Body = io.NopCloser(bytes.NewReader(decoded))
+			return resp
+		}
+	}
+	orig.Body = io.NopCloser(bytes.NewReader(raw))
+	return &orig
+}
+
+// decodeBody replaces resp.Body with a reader of its decoded content
+// and updates the headers to match. Empty bodies are left alone.
+// Decompressed content past maxSize fails the read.
+func decodeBody(resp *http.Response, maxSize int64) error {
+	raw := bufio.NewReader(resp.Body)
+	if _, err := raw.Peek(1); err != nil {
+		return nil
+	}
+
+	body := &decodedBody{Reader: raw, closers: []io.Closer{resp.Body}}
+	codings := contentCodings(resp.Header.Get("Content-Encoding"))
+	// Codings are listed in the order they were applied.
+	for i := len(codings) - 1; i >= 0; i-- {
+		if err := body.decompress(codings[i]); err != nil {
+			return err
+		}
+	}
+
+	// Export files are often served as gzip files without a
+	// Content-Encoding. No UTF-8 text starts with the gzip magic bytes.
+	peek := bufio.NewReader(body.Reader)
+	body.Reader = peek
+	if magic, _ := peek.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		if err := body.decompress("gzip"); err != nil {
+			return err
+		}
+		codings = append(codings, "gzip")
+	}
+	if len(codings) > 0 {
+		body.Reader = &sizeCap{r: body.Reader, left: maxSize, max: maxSize}
+	}
+
+	converted := false
+	mediaType, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err == nil {
+		if enc := lookupCharset(params["charset"]); enc != nil {
+			body.Reader = transform.NewReader(body.Reader, enc.NewDecoder())
+			params["charset"] = "utf-8"
+			resp.Header.Set("Content-Type", mime.FormatMediaType(mediaType, params))
+			converted = true
+		}
+	}
+
+	if len(codings) > 0 {
+		resp.Header.Del("Content-Encoding")
+		resp.Uncompressed = true
+	}
+	if len(codings) > 0 || converted {
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+	}
+	resp.Body = body
+	return nil
+}
+
+// contentCodings splits a Content-Encoding header into lower-case codings,
+// leaving out identity.
+func contentCodings(header string) []string {
+	var codings []string
+	for _, c := range strings.Split(header, ",") {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if c != "" && c != "identity" {
+			codings = append(codings, c)
+		}
+	}
+	return codings
+}
+
+// lookupCharset returns the encoding named by label, or nil when label is
+// empty, names UTF-8 or is not a known charset; such bodies are read as is.
+func lookupCharset(label string) encoding.Encoding {
+	if label == "" {
+		return nil
+	}
+	enc, err := htmlindex.Get(label)
+	if err != nil {
+		return nil
+	}
+	if name, _ := htmlindex.Name(enc); name == "utf-8" {
+		return nil
+	}
+	return enc
+}
+
+// decodedBody reads a response body through its decoders. Close closes
+// the decoders and then the original body.
+type decodedBody struct {
+	io.Reader
+	closers []io.Closer
+}
+
+// decompress adds a decoder for coding on top of the body.
+func (b *decodedBody) decompress(coding string) error {
+	switch coding {
+	case "gzip", "x-gzip":
+		zr, err := gzip.NewReader(b.Reader)
+		if err != nil {
+			return fmt.Errorf("gzip: %w", err)
+		}
+		b.push(zr, zr)
+	case "deflate":
+		// "deflate" means zlib-wrapped data, but some servers send the raw
+		// stream. A zlib header is a multiple of 31 with method 8.
+		br := bufio.NewReader(b.Reader)
+		h, _ := br.Peek(2)
+		if len(h) == 2 && h[0]&0x0f == 8 && (uint16(h[0])<<8|uint16(h[1]))%31 == 0 {
+			zr, err := zlib.NewReader(br)
+			if err != nil {
+				return fmt.Errorf("deflate: %w", err)
+			}
+			b.push(zr, zr)
+		} else {
+			fr := flate.NewReader(br)
+			b.push(fr, fr)
+		}
+	case "br":
+		b.push(brotli.NewReader(b.Reader), nil)
+	case "zstd":
+		zr, err := zstd.NewReader(b.Reader, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return fmt.Errorf("zstd: %w", err)
+		}
+		rc := zr.IOReadCloser()
+		b.push(rc, rc)
+	default:
+		return fmt.Errorf("unsupported content encoding %q", coding)
+	}
+	return nil
+}
+
+// push makes r the body's reader. c, when set, is closed with the body.
+func (b *decodedBody) push(r io.Reader, c io.Closer) {
+	b.Reader = r
+	if c != nil {
+		b.closers = append(b.closers, c)
+	}
+}
+
+// Close closes the decoders, and the original body last.
+func (b *decodedBody) Close() error {
+	var err error
+	for i := len(b.closers) - 1; i >= 0; i-- {
+		if cerr := b.closers[i].Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// sizeCap passes through up to max bytes of r and fails the read that
+// would go past them.
+type sizeCap struct {
+	r         io.Reader
+	left, max int64
+}
+
+func (c *sizeCap) Read(p []byte) (int, error) {
+	if c.left < 0 {
+		return 0, c.err()
+	}
+	// Read one byte past the cap to tell a body of exactly max bytes
+	// from a larger one.
+	if int64(len(p)) > c.left+1 {
+		p = p[:c.left+1]
+	}
+	n, err := c.r.Read(p)
+	c.left -= int64(n)
+	if c.left < 0 {
+		return n - 1, c.err()
+	}
+	return n, err
+}
+
+func (c *sizeCap) err() error {
+	return fmt.Errorf("decompressed body exceeds %d bytes", c.max)
+}
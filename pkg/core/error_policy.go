@@ -0,0 +1,182 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/errors"
+	"github.com/saturnines/nexus-core/pkg/sink"
+)
+
+// WithDeadLetter writes items rejected by the error policy to s instead of
+// the error_policy.dead_letter destination. The caller owns s and closes it.
+func WithDeadLetter(s sink.Sink) ConnectorOption {
+	return func(c *Connector) {
+		c.deadLetter = s
+	}
+}
+
+// rejectedItem is an item that could not be turned into a record.
+type rejectedItem struct {
+	index  int
+	line   int // 1-based line of a line-delimited body, 0 otherwise
+	raw    interface{}
+	reason error
+}
+
+// position describes where the item was in its page for error messages.
+func (r rejectedItem) position() string {
+	if r.line > 0 {
+		return fmt.Sprintf("line %d", r.line)
+	}
+	return fmt.Sprintf("index %d", r.index)
+}
+
+// extractedPage holds the records mapped from one page, the items rejected
+// from it and how many items the page had.
+type extractedPage struct {
+	records  []map[string]interface{}
+	rejected []rejectedItem
+	items    int
+	partial  bool          // a streamed batch; more of the same page follows
+	decoded  []interface{} // line-delimited items before mapping, for the pager
+}
+
+// skipBadItems reports whether the error policy drops bad items rather than
+// failing on the first one.
+func (c *Connector) skipBadItems() bool {
+	return c.cfg.ErrorPolicy != nil && c.cfg.ErrorPolicy.Mode == config.ErrorPolicySkip
+}
+
+// validateErrorPolicy checks that a configured dead-letter destination has a
+// registered sink, so typos fail when the connector is created.
+func (c *Connector) validateErrorPolicy() error {
+	ep := c.cfg.ErrorPolicy
+	if ep == nil || ep.DeadLetter == nil || c.deadLetter != nil {
+		return nil
+	}
+	for _, kind := range sink.DefaultRegistry.GetAvailableSinks() {
+		if kind == string(ep.DeadLetter.Type) {
+			return nil
+		}
+	}
+	return errors.WrapError(
+		fmt.Errorf("unsupported destination type: %s", ep.DeadLetter.Type),
+		errors.ErrConfiguration,
+		"error policy dead letter",
+	)
+}
+
+// errorBudget counts rejected items over one run and writes them to the
+// dead-letter sink.
+type errorBudget struct {
+	c          *Connector
+	deadLetter sink.Sink
+	owned      bool // created from config and closed at the end of the run
+	ready      bool // deadLetter.Init has run
+	items      int
+	rejected   int
+}
+
+func (c *Connector) newErrorBudget() *errorBudget {
+	return &errorBudget{c: c, deadLetter: c.deadLetter}
+}
+
+// record accounts for page, writing its rejected items to the dead-letter
+// sink. It returns an error once the policy's budget is exhausted. Under
+// fail_fast the first rejected item's error is returned as-is.
+func (b *errorBudget) record(ctx context.Context, pageNo int, page extractedPage) error {
+	b.items += page.items
+	if len(page.rejected) == 0 {
+		return nil
+	}
+	b.rejected += len(page.rejected)
+
+	if err := b.writeDeadLetters(ctx, pageNo, page.rejected); err != nil {
+		return err
+	}
+
+	if !b.c.skipBadItems() {
+		return page.rejected[0].reason
+	}
+	ep := b.c.cfg.ErrorPolicy
+	if ep.MaxErrors > 0 && b.rejected > ep.MaxErrors {
+		return errors.WrapError(
+			fmt.Errorf("%d items rejected, max_errors is %d: %w", b.rejected, ep.MaxErrors, page.rejected[len(page.rejected)-1].reason),
+			errors.ErrExtraction,
+			"error policy",
+		)
+	}
+	return nil
+}
+
+// finish checks the rejected share of all items once the run is complete.
+func (b *errorBudget) finish() error {
+	ep := b.c.cfg.ErrorPolicy
+	if ep == nil || ep.MaxErrorPercent <= 0 || b.items == 0 {
+		return nil
+	}
+	if pct := float64(b.rejected) * 100 / float64(b.items); pct > ep.MaxErrorPercent {
+		return errors.WrapError(
+			fmt.Errorf("%d of %d items rejected (%.1f%%), max_error_percent is %g", b.rejected, b.items, pct, ep.MaxErrorPercent),
+			errors.ErrExtraction,
+			"error policy",
+		)
+	}
+	return nil
+}
+
+func (b *errorBudget) writeDeadLetters(ctx context.Context, pageNo int, rejected []rejectedItem) error {
+	if b.deadLetter == nil {
+		ep := b.c.cfg.ErrorPolicy
+		if ep == nil || ep.DeadLetter == nil {
+			return nil
+		}
+		s, err := sink.Create(ep.DeadLetter)
+		if err != nil {
+			return err
+		}
+		b.deadLetter, b.owned = s, true
+	}
+	if !b.ready {
+		if err := b.deadLetter.Init(ctx); err != nil {
+			return errors.WrapError(err, errors.ErrDestination, "init dead letter")
+		}
+		b.ready = true
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	records := make([]map[string]interface{}, len(rejected))
+	for i, r := range rejected {
+		raw, err := json.Marshal(r.raw)
+		if err != nil {
+			raw = []byte(fmt.Sprintf("%q", fmt.Sprint(r.raw)))
+		}
+		records[i] = map[string]interface{}{
+			"pipeline":    b.c.cfg.Name,
+			"page":        pageNo,
+			"index":       r.index,
+			"reason":      r.reason.Error(),
+			"raw":         string(raw),
+			"rejected_at": now,
+		}
+		if r.line > 0 {
+			records[i]["line"] = r.line
+		}
+	}
+	if err := b.deadLetter.Write(ctx, records); err != nil {
+		return errors.WrapError(err, errors.ErrDestination, "write dead letter")
+	}
+	return nil
+}
+
+// close releases a dead-letter sink created for this run.
+func (b *errorBudget) close() error {
+	if b.owned && b.deadLetter != nil {
+		return b.deadLetter.Close()
+	}
+	return nil
+}
@@ -1,229 +1,50 @@
 package core
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"github.com/saturnines/nexus-core/pkg/config"
 	"github.com/saturnines/nexus-core/pkg/errors"
-	"strconv"
+	"github.com/saturnines/nexus-core/pkg/jsonpath"
+	"io"
 	"strings"
 )
 
-// ExtractFieldEnhanced extracts a field from data using an enhanced path syntax
-// Supports:
+// ExtractFieldEnhanced extracts a field from data using a JSONPath
+// expression. Supports:
 // - Nested fields: "user.name"
 // - Array indices: "items[0]", "items[-1]" (negative for last)
 // - Array wildcards: "items[*].name"
 // - Complex paths: "data.users[*].addresses[0].city"
+// - Filters, slices, unions, quoted keys and descent: "items[?(@.id>1)]"
+//
+// Paths that can select several values return them as []interface{}.
 func ExtractFieldEnhanced(data interface{}, path string) (interface{}, bool) {
 	if path == "" {
 		return nil, false
 	}
-
-	// Parse the path into segments
-	segments, err := parsePath(path)
-	if err != nil {
-		return nil, false
-	}
-
-	return traversePath(data, segments)
+	return jsonpath.Get(data, path)
 }
 
-// PathSegment represents a single segment in a path
-type PathSegment struct {
-	Field string
-	Type  SegmentType
-	Index int // For array indices
-}
-
-type SegmentType int
-
-const (
-	FieldSegment  SegmentType = iota // Regular field access
-	ArrayIndex                       // Specific array index [0], [-1]
-	ArrayWildcard                    // Array wildcard [*]
-)
-
-// parsePath converts a string path into structured segments
-func parsePath(path string) ([]PathSegment, error) {
-	var segments []PathSegment
-
-	// Handle paths that start with array notation (e.g., "[0].field")
-	if strings.HasPrefix(path, "[") {
-		path = "." + path
+// decodeResponse decodes a JSON body. In exact mode numbers are kept as
+// json.Number instead of float64.
+func decodeResponse(raw []byte, mode config.NumberMode) (interface{}, error) {
+	var v interface{}
+	if mode != config.NumberModeExact {
+		err := json.Unmarshal(raw, &v)
+		return v, err
 	}
 
-	parts := strings.Split(path, ".")
-
-	for _, part := range parts {
-		if part == "" {
-			continue
-		}
-
-		// Check for array notation
-		if idx := strings.Index(part, "["); idx != -1 {
-			// Field name before bracket
-			if idx > 0 {
-				fieldName := part[:idx]
-				segments = append(segments, PathSegment{
-					Field: fieldName,
-					Type:  FieldSegment,
-				})
-			}
-
-			// Parse array indices
-			remaining := part[idx:]
-			for len(remaining) > 0 {
-				if !strings.HasPrefix(remaining, "[") {
-					return nil, errors.WrapError(
-						fmt.Errorf("invalid array notation in path: %s", part),
-						errors.ErrConfiguration,
-						"parse field path",
-					)
-				}
-
-				endIdx := strings.Index(remaining, "]")
-				if endIdx == -1 {
-					return nil, errors.WrapError(
-						fmt.Errorf("unclosed bracket in path: %s", part),
-						errors.ErrConfiguration,
-						"parse field path",
-					)
-				}
-
-				indexStr := remaining[1:endIdx]
-
-				// Handle wildcard
-				if indexStr == "*" {
-					segments = append(segments, PathSegment{
-						Type: ArrayWildcard,
-					})
-				} else {
-					// Parse numeric index
-					index, err := strconv.Atoi(indexStr)
-					if err != nil {
-						return nil, errors.WrapError(
-							fmt.Errorf("invalid array index: %s", indexStr),
-							errors.ErrConfiguration,
-							"parse array index",
-						)
-					}
-					segments = append(segments, PathSegment{
-						Type:  ArrayIndex,
-						Index: index,
-					})
-				}
-
-				// Move past this bracket pair
-				remaining = remaining[endIdx+1:]
-
-				// Handle chained array access like [0][1]
-				if len(remaining) > 0 && !strings.HasPrefix(remaining, "[") {
-					// There's a field after the bracket
-					if dotIdx := strings.Index(remaining, "."); dotIdx != -1 {
-						// Skip to next part
-						break
-					} else {
-						// This is an error - text after ] without .
-						return nil, errors.WrapError(
-							fmt.Errorf("invalid syntax after bracket: %s", remaining),
-							errors.ErrConfiguration,
-							"parse field path",
-						)
-					}
-				}
-			}
-		} else {
-			// Simple field
-			segments = append(segments, PathSegment{
-				Field: part,
-				Type:  FieldSegment,
-			})
-		}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
 	}
-
-	return segments, nil
-}
-
-// traversePath walks through data following the path segments
-func traversePath(data interface{}, segments []PathSegment) (interface{}, bool) {
-	current := data
-
-	for i, segment := range segments {
-		switch segment.Type {
-		case FieldSegment:
-			// Handle map field access
-			switch v := current.(type) {
-			case map[string]interface{}:
-				val, ok := v[segment.Field]
-				if !ok {
-					return nil, false
-				}
-				current = val
-
-			case []interface{}:
-				// If we're trying to access a field on an array,
-				// and this is followed by a wildcard, we might be doing
-				// something like items[*].name
-				if i+1 < len(segments) && segments[i+1].Type == ArrayWildcard {
-					// Skip wildcard will handle
-					continue
-				}
-				return nil, false
-
-			default:
-				return nil, false
-			}
-
-		case ArrayIndex:
-			// Handle array index access
-			arr, ok := current.([]interface{})
-			if !ok {
-				return nil, false
-			}
-
-			index := segment.Index
-			// Handle negative indices
-			if index < 0 {
-				index = len(arr) + index
-			}
-
-			if index < 0 || index >= len(arr) {
-				return nil, false
-			}
-
-			current = arr[index]
-
-		case ArrayWildcard:
-			// Handle array wildcard
-			arr, ok := current.([]interface{})
-			if !ok {
-				return nil, false
-			}
-
-			// If this is the last segment just return the whole array
-			if i == len(segments)-1 {
-				return arr, true
-			}
-
-			// Otherwise collect results from remaining path on each element
-			var results []interface{}
-			remainingSegments := segments[i+1:]
-
-			for _, elem := range arr {
-				if result, ok := traversePath(elem, remainingSegments); ok {
-					// Handle nested wildcards - flatten arrays
-					if resultArr, isArr := result.([]interface{}); isArr {
-						results = append(results, resultArr...)
-					} else {
-						results = append(results, result)
-					}
-				}
-			}
-
-			return results, len(results) > 0
-		}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid character after top-level value")
 	}
-
-	return current, true
+	return v, nil
 }
 
 // ExtractFieldsMulti Helper function to extract multiple fields with array support
@@ -0,0 +1,216 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/errors"
+	"github.com/saturnines/nexus-core/pkg/jsonpath"
+	"github.com/saturnines/nexus-core/pkg/transform"
+)
+
+// fieldMapping is a config.Field with its path and transform chain compiled.
+type fieldMapping struct {
+	config.Field
+	path      *jsonpath.Path        // nil when the field has no path
+	transform transform.Transformer // nil when the field has no transform or type
+}
+
+// typeAliases maps Field.Type spellings onto registered transformer names.
+var typeAliases = map[string]string{
+	"integer": "int",
+	"number":  "float",
+	"double":  "float",
+	"boolean": "bool",
+	"text":    "string",
+	"numeric": "decimal",
+}
+
+// compileFields builds the transform chain for every field once, so
+// configuration errors surface when the connector is created.
+// Field.Transform runs first, then the Field.Type coercion.
+func compileFields(fields []config.Field, registry *transform.Registry) ([]fieldMapping, error) {
+	if registry == nil {
+		registry = transform.DefaultRegistry
+	}
+
+	mappings := make([]fieldMapping, len(fields))
+	for i, f := range fields {
+		var path *jsonpath.Path
+		if f.Path != "" {
+			p, err := jsonpath.Compile(f.Path)
+			if err != nil {
+				return nil, errors.WrapError(
+					err,
+					errors.ErrConfiguration,
+					fmt.Sprintf("compile path for field %q", f.Name),
+				)
+			}
+			path = p
+		}
+
+		var steps []transform.Transformer
+
+		if f.Transform != nil {
+			t, err := buildTransform(registry, f.Transform)
+			if err != nil {
+				return nil, errors.WrapError(
+					err,
+					errors.ErrConfiguration,
+					fmt.Sprintf("compile transform for field %q", f.Name),
+				)
+			}
+			steps = append(steps, t)
+		}
+
+		if f.Type != "" {
+			name := strings.ToLower(f.Type)
+			if alias, ok := typeAliases[name]; ok {
+				name = alias
+			}
+			t, err := registry.Create(name, nil)
+			if err != nil {
+				return nil, errors.WrapError(
+					err,
+					errors.ErrConfiguration,
+					fmt.Sprintf("compile type for field %q", f.Name),
+				)
+			}
+			steps = append(steps, t)
+		}
+
+		mappings[i] = fieldMapping{Field: f, path: path}
+		switch len(steps) {
+		case 0:
+		case 1:
+			mappings[i].transform = steps[0]
+		default:
+			mappings[i].transform = transform.NewChainTransform(steps...)
+		}
+	}
+	return mappings, nil
+}
+
+// buildTransform turns a FieldTransform into a Transformer. A node may set
+// Type, Chain or both; Type is applied before the chain.
+func buildTransform(registry *transform.Registry, ft *config.FieldTransform) (transform.Transformer, error) {
+	var steps []transform.Transformer
+
+	if ft.Type != "" {
+		t, err := registry.Create(ft.Type, ft.Config)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, t)
+	}
+
+	for i := range ft.Chain {
+		t, err := buildTransform(registry, &ft.Chain[i])
+		if err != nil {
+			return nil, fmt.Errorf("chain[%d]: %w", i, err)
+		}
+		steps = append(steps, t)
+	}
+
+	switch len(steps) {
+	case 0:
+		return nil, fmt.Errorf("transform must set type or chain")
+	case 1:
+		return steps[0], nil
+	default:
+		return transform.NewChainTransform(steps...), nil
+	}
+}
+
+// lookup evaluates the field's path against item.
+func (f *fieldMapping) lookup(item interface{}) (interface{}, bool) {
+	if f.path == nil {
+		return nil, false
+	}
+	return f.path.Get(item)
+}
+
+// mapFields applies fields to item. Both extractors map items through it,
+// so REST and GraphQL treat missing and null values the same way.
+func mapFields(fields []fieldMapping, item interface{}) (map[string]interface{}, error) {
+	mapped := make(map[string]interface{}, len(fields))
+	for i := range fields {
+		f := &fields[i]
+		value, ok := f.lookup(item)
+
+		var policy config.FieldPolicy
+		switch {
+		case !ok:
+			policy = f.OnMissing
+		case value == nil:
+			policy = f.OnNull
+		default:
+			value, err := f.apply(value)
+			if err != nil {
+				return nil, err
+			}
+			mapped[f.Name] = value
+			continue
+		}
+
+		switch policy {
+		case config.FieldPolicyOmit:
+		case config.FieldPolicyNull:
+			mapped[f.Name] = nil
+		case config.FieldPolicyDefault:
+			mapped[f.Name] = f.DefaultValue
+		case config.FieldPolicyError:
+			state := "missing"
+			if ok {
+				state = "null"
+			}
+			return nil, errors.WrapError(
+				fmt.Errorf("field %q is %s at path %q", f.Name, state, f.Path),
+				errors.ErrExtraction,
+				"apply field policy",
+			)
+		default:
+			// Unset: fall back to the default value, if any.
+			if f.DefaultValue != nil {
+				mapped[f.Name] = f.DefaultValue
+			}
+		}
+	}
+	return mapped, nil
+}
+
+// unresolvedFields returns the fields whose path resolves on none of items,
+// skipping fields that declare on_missing and so expect to be absent.
+func unresolvedFields(fields []fieldMapping, items []interface{}) []fieldMapping {
+	var out []fieldMapping
+	for i := range fields {
+		f := &fields[i]
+		if f.OnMissing != "" {
+			continue
+		}
+		found := false
+		for _, item := range items {
+			if _, ok := f.lookup(item); ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			out = append(out, *f)
+		}
+	}
+	return out
+}
+
+// apply runs the compiled transform chain on a present, non-null value.
+func (f *fieldMapping) apply(value interface{}) (interface{}, error) {
+	if f.transform == nil {
+		return value, nil
+	}
+	out, err := f.transform.Transform(value)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrExtraction, fmt.Sprintf("transform field %q", f.Name))
+	}
+	return out, nil
+}
@@ -0,0 +1,127 @@
+package core
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/errors"
+)
+
+// flattener merges nested objects into prefixed top-level keys.
+type flattener struct {
+	sep      string
+	maxDepth int // 0 means no limit
+	arrays   config.FlattenArrays
+	exclude  map[string]bool
+}
+
+func newFlattener(fl *config.Flatten, exclude []string) *flattener {
+	f := &flattener{exclude: make(map[string]bool, len(exclude))}
+	for _, k := range exclude {
+		f.exclude[k] = true
+	}
+	if fl == nil {
+		// Without flatten the item is copied as-is, minus excluded keys.
+		f.maxDepth = -1
+		f.arrays = config.FlattenArraysKeep
+		return f
+	}
+	f.sep = fl.Separator
+	if f.sep == "" {
+		f.sep = "_"
+	}
+	f.maxDepth = fl.MaxDepth
+	f.arrays = fl.Arrays
+	return f
+}
+
+// flatten returns the records for item: one, or several when arrays are
+// exploded. Keys are visited in sorted order so output is deterministic.
+func (f *flattener) flatten(item map[string]interface{}) ([]map[string]interface{}, error) {
+	records := []map[string]interface{}{make(map[string]interface{}, len(item))}
+	var err error
+	for _, k := range sortedKeys(item) {
+		if records, err = f.add(records, k, item[k], 1); err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
+
+// add stores v under key in every record. depth is the nesting level of
+// key, starting at 1 for the item's own keys.
+func (f *flattener) add(records []map[string]interface{}, key string, v interface{}, depth int) ([]map[string]interface{}, error) {
+	if f.exclude[key] {
+		return records, nil
+	}
+	nest := f.maxDepth == 0 || depth <= f.maxDepth
+
+	var err error
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if nest && len(val) > 0 {
+			for _, k := range sortedKeys(val) {
+				if records, err = f.add(records, key+f.sep+k, val[k], depth+1); err != nil {
+					return nil, err
+				}
+			}
+			return records, nil
+		}
+
+	case []interface{}:
+		switch f.arrays {
+		case config.FlattenArraysJSON:
+			b, err := json.Marshal(val)
+			if err != nil {
+				return nil, errors.WrapError(err, errors.ErrExtraction, "flatten array "+key)
+			}
+			v = string(b)
+
+		case config.FlattenArraysIndex:
+			if nest && len(val) > 0 {
+				for i, el := range val {
+					if records, err = f.add(records, key+f.sep+strconv.Itoa(i), el, depth+1); err != nil {
+						return nil, err
+					}
+				}
+				return records, nil
+			}
+
+		case config.FlattenArraysExplode:
+			if len(val) == 0 {
+				v = nil
+				break
+			}
+			// Every record is copied once per element, so several
+			// exploded arrays yield their cross product.
+			var out []map[string]interface{}
+			for _, el := range val {
+				copies := make([]map[string]interface{}, len(records))
+				for i, r := range records {
+					copies[i] = copyRecord(r, 0)
+				}
+				if copies, err = f.add(copies, key, el, depth); err != nil {
+					return nil, err
+				}
+				out = append(out, copies...)
+			}
+			return out, nil
+		}
+	}
+
+	for _, r := range records {
+		r[key] = v
+	}
+	return records, nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,49 +1,72 @@
 package core
 
 import (
-	"encoding/json"
 	"fmt"
 	"github.com/saturnines/nexus-core/pkg/errors"
+	"github.com/saturnines/nexus-core/pkg/jsonpath"
 	"strings"
 
 	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/transform"
 )
 
 // GraphQLExtractor implements Extractor for GraphQL sources.
 type GraphQLExtractor struct {
-	rootPath string
-	fields   []config.Field
+	rootPath   string
+	root       *jsonpath.Path
+	numberMode config.NumberMode
+	recordMapper
 }
 
-// NewGraphQLExtractor initialises a GraphQLExtractor.
-func NewGraphQLExtractor(g *config.GraphQLSource) *GraphQLExtractor {
-	var root string
-	rp := g.ResponseMapping.RootPath
+// NewGraphQLExtractor initialises a GraphQLExtractor. A nil registry uses
+// transform.DefaultRegistry.
+func NewGraphQLExtractor(g *config.GraphQLSource, registry *transform.Registry) (*GraphQLExtractor, error) {
+	root := graphQLPath(g.ResponseMapping.RootPath)
+	path, err := compileRootPath(root)
+	if err != nil {
+		return nil, err
+	}
 
-	switch {
-	case rp == "", rp == "data":
-		root = "data"
-	case strings.HasPrefix(rp, "data."):
-		root = rp
-	default:
-		root = "data." + rp
+	mapper, err := newRecordMapper(g.ResponseMapping, registry)
+	if err != nil {
+		return nil, err
 	}
 
 	return &GraphQLExtractor{
-		rootPath: root,
-		fields:   g.ResponseMapping.Fields,
+		rootPath:     root,
+		root:         path,
+		numberMode:   g.ResponseMapping.NumberMode,
+		recordMapper: mapper,
+	}, nil
+}
+
+// graphQLPath resolves a configured path against the whole GraphQL response.
+// Paths are relative to "data" unless they already start with "data" or "$".
+func graphQLPath(p string) string {
+	switch {
+	case p == "", p == "data":
+		return "data"
+	case strings.HasPrefix(p, "data."), strings.HasPrefix(p, "data["), strings.HasPrefix(p, "$"):
+		return p
+	default:
+		return "data." + p
 	}
 }
 
 // Items extracts the slice of items from the GraphQL response body.
 func (e *GraphQLExtractor) Items(b []byte) ([]interface{}, error) {
-	var raw interface{}
-	if err := json.Unmarshal(b, &raw); err != nil {
+	raw, err := decodeResponse(b, e.numberMode)
+	if err != nil {
 		return nil, errors.WrapError(err, errors.ErrHTTPResponse, "decode GraphQL response JSON")
 	}
 
+	// A wildcard, filter or descent root selects the items themselves
+	if !e.root.Definite() {
+		return e.root.Find(raw), nil
+	}
+
 	// Navigate to the configured root
-	node, ok := ExtractFieldEnhanced(raw, e.rootPath)
+	node, ok := e.root.Get(raw)
 	if !ok || node == nil {
 		return nil, errors.WrapError(
 			fmt.Errorf("root path '%s' not found", e.rootPath),
@@ -67,15 +90,7 @@ func (e *GraphQLExtractor) Items(b []byte) ([]interface{}, error) {
 	}
 }
 
-// Map applies the field mappings to a single item.
+// Map maps a single item to one record.
 func (e *GraphQLExtractor) Map(item interface{}) (map[string]interface{}, error) {
-	m := make(map[string]interface{}, len(e.fields))
-	for _, f := range e.fields {
-		if v, ok := ExtractFieldEnhanced(item, f.Path); ok && v != nil {
-			m[f.Name] = v
-		} else if f.DefaultValue != nil {
-			m[f.Name] = f.DefaultValue
-		}
-	}
-	return m, nil
+	return e.mapItem(item)
 }
@@ -0,0 +1,50 @@
+package core
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/errors"
+	"github.com/saturnines/nexus-core/pkg/transport/graphql"
+)
+
+// checkGraphQLRetry reports whether a GraphQL response failed with an error
+// listed in RetryConfig.RetryableGraphQLErrors, along with how long the
+// response's cost hints ask us to wait before trying again.
+func (c *Connector) checkGraphQLRetry(body []byte) (bool, time.Duration) {
+	if c.cfg.Source.Type != config.SourceTypeGraphQL ||
+		c.cfg.RetryConfig == nil || len(c.cfg.RetryConfig.RetryableGraphQLErrors) == 0 {
+		return false, 0
+	}
+
+	var resp errors.GraphQLResponse
+	if err := json.Unmarshal(body, &resp); err != nil || len(resp.Errors) == 0 {
+		return false, 0
+	}
+	if !c.isRetryableGraphQLError(resp.Errors) {
+		return false, 0
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return true, 0
+	}
+	return true, graphql.ThrottleDelay(data, time.Now())
+}
+
+// isRetryableGraphQLError matches each configured entry against the error
+// code (extensions.code or type) or, failing that, the message.
+func (c *Connector) isRetryableGraphQLError(gqlErrs []errors.GraphQLError) bool {
+	for _, e := range gqlErrs {
+		code := e.Code()
+		msg := strings.ToLower(e.Message)
+		for _, want := range c.cfg.RetryConfig.RetryableGraphQLErrors {
+			if strings.EqualFold(want, code) || strings.Contains(msg, strings.ToLower(want)) {
+				return true
+			}
+		}
+	}
+	return false
+}
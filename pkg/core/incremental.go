@@ -0,0 +1,206 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/saturnines/nexus-core/pkg/checkpoint"
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/errors"
+	"github.com/saturnines/nexus-core/pkg/transport/graphql"
+	"github.com/saturnines/nexus-core/pkg/transport/rest"
+)
+
+// WithWatermarkStore persists the incremental sync watermark in store under
+// key, which defaults to the pipeline name. Without a store the watermark
+// only lives as long as the Connector.
+func WithWatermarkStore(store checkpoint.WatermarkStore, key string) ConnectorOption {
+	return func(c *Connector) {
+		c.watermarkStore = store
+		c.watermarkKey = key
+	}
+}
+
+// Watermark returns the high-watermark committed by the last completed run,
+// or "" if there is none yet.
+func (c *Connector) Watermark() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.watermark
+}
+
+// validateIncremental checks that the cursor has somewhere to go.
+func validateIncremental(cfg *config.Pipeline) error {
+	inc := cfg.Incremental
+	if inc == nil {
+		return nil
+	}
+	var err error
+	switch {
+	case inc.CursorField == "":
+		err = fmt.Errorf("cursor_field is required")
+	case cfg.Source.Type == config.SourceTypeGraphQL && inc.Variable == "":
+		err = fmt.Errorf("variable is required for GraphQL sources")
+	case cfg.Source.Type == config.SourceTypeREST && inc.Param == "":
+		err = fmt.Errorf("param is required for REST sources")
+	}
+	if err != nil {
+		return errors.WrapError(err, errors.ErrConfiguration, "incremental config")
+	}
+	return nil
+}
+
+func (c *Connector) watermarkStoreKey() string {
+	if c.watermarkKey != "" {
+		return c.watermarkKey
+	}
+	return c.cfg.Name
+}
+
+// beginIncremental loads the last watermark and returns the request
+// builder for this run, carrying the watermark, with a tracker for the
+// run. With a store, the stored watermark is the only one used. The
+// connector's builder is returned unchanged, with a nil tracker, when the
+// pipeline is not incremental.
+func (c *Connector) beginIncremental(ctx context.Context) (RequestBuilder, *watermarkTracker, error) {
+	inc := c.cfg.Incremental
+	if inc == nil {
+		return c.builder, nil, nil
+	}
+
+	var value string
+	if c.watermarkStore != nil {
+		stored, err := c.watermarkStore.LoadWatermark(ctx, c.watermarkStoreKey())
+		if err != nil {
+			return nil, nil, err
+		}
+		value = stored
+	} else {
+		value = c.Watermark()
+	}
+	if value == "" {
+		value = inc.InitialValue
+	}
+
+	builder := c.builder
+	if value != "" {
+		builder = c.withWatermark(value)
+	}
+	return builder, &watermarkTracker{field: inc.CursorField, start: value, max: value}, nil
+}
+
+// withWatermark returns a copy of the connector's builder with the REST
+// query parameter or GraphQL variable set. Runs may overlap, so the
+// shared builder and the pipeline config are never modified.
+func (c *Connector) withWatermark(value string) RequestBuilder {
+	inc := c.cfg.Incremental
+	switch b := c.builder.(type) {
+	case *rest.Builder:
+		nb := *b
+		nb.QueryParams = make(map[string]string, len(b.QueryParams)+1)
+		for k, v := range b.QueryParams {
+			nb.QueryParams[k] = v
+		}
+		nb.QueryParams[inc.Param] = value
+		return &nb
+	case *graphql.Builder:
+		nb := *b
+		nb.Variables = make(map[string]interface{}, len(b.Variables)+1)
+		for k, v := range b.Variables {
+			nb.Variables[k] = v
+		}
+		nb.Variables[inc.Variable] = value
+		return &nb
+	}
+	return c.builder
+}
+
+// commitIncremental records the run's watermark once every page has been
+// consumed, so a failed or abandoned run never skips records. Overlapping
+// runs can finish in any order, so a watermark older than the one already
+// committed is ignored.
+func (c *Connector) commitIncremental(ctx context.Context, t *watermarkTracker) error {
+	if t == nil || t.max == t.start {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.watermarkStore != nil {
+		key := c.watermarkStoreKey()
+		stored, err := c.watermarkStore.LoadWatermark(ctx, key)
+		if err != nil {
+			return err
+		}
+		if stored == "" || compareWatermarks(t.max, stored) > 0 {
+			if err := c.watermarkStore.SaveWatermark(ctx, key, t.max); err != nil {
+				return err
+			}
+		}
+	}
+	if c.watermark == "" || compareWatermarks(t.max, c.watermark) > 0 {
+		c.watermark = t.max
+	}
+	return nil
+}
+
+// watermarkTracker keeps the largest cursor value seen during a run.
+type watermarkTracker struct {
+	field string
+	start string
+	max   string
+}
+
+func (t *watermarkTracker) observe(record map[string]interface{}) {
+	if t == nil {
+		return
+	}
+	v, ok := record[t.field]
+	if !ok || v == nil {
+		return
+	}
+	s := formatParam(v)
+	if s == "" {
+		return
+	}
+	if t.max == "" || compareWatermarks(s, t.max) > 0 {
+		t.max = s
+	}
+}
+
+// formatParam renders a value the way it is sent in a request. Floats are
+// written without exponents so large IDs and epochs survive.
+func formatParam(v interface{}) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(x), 'f', -1, 32)
+	case time.Time:
+		return x.UTC().Format(time.RFC3339Nano)
+	default:
+		return stringifyValue(x)
+	}
+}
+
+// compareWatermarks orders two cursor values numerically, then as RFC 3339
+// timestamps, and otherwise lexically. Numbers are compared exactly, so
+// 64-bit IDs that differ only in their last digits still order correctly.
+func compareWatermarks(a, b string) int {
+	if ra, ok := new(big.Rat).SetString(a); ok && !strings.Contains(a, "/") {
+		if rb, ok := new(big.Rat).SetString(b); ok && !strings.Contains(b, "/") {
+			return ra.Cmp(rb)
+		}
+	}
+	if ta, err := time.Parse(time.RFC3339Nano, a); err == nil {
+		if tb, err := time.Parse(time.RFC3339Nano, b); err == nil {
+			return ta.Compare(tb)
+		}
+	}
+	return strings.Compare(a, b)
+}
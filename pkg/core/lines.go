@@ -0,0 +1,95 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/errors"
+)
+
+// badLine stands in for an item whose line or row could not be decoded, so
+// the connector can reject it like any other bad item.
+type badLine struct {
+	line int
+	text string
+	err  error
+}
+
+func (b badLine) error() error {
+	return errors.WrapError(
+		fmt.Errorf("line %d: %v", b.line, b.err),
+		errors.ErrExtraction,
+		"decode response line",
+	)
+}
+
+// lineDelimited reports whether bodies hold one item per line or row.
+func (e *RestExtractor) lineDelimited() bool {
+	switch e.format {
+	case config.ResponseFormatNDJSON, config.ResponseFormatCSV, config.ResponseFormatTSV:
+		return true
+	}
+	return false
+}
+
+// decodeLines decodes a line-delimited body. lines holds the 1-based line
+// number each item starts on. Lines or rows that cannot be decoded are
+// returned as badLine items.
+func (e *RestExtractor) decodeLines(raw []byte) (items []interface{}, lines []int) {
+	// Reading from memory cannot fail.
+	_ = e.readLines(bytes.NewReader(raw), e.numberMode, func(item interface{}, line int) error {
+		items = append(items, item)
+		lines = append(lines, line)
+		return nil
+	})
+	return items, lines
+}
+
+// readLines reads a line-delimited body from r, handing each item and the
+// line it starts on to emit. JSON numbers are decoded per mode.
+func (e *RestExtractor) readLines(r io.Reader, mode config.NumberMode, emit func(item interface{}, line int) error) error {
+	if e.format == config.ResponseFormatNDJSON {
+		return readNDJSON(r, mode, emit)
+	}
+	return e.csv.read(r, emit)
+}
+
+// lineSkeleton stands in for a buffered line-delimited body when pagers
+// read it: an array with one entry per item in which only the last is
+// kept, as the streaming decoder leaves an items array. Pagers see it as
+// "data", so "data[-1].id" still reads a cursor. items are the body's
+// decoded items; csv rows are reused as they are, while the last ndjson
+// line is decoded again with exact numbers.
+func (e *RestExtractor) lineSkeleton(raw []byte, items []interface{}) []interface{} {
+	if e.format == config.ResponseFormatNDJSON {
+		return ndjsonSkeleton(raw)
+	}
+	var last interface{}
+	for i := len(items) - 1; i >= 0; i-- {
+		if _, bad := items[i].(badLine); !bad {
+			last = items[i]
+			break
+		}
+	}
+	return e.lineStub(len(items), last)
+}
+
+// lineStub is itemsStub for line-delimited bodies. csv cells are all text,
+// so the kept row is typed for pagers, as typedText describes.
+func (e *RestExtractor) lineStub(n int, last interface{}) []interface{} {
+	if e.format != config.ResponseFormatNDJSON {
+		last = typedText(last)
+	}
+	return itemsStub(n, last)
+}
+
+// itemsStub returns an array of n items in which only the last is kept.
+func itemsStub(n int, last interface{}) []interface{} {
+	stub := make([]interface{}, n)
+	if n > 0 {
+		stub[n-1] = last
+	}
+	return stub
+}
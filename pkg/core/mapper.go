@@ -0,0 +1,205 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/errors"
+	"github.com/saturnines/nexus-core/pkg/jsonpath"
+	"github.com/saturnines/nexus-core/pkg/transform"
+)
+
+// recordMapper turns one response item into records. Both extractors embed
+// it so REST and GraphQL items are mapped identically.
+type recordMapper struct {
+	fields      []fieldMapping
+	passthrough *flattener      // nil unless mode is passthrough
+	explode     *explodeMapping // nil unless the mapping fans out a nested array
+}
+
+// explodeMapping is a config.Explode with its path and fields compiled.
+type explodeMapping struct {
+	config.Explode
+	path   *jsonpath.Path
+	fields []fieldMapping
+}
+
+func newRecordMapper(m config.ResponseMapping, registry *transform.Registry) (recordMapper, error) {
+	fields, err := compileFields(m.Fields, registry)
+	if err != nil {
+		return recordMapper{}, err
+	}
+	r := recordMapper{fields: fields}
+	if m.Mode == config.MappingModePassthrough {
+		r.passthrough = newFlattener(m.Flatten, m.Exclude)
+	}
+
+	if ex := m.Explode; ex != nil {
+		path, err := jsonpath.Compile(ex.Path)
+		if err != nil {
+			return recordMapper{}, errors.WrapError(err, errors.ErrConfiguration, "compile explode path")
+		}
+		exFields, err := compileFields(ex.Fields, registry)
+		if err != nil {
+			return recordMapper{}, err
+		}
+		r.explode = &explodeMapping{Explode: *ex, path: path, fields: exFields}
+	}
+	return r, nil
+}
+
+// mapRecords maps item to its records: one record, or one per element of
+// each exploded array, each carrying the item's fields.
+func (r *recordMapper) mapRecords(item interface{}) ([]map[string]interface{}, error) {
+	parents, err := r.mapParents(item)
+	if err != nil || r.explode == nil {
+		return parents, err
+	}
+
+	var records []map[string]interface{}
+	for _, parent := range parents {
+		exploded, err := r.explode.records(parent, item)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, exploded...)
+	}
+	return records, nil
+}
+
+// mapItem returns the first record mapParents builds for item, or an empty
+// record when flattening exploded an array into none.
+func (r *recordMapper) mapItem(item interface{}) (map[string]interface{}, error) {
+	records, err := r.mapParents(item)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return map[string]interface{}{}, nil
+	}
+	return records[0], nil
+}
+
+// mapParents maps item before any explode: the configured fields, or in
+// passthrough mode the flattened item with the fields layered on top.
+func (r *recordMapper) mapParents(item interface{}) ([]map[string]interface{}, error) {
+	mapped, err := mapFields(r.fields, item)
+	if err != nil {
+		return nil, err
+	}
+	if r.passthrough == nil {
+		return []map[string]interface{}{mapped}, nil
+	}
+
+	obj, ok := item.(map[string]interface{})
+	if !ok {
+		return nil, errors.WrapError(
+			fmt.Errorf("passthrough item is not an object: %T", item),
+			errors.ErrExtraction,
+			"pass item through",
+		)
+	}
+	records, err := r.passthrough.flatten(obj)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		for k, v := range mapped {
+			record[k] = v
+		}
+	}
+	return records, nil
+}
+
+// records explodes item into one record per element, each starting from a
+// copy of parent.
+func (ex *explodeMapping) records(parent map[string]interface{}, item interface{}) ([]map[string]interface{}, error) {
+	elements, err := ex.elements(item)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(elements) == 0 {
+		if ex.OnEmpty != config.ExplodeEmptyKeep {
+			return nil, nil
+		}
+		record := copyRecord(parent, len(ex.fields)+1)
+		for _, f := range ex.fields {
+			record[f.Name] = nil
+		}
+		if ex.IndexField != "" {
+			record[ex.IndexField] = nil
+		}
+		return []map[string]interface{}{record}, nil
+	}
+
+	records := make([]map[string]interface{}, len(elements))
+	for i, el := range elements {
+		child, err := mapFields(ex.fields, el)
+		if err != nil {
+			return nil, fmt.Errorf("explode %s element %d: %w", ex.Path, i, err)
+		}
+		record := copyRecord(parent, len(child)+1)
+		for k, v := range child {
+			record[k] = v
+		}
+		if ex.IndexField != "" {
+			record[ex.IndexField] = i
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// unresolved returns the fields whose path resolves on none of items,
+// checking exploded fields against every element of the page.
+func (r *recordMapper) unresolved(items []interface{}) []fieldMapping {
+	missing := unresolvedFields(r.fields, items)
+	if r.explode == nil {
+		return missing
+	}
+
+	ex := r.explode
+	var elements []interface{}
+	found := false
+	for _, item := range items {
+		if _, ok := ex.path.Get(item); ok {
+			found = true
+		}
+		els, _ := ex.elements(item)
+		elements = append(elements, els...)
+	}
+	if !found {
+		return append(missing, fieldMapping{Field: config.Field{Name: "explode", Path: ex.Path}})
+	}
+	if len(elements) > 0 {
+		missing = append(missing, unresolvedFields(ex.fields, elements)...)
+	}
+	return missing
+}
+
+// elements returns the array to explode. A missing or null array is empty.
+func (ex *explodeMapping) elements(item interface{}) ([]interface{}, error) {
+	v, ok := ex.path.Get(item)
+	if !ok || v == nil {
+		return nil, nil
+	}
+	arr, ok := v.([]interface{})
+	if !ok {
+		return nil, errors.WrapError(
+			fmt.Errorf("explode path %q is not an array, got %T", ex.Path, v),
+			errors.ErrExtraction,
+			"explode item",
+		)
+	}
+	return arr, nil
+}
+
+// copyRecord returns a shallow copy of r with room for extra more keys.
+func copyRecord(r map[string]interface{}, extra int) map[string]interface{} {
+	out := make(map[string]interface{}, len(r)+extra)
+	for k, v := range r {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+)
+
+// readNDJSON reads one JSON item per line, skipping blank lines.
+func readNDJSON(r io.Reader, mode config.NumberMode, emit func(item interface{}, line int) error) error {
+	br := bufio.NewReader(r)
+	for n := 1; ; n++ {
+		text, err := br.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if item, ok := decodeLine(text, n, mode); ok {
+			if emitErr := emit(item, n); emitErr != nil {
+				return emitErr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
+
+// decodeLine decodes line n. ok is false for a blank line.
+func decodeLine(text []byte, n int, mode config.NumberMode) (item interface{}, ok bool) {
+	text = bytes.TrimSpace(text)
+	if len(text) == 0 {
+		return nil, false
+	}
+	item, err := decodeResponse(text, mode)
+	if err != nil {
+		return badLine{line: n, text: string(text), err: err}, true
+	}
+	return item, true
+}
+
+// ndjsonSkeleton counts the items of an ndjson body and decodes only the
+// last one, with numbers kept as json.Number as pagers read them.
+func ndjsonSkeleton(raw []byte) []interface{} {
+	n := 0
+	var last []byte
+	for _, text := range bytes.Split(raw, []byte{'\n'}) {
+		if text = bytes.TrimSpace(text); len(text) > 0 {
+			n++
+			last = text
+		}
+	}
+	var item interface{}
+	if last != nil {
+		item, _ = decodeResponse(last, config.NumberModeExact)
+	}
+	return itemsStub(n, item)
+}
@@ -0,0 +1,93 @@
+package core
+
+import (
+	"context"
+	"sync"
+
+	"github.com/saturnines/nexus-core/pkg/errors"
+	"github.com/saturnines/nexus-core/pkg/pagination"
+)
+
+// pageResult is the outcome of one planned page request.
+type pageResult struct {
+	page extractedPage
+	err  error
+}
+
+// fetchPlanned fetches planned pages with up to Pagination.Concurrency
+// requests in flight and hands them to deliver in page order. A page slot is
+// only freed once fn has consumed it, so at most Concurrency pages are held
+// in memory. Requests go through c.client, so retry and rate limit settings
+// apply to every worker. It reports whether deliver asked to stop.
+func (c *Connector) fetchPlanned(
+	ctx context.Context,
+	planned []pagination.PlannedRequest,
+	deliver func(extractedPage) (bool, error),
+) (bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]chan pageResult, len(planned))
+	for i := range results {
+		results[i] = make(chan pageResult, 1)
+	}
+
+	slots := make(chan struct{}, c.cfg.Pagination.Concurrency)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i, p := range planned {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+
+			wg.Add(1)
+			go func(i int, req *pagination.PlannedRequest) {
+				defer wg.Done()
+				page, err := c.fetchPlannedPage(ctx, req)
+				results[i] <- pageResult{page: page, err: err}
+			}(i, &p)
+		}
+	}()
+
+	for i, p := range planned {
+		var res pageResult
+		select {
+		case res = <-results[i]:
+		case <-ctx.Done():
+			return false, errors.WrapError(ctx.Err(), errors.ErrPagination, "context done")
+		}
+		<-slots
+
+		if res.err != nil {
+			return false, res.err
+		}
+		more, err := deliver(res.page)
+		if err != nil {
+			return false, err
+		}
+		if !more {
+			return true, nil
+		}
+		if c.stateStore != nil {
+			if err := c.stateStore.Save(ctx, c.stateKey(), p.State); err != nil {
+				return false, err
+			}
+		}
+	}
+	return false, nil
+}
+
+// fetchPlannedPage fetches and extracts a single planned page.
+func (c *Connector) fetchPlannedPage(ctx context.Context, p *pagination.PlannedRequest) (extractedPage, error) {
+	resp, body, err := c.fetchPage(p.Request.WithContext(ctx), false)
+	if err != nil {
+		return extractedPage{}, err
+	}
+	return c.extractFromBytes(resp, body)
+}
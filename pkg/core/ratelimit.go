@@ -0,0 +1,243 @@
+package core
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+)
+
+// defaultMaxWait caps pauses requested by rate limit headers.
+const defaultMaxWait = 5 * time.Minute
+
+// RateLimitTransport throttles requests with a token bucket and slows down
+// further when responses carry Retry-After, X-RateLimit-* or the IETF draft
+// RateLimit-* headers. It sits beneath RetryTransport, so every attempt,
+// including retries, waits for its turn.
+type RateLimitTransport struct {
+	Base http.RoundTripper
+	Cfg  *config.RateLimit
+
+	mu          sync.Mutex
+	rate        float64 // tokens per second, 0 means no fixed rate
+	burst       float64
+	tokens      float64
+	last        time.Time // last token refill
+	pausedUntil time.Time // set from Retry-After or an exhausted window
+	pace        time.Duration
+	paceUntil   time.Time // spread remaining requests until the window resets
+	lastSent    time.Time
+}
+
+// NewRateLimitTransport creates a new rate limit transport
+func NewRateLimitTransport(base http.RoundTripper, cfg *config.RateLimit) *RateLimitTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	rate := cfg.RequestsPerSecond
+	if rate == 0 && cfg.RequestsPerMinute > 0 {
+		rate = cfg.RequestsPerMinute / 60
+	}
+	burst := float64(cfg.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimitTransport{
+		Base:   base,
+		Cfg:    cfg,
+		rate:   rate,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+func (t *RateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for {
+		delay := t.reserve(time.Now())
+		if delay <= 0 {
+			break
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+
+	resp, err := t.Base.RoundTrip(req)
+	if err == nil && !t.Cfg.IgnoreHeaders {
+		t.observe(resp, time.Now())
+	}
+	return resp, err
+}
+
+// reserve takes a token if one is available and returns 0, or returns how
+// long to wait before trying again.
+func (t *RateLimitTransport) reserve(now time.Time) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if now.Before(t.pausedUntil) {
+		return t.pausedUntil.Sub(now)
+	}
+	if now.Before(t.paceUntil) {
+		if next := t.lastSent.Add(t.pace); now.Before(next) {
+			return next.Sub(now)
+		}
+	}
+
+	if t.rate > 0 {
+		t.tokens += now.Sub(t.last).Seconds() * t.rate
+		if t.tokens > t.burst {
+			t.tokens = t.burst
+		}
+		t.last = now
+		if t.tokens < 1 {
+			return time.Duration((1 - t.tokens) / t.rate * float64(time.Second))
+		}
+		t.tokens--
+	}
+
+	t.lastSent = now
+	return 0
+}
+
+// observe adapts to the rate limit headers of resp.
+func (t *RateLimitTransport) observe(resp *http.Response, now time.Time) {
+	h := resp.Header
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		if d, ok := parseRetryAfter(h.Get("Retry-After"), now); ok {
+			t.pauseFor(d, now)
+			return
+		}
+	}
+
+	remaining, reset, ok := parseRateLimitHeaders(h, now)
+	if !ok {
+		return
+	}
+	if remaining <= 0 {
+		t.pauseFor(reset, now)
+		return
+	}
+
+	// Spread what is left of the window evenly so we never hit zero.
+	pace := reset / time.Duration(remaining)
+	t.mu.Lock()
+	t.pace = pace
+	t.paceUntil = now.Add(t.capWait(reset))
+	t.mu.Unlock()
+}
+
+func (t *RateLimitTransport) pauseFor(d time.Duration, now time.Time) {
+	until := now.Add(t.capWait(d))
+	t.mu.Lock()
+	if until.After(t.pausedUntil) {
+		t.pausedUntil = until
+	}
+	t.mu.Unlock()
+}
+
+func (t *RateLimitTransport) capWait(d time.Duration) time.Duration {
+	return capWait(t.Cfg, d)
+}
+
+// capWait limits a server-requested pause to cfg.MaxWait, or defaultMaxWait
+// when rate limiting is not configured.
+func capWait(cfg *config.RateLimit, d time.Duration) time.Duration {
+	limit := defaultMaxWait
+	if cfg != nil && cfg.MaxWait > 0 {
+		limit = time.Duration(cfg.MaxWait * float64(time.Second))
+	}
+	if d > limit {
+		return limit
+	}
+	return d
+}
+
+// parseRetryAfter accepts delay seconds or an HTTP date.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.ParseFloat(v, 64); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs * float64(time.Second)), true
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		d := at.Sub(now)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// rateLimitParam matches r=/remaining= and t=/reset= in a structured
+// RateLimit header, e.g. `limit=100, remaining=0, reset=30` or
+// `"default";r=0;t=30`.
+var rateLimitParam = regexp.MustCompile(`(?i)\b(r|remaining|t|reset)\s*=\s*"?(\d+(?:\.\d+)?)`)
+
+// parseRateLimitHeaders reads the remaining quota and time until the window
+// resets from RateLimit-*, RateLimit or X-RateLimit-* headers.
+func parseRateLimitHeaders(h http.Header, now time.Time) (int, time.Duration, bool) {
+	remainingStr, resetStr := h.Get("RateLimit-Remaining"), h.Get("RateLimit-Reset")
+
+	if remainingStr == "" {
+		if combined := h.Get("RateLimit"); combined != "" {
+			for _, m := range rateLimitParam.FindAllStringSubmatch(combined, -1) {
+				switch strings.ToLower(m[1]) {
+				case "r", "remaining":
+					remainingStr = m[2]
+				case "t", "reset":
+					resetStr = m[2]
+				}
+			}
+		}
+	}
+	if remainingStr == "" {
+		remainingStr, resetStr = h.Get("X-RateLimit-Remaining"), h.Get("X-RateLimit-Reset")
+	}
+
+	remaining, err := strconv.Atoi(strings.TrimSpace(remainingStr))
+	if err != nil {
+		return 0, 0, false
+	}
+	reset, ok := parseReset(resetStr, now)
+	if !ok {
+		return 0, 0, false
+	}
+	return remaining, reset, true
+}
+
+// parseReset handles both delta seconds and Unix timestamps, which
+// X-RateLimit-Reset uses interchangeably across providers.
+func parseReset(v string, now time.Time) (time.Duration, bool) {
+	secs, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil || secs < 0 {
+		return 0, false
+	}
+	// Anything past 2001 as a Unix time is a timestamp, not a delay.
+	if secs > 1e9 {
+		d := time.Unix(int64(secs), 0).Sub(now)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return time.Duration(secs * float64(time.Second)), true
+}
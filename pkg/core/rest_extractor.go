@@ -1,25 +1,79 @@
 package core
 
 import (
-	"encoding/json"
 	"fmt"
 
 	"github.com/saturnines/nexus-core/pkg/config"
 	"github.com/saturnines/nexus-core/pkg/errors"
+	"github.com/saturnines/nexus-core/pkg/jsonpath"
+	"github.com/saturnines/nexus-core/pkg/transform"
 )
 
 type RestExtractor struct {
-	rootPath string
-	fields   []config.Field
+	rootPath   string
+	root       *jsonpath.Path // nil when rootPath is empty
+	rootNames  []string       // member names of root, when streaming
+	numberMode config.NumberMode
+	streaming  bool
+	format     config.ResponseFormat
+	csv        *csvFormat // nil unless format is csv or tsv
+	recordMapper
 }
 
-func NewRestExtractor(m config.ResponseMapping) *RestExtractor {
-	return &RestExtractor{rootPath: m.RootPath, fields: m.Fields}
+// NewRestExtractor compiles the field mappings in m. A nil registry uses
+// transform.DefaultRegistry.
+func NewRestExtractor(m config.ResponseMapping, registry *transform.Registry) (*RestExtractor, error) {
+	root, err := compileRootPath(m.RootPath)
+	if err != nil {
+		return nil, err
+	}
+	mapper, err := newRecordMapper(m, registry)
+	if err != nil {
+		return nil, err
+	}
+	e := &RestExtractor{
+		rootPath:     m.RootPath,
+		root:         root,
+		numberMode:   m.NumberMode,
+		streaming:    m.Streaming,
+		format:       m.ResponseFormat,
+		recordMapper: mapper,
+	}
+
+	if m.ResponseFormat == config.ResponseFormatCSV || m.ResponseFormat == config.ResponseFormatTSV {
+		if e.csv, err = newCSVFormat(m, mapper.fields); err != nil {
+			return nil, err
+		}
+	}
+
+	// The streaming decoder can only follow object members to the items.
+	if m.Streaming && root != nil {
+		names, ok := root.Names()
+		if !ok {
+			return nil, errors.WrapError(
+				fmt.Errorf("root path %q must only name object members, e.g. result.items", m.RootPath),
+				errors.ErrConfiguration,
+				"streaming root path",
+			)
+		}
+		e.rootNames = names
+	}
+	return e, nil
 }
 
 func (e *RestExtractor) Items(raw []byte) ([]interface{}, error) {
-	var responseData interface{}
-	if err := json.Unmarshal(raw, &responseData); err != nil {
+	if e.lineDelimited() {
+		items, _ := e.decodeLines(raw)
+		for _, item := range items {
+			if bad, ok := item.(badLine); ok {
+				return nil, bad.error()
+			}
+		}
+		return items, nil
+	}
+
+	responseData, err := decodeResponse(raw, e.numberMode)
+	if err != nil {
 		return nil, errors.WrapError(err, errors.ErrHTTPResponse, "failed to decode response JSON")
 	}
 
@@ -47,7 +101,7 @@ func (e *RestExtractor) Items(raw []byte) ([]interface{}, error) {
 
 func (e *RestExtractor) extractItems(responseData map[string]interface{}) ([]interface{}, error) {
 	rp := e.rootPath
-	if rp == "" {
+	if e.root == nil {
 		if items, ok := responseData["items"].([]interface{}); ok {
 			return items, nil
 		}
@@ -57,7 +111,14 @@ func (e *RestExtractor) extractItems(responseData map[string]interface{}) ([]int
 		return []interface{}{responseData}, nil
 	}
 
-	root, ok := ExtractFieldEnhanced(responseData, rp)
+	// A wildcard, filter or descent root selects the items themselves and
+	// may legitimately match nothing. Legacy wildcard roots such as
+	// "data.items[*]" resolve as they always have.
+	if !e.root.Definite() && !e.root.Legacy() {
+		return e.root.Find(responseData), nil
+	}
+
+	root, ok := e.root.Get(responseData)
 	if !ok {
 		return nil, errors.WrapError(
 			fmt.Errorf("root path '%s' not found", rp),
@@ -78,21 +139,20 @@ func (e *RestExtractor) extractItems(responseData map[string]interface{}) ([]int
 	return items, nil
 }
 
+// Map maps a single item to one record. Exploded records are built by the
+// connector, which maps items through mapRecords.
 func (e *RestExtractor) Map(item interface{}) (map[string]interface{}, error) {
-	// Don't validate here - let connector handle it
-	mapped := make(map[string]interface{})
-	for _, field := range e.fields {
-		value, ok := ExtractFieldEnhanced(item, field.Path)
-
-		// Check if field is missing OR null
-		if !ok || value == nil {
-			if field.DefaultValue != nil {
-				mapped[field.Name] = field.DefaultValue
-			}
-			continue
-		}
+	return e.mapItem(item)
+}
 
-		mapped[field.Name] = value
+// compileRootPath compiles a response root path; an empty path yields nil.
+func compileRootPath(rp string) (*jsonpath.Path, error) {
+	if rp == "" {
+		return nil, nil
+	}
+	root, err := jsonpath.Compile(rp)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrConfiguration, "compile root path")
 	}
-	return mapped, nil
+	return root, nil
 }
@@ -2,32 +2,64 @@ package core
 
 import (
 	"bytes"
-	"errors"
+	"context"
+	crand "crypto/rand"
+	"encoding/json"
 	"fmt"
 	"io"
 	"math"
 	"math/rand"
 	"net"
 	"net/http"
+	"regexp"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/errors"
 )
 
-// HTTPError wraps HTTP error responses
-type HTTPError struct {
-	StatusCode int
-	Status     string
+// HTTPError is the error RetryTransport returns for non-retryable error
+// responses.
+//
+// Deprecated: use *errors.APIError, which HTTPError is an alias of.
+type HTTPError = errors.APIError
+
+// attemptsKey holds the *int32 that counts attempts for one logical request.
+type attemptsKey struct{}
+
+// withAttemptCounter returns a context in which every attempt sent for a
+// request, including RetryTransport retries, is counted.
+func withAttemptCounter(ctx context.Context) (context.Context, *int32) {
+	if n, ok := ctx.Value(attemptsKey{}).(*int32); ok {
+		return ctx, n
+	}
+	n := new(int32)
+	return context.WithValue(ctx, attemptsKey{}, n), n
 }
 
-func (e *HTTPError) Error() string {
-	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Status)
+// countAttempt records one more attempt and returns the running total, or
+// fallback when ctx has no counter.
+func countAttempt(ctx context.Context, fallback int) int {
+	n, ok := ctx.Value(attemptsKey{}).(*int32)
+	if !ok {
+		return fallback
+	}
+	return int(atomic.AddInt32(n, 1))
+}
+
+// attemptCount returns the attempts recorded in ctx so far.
+func attemptCount(ctx context.Context) int {
+	if n, ok := ctx.Value(attemptsKey{}).(*int32); ok {
+		return int(atomic.LoadInt32(n))
+	}
+	return 0
 }
 
 type RetryTransport struct {
-	Base   http.RoundTripper
-	Cfg    *config.RetryConfig
-	jitter *rand.Rand // Localized jitter source
+	Base http.RoundTripper
+	Cfg  *config.RetryConfig
 }
 
 // NewRetryTransport creates a new retry transport
@@ -36,9 +68,8 @@ func NewRetryTransport(base http.RoundTripper, cfg *config.RetryConfig) *RetryTr
 		base = http.DefaultTransport
 	}
 	return &RetryTransport{
-		Base:   base,
-		Cfg:    cfg,
-		jitter: rand.New(rand.NewSource(time.Now().UnixNano())),
+		Base: base,
+		Cfg:  cfg,
 	}
 }
 
@@ -47,12 +78,8 @@ func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 		return t.Base.RoundTrip(req)
 	}
 
-	switch req.Method {
-	case http.MethodGet, http.MethodHead,
-		http.MethodPut, http.MethodDelete,
-		http.MethodOptions, http.MethodTrace:
-		// retryable for idempotency methods
-	default:
+	req, ok := t.prepareRetry(req)
+	if !ok {
 		return t.Base.RoundTrip(req)
 	}
 
@@ -64,6 +91,14 @@ func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 		// A lazy way in theory could be just clone if body of http request is "small"
 		req2 := t.cloneRequest(req)
 
+		// The caller counts the first attempt; count the retries.
+		attemptNo := attempt + 1
+		if attempt > 0 {
+			attemptNo = countAttempt(req.Context(), attemptNo)
+		} else if n := attemptCount(req.Context()); n > 0 {
+			attemptNo = n
+		}
+
 		resp, err := t.Base.RoundTrip(req2)
 
 		// Network error handling with proper interface detection
@@ -93,10 +128,14 @@ func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 				}
 				// Return HTTP error for 4xx/5xx that aren't retryable, this should cover 99% of cases
 				if resp.StatusCode >= 400 {
-					return nil, &HTTPError{
-						StatusCode: resp.StatusCode,
-						Status:     resp.Status,
+					body, _ := io.ReadAll(io.LimitReader(resp.Body, errors.MaxErrorBody+1))
+					resp.Body.Close()
+					if resp.Request == nil {
+						resp.Request = req2
 					}
+					apiErr := errors.NewResponseError(resp, body)
+					apiErr.Attempt = attemptNo
+					return nil, apiErr
 				}
 				return resp, nil
 			}
@@ -145,6 +184,128 @@ func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	return nil, fmt.Errorf("retry transport failed after %d attempts: no response received", t.Cfg.MaxAttempts)
 }
 
+// idempotentMethods are retried when RetryConfig.RetryMethods is empty.
+var idempotentMethods = []string{
+	http.MethodGet, http.MethodHead,
+	http.MethodPut, http.MethodDelete,
+	http.MethodOptions, http.MethodTrace,
+}
+
+// prepareRetry reports whether req may be retried. A method is retryable if
+// it is allowed by RetryMethods (or is idempotent), if RetryNonIdempotent is
+// set, if the request is a GraphQL query rather than a mutation, or if it
+// carries an Idempotency-Key. With IdempotencyKey enabled, other POSTs get a
+// generated key first, so every attempt shares it.
+func (t *RetryTransport) prepareRetry(req *http.Request) (*http.Request, bool) {
+	if t.Cfg.RetryNonIdempotent {
+		return req, true
+	}
+
+	methods := t.Cfg.RetryMethods
+	if len(methods) == 0 {
+		methods = idempotentMethods
+	}
+	for _, m := range methods {
+		if strings.EqualFold(m, req.Method) {
+			return req, true
+		}
+	}
+
+	if req.Method != http.MethodPost {
+		return req, false
+	}
+	if req.Header.Get("Idempotency-Key") != "" {
+		return req, true
+	}
+
+	op, isGraphQL := graphQLOperation(req)
+	if isGraphQL {
+		return req, op == "query"
+	}
+
+	if t.Cfg.IdempotencyKey {
+		key, err := newIdempotencyKey()
+		if err != nil {
+			return req, false
+		}
+		// Don't modify the caller's request.
+		req = req.Clone(req.Context())
+		req.Header.Set("Idempotency-Key", key)
+		return req, true
+	}
+	return req, false
+}
+
+// graphQLComment strips # comments; graphQLMutation finds a mutation
+// operation at the start of the document or after another definition.
+var (
+	graphQLComment  = regexp.MustCompile(`#[^\n]*`)
+	graphQLMutation = regexp.MustCompile(`(?:^|\})\s*mutation\b`)
+	graphQLSubscr   = regexp.MustCompile(`(?:^|\})\s*subscription\b`)
+)
+
+// graphQLOperation inspects a JSON POST body. It returns the operation type
+// ("query", "mutation" or "subscription") and whether the body is a GraphQL
+// request at all. Documents mixing queries and mutations count as mutations.
+func graphQLOperation(req *http.Request) (string, bool) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return "", false
+	}
+	if ct := req.Header.Get("Content-Type"); ct != "" && !strings.Contains(ct, "json") {
+		return "", false
+	}
+
+	body, err := peekBody(req)
+	if err != nil {
+		return "", false
+	}
+	var payload struct {
+		Query *string `json:"query"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Query == nil {
+		return "", false
+	}
+
+	doc := graphQLComment.ReplaceAllString(*payload.Query, "")
+	switch {
+	case graphQLMutation.MatchString(doc):
+		return "mutation", true
+	case graphQLSubscr.MatchString(doc):
+		return "subscription", true
+	default:
+		return "query", true
+	}
+}
+
+// peekBody reads req's body and replaces it so it can be sent afterwards.
+func peekBody(req *http.Request) ([]byte, error) {
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+	buf, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(buf))
+	return buf, nil
+}
+
+// newIdempotencyKey returns a random UUIDv4.
+func newIdempotencyKey() (string, error) {
+	var b [16]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
 // cloneRequest makes a deep copy for safe body reuse
 func (t *RetryTransport) cloneRequest(r *http.Request) *http.Request {
 	r2 := r.Clone(r.Context())
@@ -156,13 +317,20 @@ func (t *RetryTransport) cloneRequest(r *http.Request) *http.Request {
 	return r2
 }
 
-// backoff computes full jitter exponential backoff
+// backoff computes full jitter exponential backoff. The transport is shared
+// by concurrent page and child fetches, so jitter comes from the
+// goroutine-safe global source.
 func (t *RetryTransport) backoff(attempt int) time.Duration {
+	return backoffDelay(t.Cfg, attempt, rand.Float64())
+}
+
+// backoffDelay scales the exponential delay for attempt by jitter, a value in [0, 1).
+func backoffDelay(cfg *config.RetryConfig, attempt int, jitter float64) time.Duration {
 	// Convert seconds to time.Duration
-	base := time.Duration(t.Cfg.InitialBackoff * float64(time.Second))
+	base := time.Duration(cfg.InitialBackoff * float64(time.Second))
 
 	// Calculate max delay for this attempt
-	maxDelay := time.Duration(float64(base) * math.Pow(t.Cfg.BackoffMultiplier, float64(attempt)))
+	maxDelay := time.Duration(float64(base) * math.Pow(cfg.BackoffMultiplier, float64(attempt)))
 
 	// Cap at 30 seconds
 	if maxDelay > 30*time.Second {
@@ -170,7 +338,7 @@ func (t *RetryTransport) backoff(attempt int) time.Duration {
 	}
 
 	// Full jitter: random duration between 0 and max
-	return time.Duration(t.jitter.Float64() * float64(maxDelay))
+	return time.Duration(jitter * float64(maxDelay))
 }
 
 // contains checks if slice contains value
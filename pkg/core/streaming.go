@@ -0,0 +1,413 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/errors"
+	"github.com/saturnines/nexus-core/pkg/pagination"
+)
+
+// streamBatchSize is how many streamed items are mapped and delivered
+// together.
+const streamBatchSize = 500
+
+// errStopStream aborts decoding once the consumer stops or fails.
+var errStopStream = stderrors.New("stream stopped")
+
+// jsonStream decodes a JSON document token by token. The elements of the
+// items array are handed to emit one at a time instead of being kept; the
+// rest of the document is decoded normally.
+type jsonStream struct {
+	dec   *json.Decoder
+	path  []string // member names leading to the items array
+	auto  bool     // no root path: a top-level "items" or "data" array holds the items
+	emit  func(item interface{}) error
+	check func(partial interface{}) error // called before the first item
+	found bool                            // the items array was streamed
+	open  []map[string]interface{}        // objects on the path being decoded
+}
+
+// decode reads the whole document. In the returned skeleton the items array
+// keeps only its length and last element, so pagers can still count the
+// items or read a cursor from "data[-1].id".
+func (s *jsonStream) decode() (interface{}, error) {
+	doc, err := s.value(s.path, true, true)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after top-level value")
+	}
+	return doc, nil
+}
+
+// value decodes the next value. While onPath is true, rest holds the member
+// names still to follow to the items array.
+func (s *jsonStream) value(rest []string, onPath, top bool) (interface{}, error) {
+	if !onPath {
+		var v interface{}
+		err := s.dec.Decode(&v)
+		return v, err
+	}
+
+	tok, err := s.dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		obj := make(map[string]interface{})
+		s.open = append(s.open, obj)
+		for s.dec.More() {
+			kt, err := s.dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key := kt.(string)
+
+			var v interface{}
+			switch {
+			case s.found:
+				v, err = s.value(nil, false, false)
+			case s.auto:
+				v, err = s.value(nil, top && (key == "items" || key == "data"), false)
+			case len(rest) > 0 && key == rest[0]:
+				v, err = s.value(rest[1:], true, false)
+			default:
+				v, err = s.value(nil, false, false)
+			}
+			if err != nil {
+				return nil, err
+			}
+			obj[key] = v
+		}
+		if _, err := s.dec.Token(); err != nil {
+			return nil, err
+		}
+		s.open = s.open[:len(s.open)-1]
+		return obj, nil
+
+	case json.Delim('['):
+		// A top-level array holds the items whatever the root path, as in
+		// RestExtractor.Items.
+		if top || len(rest) == 0 {
+			return s.items()
+		}
+		arr := []interface{}{}
+		for s.dec.More() {
+			var v interface{}
+			if err := s.dec.Decode(&v); err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		if _, err := s.dec.Token(); err != nil {
+			return nil, err
+		}
+		return arr, nil
+	}
+	return tok, nil
+}
+
+// items streams the elements of the array whose '[' was just read.
+func (s *jsonStream) items() (interface{}, error) {
+	s.found = true
+	if s.check != nil {
+		if err := s.check(s.partial()); err != nil {
+			return nil, err
+		}
+	}
+	n := 0
+	var last interface{}
+	for s.dec.More() {
+		var v interface{}
+		if err := s.dec.Decode(&v); err != nil {
+			return nil, err
+		}
+		if err := s.emit(v); err != nil {
+			return nil, err
+		}
+		n++
+		last = v
+	}
+	if _, err := s.dec.Token(); err != nil {
+		return nil, err
+	}
+	return itemsStub(n, last), nil
+}
+
+// partial returns the document decoded so far: the members read before the
+// items array, at every level on the way to it.
+func (s *jsonStream) partial() interface{} {
+	if len(s.open) == 0 {
+		return nil
+	}
+	// Each open object is the value of the next path name in its parent.
+	for i := 1; i < len(s.open); i++ {
+		s.open[i-1][s.path[i-1]] = s.open[i]
+	}
+	return s.open[0]
+}
+
+// streaming reports whether responses are decoded incrementally.
+func (c *Connector) streaming() bool {
+	e, ok := c.extractor.(*RestExtractor)
+	return ok && e.streaming
+}
+
+// fetchStream sends req like fetch but leaves the body of a 200 response
+// unread. Other responses are buffered for the status error. Errors
+// reported in the body are checked by streamPage and retried by
+// streamRetry.
+func (c *Connector) fetchStream(req *http.Request) (*http.Response, []byte, error) {
+	req, err := rewind(req)
+	if err != nil {
+		return nil, nil, errors.WrapError(err, errors.ErrHTTPRequest, "rewind request body")
+	}
+	ctx, _ := withAttemptCounter(req.Context())
+	req = req.WithContext(ctx)
+	countAttempt(ctx, 0)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, nil, doError(err)
+	}
+	if resp.StatusCode == http.StatusOK {
+		return resp, nil, nil
+	}
+	body, err := readAndBuffer(resp)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, body, nil
+}
+
+// streamRetry streams resp, the response to req, through handle with
+// streamPage. When the body reports a retryable API error before any item
+// was handed to handle, req is sent again per RetryConfig, as fetch does
+// for buffered bodies.
+func (c *Connector) streamRetry(req *http.Request, resp *http.Response, handle func(extractedPage) (bool, error)) (*http.Response, bool, error) {
+	delivered := false
+	counted := func(page extractedPage) (bool, error) {
+		delivered = true
+		return handle(page)
+	}
+
+	for attempt := 0; ; attempt++ {
+		decoded, stopped, err := c.streamPage(resp, counted)
+		retry := c.cfg.RetryConfig
+		var apiErr *errors.APIError
+		if delivered || retry == nil || attempt >= retry.MaxAttempts-1 ||
+			!errors.As(err, &apiErr) || !apiErr.Retryable {
+			return decoded, stopped, err
+		}
+
+		// The client cancels the sent request's context once its body is
+		// closed, so only its attempt counter carries over.
+		if n, ok := resp.Request.Context().Value(attemptsKey{}).(*int32); ok {
+			req = req.WithContext(context.WithValue(req.Context(), attemptsKey{}, n))
+		}
+		select {
+		case <-req.Context().Done():
+			return nil, false, errors.WrapError(req.Context().Err(), errors.ErrHTTPRequest, "retry API error")
+		case <-time.After(backoffDelay(retry, attempt, rand.Float64())):
+		}
+
+		if req, err = cloneForRetry(req); err != nil {
+			return nil, false, errors.WrapError(err, errors.ErrHTTPRequest, "clone request for retry")
+		}
+		if resp, _, err = c.fetchPage(req, true); err != nil {
+			return nil, false, err
+		}
+	}
+}
+
+// streamPage decodes the body of resp, mapping items in batches of
+// streamBatchSize and handing each batch to handle. It returns resp with
+// the decoded skeleton as its body, for the pager, and whether handle asked
+// to stop. Line-delimited bodies are read one line at a time.
+//
+// ErrorPath and SuccessPath are checked against the members that precede
+// the items array before any item is mapped, and against the whole
+// document once it has been read.
+func (c *Connector) streamPage(resp *http.Response, handle func(extractedPage) (bool, error)) (*http.Response, bool, error) {
+	defer resp.Body.Close()
+
+	e := c.extractor.(*RestExtractor)
+	var batch []interface{}
+	var lines []int // line numbers of batch, for line-delimited bodies
+	offset := 0
+	stopped := false
+	var handleErr error
+
+	flush := func(final bool) error {
+		if len(batch) == 0 && !final {
+			return nil
+		}
+		page, err := c.extractItems(batch, offset, lines)
+		page.partial = !final
+		if err == nil {
+			var more bool
+			more, err = handle(page)
+			stopped = !more
+		}
+		offset += len(batch)
+		batch = batch[:0]
+		if lines != nil {
+			lines = lines[:0]
+		}
+		if err != nil {
+			handleErr = err
+			return errStopStream
+		}
+		if stopped {
+			return errStopStream
+		}
+		return nil
+	}
+
+	// The skeleton keeps json.Number so numeric cursors round-trip
+	// exactly; items are converted when the number mode is float.
+	emit := func(item interface{}) error {
+		if e.numberMode != config.NumberModeExact {
+			item = numbersToFloat(item)
+		}
+		batch = append(batch, item)
+		if len(batch) < streamBatchSize {
+			return nil
+		}
+		return flush(false)
+	}
+
+	if e.lineDelimited() {
+		lines = []int{}
+		n := 0
+		var last interface{}
+		err := e.readLines(resp.Body, config.NumberModeExact, func(item interface{}, line int) error {
+			n++
+			if _, bad := item.(badLine); !bad {
+				last = item
+			}
+			lines = append(lines, line)
+			return emit(item)
+		})
+		if err == errStopStream {
+			return nil, stopped, handleErr
+		}
+		if err != nil {
+			return nil, false, errors.WrapError(err, errors.ErrHTTPResponse, "read response body")
+		}
+		if err := flush(true); err != nil {
+			return nil, stopped, handleErr
+		}
+		return c.createResponse(resp, pagination.NewDecodedBody(e.lineStub(n, last))), false, nil
+	}
+
+	dec := json.NewDecoder(resp.Body)
+	dec.UseNumber()
+	var earlyErr *errors.APIError
+	check := func(partial interface{}) error {
+		// Without the success member yet, its value could still overrule
+		// the error path.
+		if m := c.responseMapping(); m.SuccessPath != "" {
+			if _, ok := ExtractFieldEnhanced(partial, m.SuccessPath); !ok {
+				return nil
+			}
+		}
+		if earlyErr = c.checkAPIErrorDoc(partial); earlyErr != nil {
+			return errStopStream
+		}
+		return nil
+	}
+	s := &jsonStream{dec: dec, path: e.rootNames, auto: e.root == nil, emit: emit, check: check}
+
+	doc, err := s.decode()
+	if err == errStopStream && earlyErr != nil {
+		annotateAPIError(earlyErr, resp, nil)
+		return nil, false, earlyErr
+	}
+	if err == errStopStream {
+		return nil, stopped, handleErr
+	}
+	if err != nil {
+		return nil, false, errors.WrapError(err, errors.ErrHTTPResponse, "failed to decode response JSON")
+	}
+
+	if apiErr := c.checkAPIErrorDoc(doc); apiErr != nil {
+		annotateAPIError(apiErr, resp, nil)
+		return nil, false, apiErr
+	}
+
+	if !s.found && doc != nil {
+		if err := e.fallbackItem(doc, s.emit); err == errStopStream {
+			return nil, stopped, handleErr
+		} else if err != nil {
+			return nil, false, err
+		}
+	}
+	if err := flush(true); err != nil {
+		return nil, stopped, handleErr
+	}
+
+	return c.createResponse(resp, pagination.NewDecodedBody(doc)), false, nil
+}
+
+// fallbackItem handles a document whose items array was not streamed, as
+// RestExtractor.Items does for buffered bodies: without a root path the
+// object itself is the only item, otherwise the root path is wrong.
+func (e *RestExtractor) fallbackItem(doc interface{}, emit func(interface{}) error) error {
+	if _, ok := doc.(map[string]interface{}); !ok {
+		return errors.WrapError(
+			fmt.Errorf("unexpected response format: %T", doc),
+			errors.ErrHTTPResponse,
+			"parse response data",
+		)
+	}
+	if e.root == nil {
+		return emit(doc)
+	}
+	if _, ok := e.root.Get(doc); !ok {
+		return errors.WrapError(
+			fmt.Errorf("root path '%s' not found", e.rootPath),
+			errors.ErrExtraction,
+			"find root path",
+		)
+	}
+	return errors.WrapError(
+		fmt.Errorf("root path '%s' is not an array", e.rootPath),
+		errors.ErrExtraction,
+		"validate root path type",
+	)
+}
+
+// numbersToFloat returns a copy of v with json.Number values replaced by
+// float64, as encoding/json decodes them without UseNumber.
+func numbersToFloat(v interface{}) interface{} {
+	switch x := v.(type) {
+	case json.Number:
+		if f, err := x.Float64(); err == nil {
+			return f
+		}
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(x))
+		for k, val := range x {
+			out[k] = numbersToFloat(val)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(x))
+		for i, val := range x {
+			out[i] = numbersToFloat(val)
+		}
+		return out
+	}
+	return v
+}
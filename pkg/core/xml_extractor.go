@@ -0,0 +1,248 @@
+package core
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/errors"
+	"github.com/saturnines/nexus-core/pkg/jsonpath"
+	"github.com/saturnines/nexus-core/pkg/transform"
+)
+
+// xsiNamespace holds the xsi:nil attribute that marks null elements.
+const xsiNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+
+// XMLExtractor reads items from XML bodies. The document is converted to
+// the maps and arrays ExtractFieldEnhanced works on, so root_path and
+// field paths select elements and attributes as they would JSON members:
+//
+//	<Order id="7"><Line sku="A"/><Line sku="B"/><Note>rush</Note></Order>
+//
+// becomes {"Order": {"@id": "7", "Line": [{"@sku": "A"}, {"@sku": "B"}], "Note": "rush"}}.
+// Element text is kept as a string; Field.Type converts it.
+type XMLExtractor struct {
+	rootPath   string
+	root       *jsonpath.Path // nil when rootPath is empty
+	rootParent *jsonpath.Path // parent of a plain root path, nil otherwise
+	attrPrefix string
+	textKey    string
+	prefixes   map[string]string // namespace URI to configured prefix
+	arrays     map[string]bool
+	recordMapper
+}
+
+// NewXMLExtractor compiles the field mappings in m. A nil registry uses
+// transform.DefaultRegistry.
+func NewXMLExtractor(m config.ResponseMapping, registry *transform.Registry) (*XMLExtractor, error) {
+	root, err := compileRootPath(m.RootPath)
+	if err != nil {
+		return nil, err
+	}
+	mapper, err := newRecordMapper(m, registry)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts config.XMLFormat
+	if m.XML != nil {
+		opts = *m.XML
+	}
+	e := &XMLExtractor{
+		rootPath:     m.RootPath,
+		root:         root,
+		attrPrefix:   opts.AttributePrefix,
+		textKey:      opts.TextKey,
+		prefixes:     make(map[string]string, len(opts.Namespaces)),
+		arrays:       make(map[string]bool, len(opts.Arrays)),
+		recordMapper: mapper,
+	}
+	if e.attrPrefix == "" {
+		e.attrPrefix = "@"
+	}
+	if e.textKey == "" {
+		e.textKey = "#text"
+	}
+	for prefix, uri := range opts.Namespaces {
+		e.prefixes[uri] = prefix
+	}
+	for _, name := range opts.Arrays {
+		e.arrays[name] = true
+	}
+
+	// XML leaves out empty lists, so a missing root is only an error when
+	// its parent element is missing too.
+	if root != nil {
+		if names, ok := root.Names(); ok && len(names) > 1 {
+			parent := "['" + strings.Join(names[:len(names)-1], "']['") + "']"
+			if e.rootParent, err = jsonpath.Compile(parent); err != nil {
+				return nil, errors.WrapError(err, errors.ErrConfiguration, "compile root path")
+			}
+		}
+	}
+	return e, nil
+}
+
+// Items decodes raw and returns the elements at the root path. A single
+// element is returned as a one-item list. Without a root path the whole
+// document is the only item.
+func (e *XMLExtractor) Items(raw []byte) ([]interface{}, error) {
+	doc, err := e.decode(raw)
+	if err != nil {
+		return nil, errors.WrapError(err, errors.ErrHTTPResponse, "failed to decode response XML")
+	}
+	return e.items(doc)
+}
+
+// items returns the elements at the root path of a decoded document.
+func (e *XMLExtractor) items(doc interface{}) ([]interface{}, error) {
+	if e.root == nil {
+		return []interface{}{doc}, nil
+	}
+
+	if !e.root.Definite() {
+		var items []interface{}
+		for _, v := range e.root.Find(doc) {
+			items = appendItems(items, v)
+		}
+		return items, nil
+	}
+
+	v, ok := e.root.Get(doc)
+	if !ok {
+		if e.rootParent != nil {
+			if _, ok := e.rootParent.Get(doc); ok {
+				return []interface{}{}, nil
+			}
+		}
+		return nil, errors.WrapError(
+			fmt.Errorf("root path '%s' not found", e.rootPath),
+			errors.ErrExtraction,
+			"find root path",
+		)
+	}
+	return appendItems(nil, v), nil
+}
+
+// appendItems appends v, or the elements of v when it is a list.
+func appendItems(items []interface{}, v interface{}) []interface{} {
+	if arr, ok := v.([]interface{}); ok {
+		return append(items, arr...)
+	}
+	return append(items, v)
+}
+
+// Map maps a single item to one record. Exploded records are built by the
+// connector, which maps items through mapRecords.
+func (e *XMLExtractor) Map(item interface{}) (map[string]interface{}, error) {
+	return e.mapItem(item)
+}
+
+// decode converts an XML document to a map holding its root element.
+func (e *XMLExtractor) decode(raw []byte) (interface{}, error) {
+	dec := xml.NewDecoder(bytes.NewReader(raw))
+	dec.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		// DecodingTransport has already converted bodies whose Content-Type
+		// names the charset; only convert ones that are not UTF-8 yet.
+		if utf8.Valid(raw) {
+			return input, nil
+		}
+		enc := lookupCharset(label)
+		if enc == nil {
+			return nil, fmt.Errorf("unsupported charset %q", label)
+		}
+		return enc.NewDecoder().Reader(input), nil
+	}
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil, fmt.Errorf("no root element")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			v, err := e.element(dec, start)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{e.name(start.Name): v}, nil
+		}
+	}
+}
+
+// element converts the element opened by start. An element with neither
+// attributes nor children becomes its text; one marked xsi:nil becomes nil.
+func (e *XMLExtractor) element(dec *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	obj := make(map[string]interface{})
+	isNil := false
+	for _, a := range start.Attr {
+		switch {
+		case a.Name.Space == "xmlns" || a.Name.Space == "" && a.Name.Local == "xmlns":
+			// Namespace declarations are resolved by the decoder.
+		case a.Name.Space == xsiNamespace && a.Name.Local == "nil":
+			isNil = a.Value == "true" || a.Value == "1"
+		default:
+			obj[e.attrPrefix+e.name(a.Name)] = a.Value
+		}
+	}
+
+	var text strings.Builder
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			v, err := e.element(dec, t)
+			if err != nil {
+				return nil, err
+			}
+			e.add(obj, e.name(t.Name), v)
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			switch {
+			case isNil:
+				return nil, nil
+			case len(obj) == 0:
+				return text.String(), nil
+			}
+			if s := strings.TrimSpace(text.String()); s != "" {
+				obj[e.textKey] = s
+			}
+			return obj, nil
+		}
+	}
+}
+
+// add stores a child element, turning repeated names into a list.
+func (e *XMLExtractor) add(obj map[string]interface{}, key string, v interface{}) {
+	prev, seen := obj[key]
+	switch {
+	case !seen && e.arrays[key]:
+		obj[key] = []interface{}{v}
+	case !seen:
+		obj[key] = v
+	default:
+		if arr, ok := prev.([]interface{}); ok {
+			obj[key] = append(arr, v)
+		} else {
+			obj[key] = []interface{}{prev, v}
+		}
+	}
+}
+
+// name returns the key for an element or attribute: its local name, with
+// the configured prefix when its namespace has one.
+func (e *XMLExtractor) name(n xml.Name) string {
+	if prefix, ok := e.prefixes[n.Space]; ok && n.Space != "" {
+		return prefix + ":" + n.Local
+	}
+	return n.Local
+}
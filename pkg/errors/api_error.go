@@ -0,0 +1,187 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxErrorBody is how much of a response body an APIError keeps.
+const MaxErrorBody = 1024
+
+// requestIDHeaders are checked in order for a provider request ID.
+var requestIDHeaders = []string{
+	"X-Request-Id",
+	"Request-Id",
+	"X-Correlation-Id",
+	"X-Amzn-RequestId",
+	"X-Amz-Request-Id",
+	"X-GitHub-Request-Id",
+	"X-Shopify-Request-Id",
+	"Cf-Ray",
+}
+
+// sensitiveParams are substrings of query parameter names whose values are
+// redacted from error URLs.
+var sensitiveParams = []string{
+	"token", "key", "secret", "password", "passwd", "signature", "sig", "auth", "credential", "session",
+}
+
+// APIError describes a failed API call. It covers both error status codes
+// and errors the API reported in the body of a successful HTTP response,
+// e.g. Slack's {"ok": false, "error": "ratelimited"}. It matches ErrAPI
+// with errors.Is and unwraps to Err, if set.
+type APIError struct {
+	StatusCode int    // HTTP status code, 0 if unknown
+	Status     string // HTTP status text, e.g. "404 Not Found"
+	Method     string
+	URL        string // Request URL with credentials and secret parameters redacted
+	Body       string // Response body, truncated to MaxErrorBody bytes
+	RequestID  string // Provider request ID header, if any
+	Attempt    int    // 1-based attempt that produced the error, 0 if unknown
+
+	Message   string // Value found at the configured error path
+	Code      string // Error code used for retry decisions
+	Retryable bool   // Whether retrying the request may succeed
+	Err       error  // Underlying cause, if any
+}
+
+// NewResponseError builds an APIError from a response and its body. The
+// request method and URL are taken from resp.Request when present.
+func NewResponseError(resp *http.Response, body []byte) *APIError {
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       truncateBody(body),
+		RequestID:  requestID(resp.Header),
+	}
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		e.URL = RedactURL(resp.Request.URL)
+	}
+	return e
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	if e.Message != "" {
+		fmt.Fprintf(&b, "%v: %s", ErrAPI, e.Message)
+		if e.Code != "" && e.Code != e.Message {
+			fmt.Fprintf(&b, " (code: %s)", e.Code)
+		}
+	} else {
+		fmt.Fprintf(&b, "API returned status %d", e.StatusCode)
+	}
+
+	var details []string
+	if e.Method != "" || e.URL != "" {
+		details = append(details, strings.TrimSpace(e.Method+" "+e.URL))
+	}
+	if e.Attempt > 0 {
+		details = append(details, fmt.Sprintf("attempt %d", e.Attempt))
+	}
+	if e.RequestID != "" {
+		details = append(details, "request id "+e.RequestID)
+	}
+	if len(details) > 0 {
+		fmt.Fprintf(&b, " [%s]", strings.Join(details, ", "))
+	}
+
+	if e.Message == "" && e.Body != "" {
+		fmt.Fprintf(&b, ": %s", e.Body)
+	}
+	if e.Err != nil {
+		fmt.Fprintf(&b, ": %v", e.Err)
+	}
+	return b.String()
+}
+
+// Is reports whether target is the ErrAPI category.
+func (e *APIError) Is(target error) bool {
+	return target == ErrAPI
+}
+
+// Unwrap returns the underlying cause.
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// IsRetryable reports whether err, or any error it wraps, is an APIError
+// marked retryable or a rate limit error.
+func IsRetryable(err error) bool {
+	var apiErr *APIError
+	if As(err, &apiErr) {
+		return apiErr.Retryable
+	}
+	return Is(err, ErrRateLimited)
+}
+
+// StatusCode returns the HTTP status code of the first APIError in err's
+// chain, or 0 if there is none.
+func StatusCode(err error) int {
+	var apiErr *APIError
+	if As(err, &apiErr) {
+		return apiErr.StatusCode
+	}
+	return 0
+}
+
+// RedactURL formats u without its password and with the values of
+// credential-like query parameters replaced by "REDACTED".
+func RedactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	r := *u
+	if r.User != nil {
+		if _, ok := r.User.Password(); ok {
+			r.User = url.UserPassword(r.User.Username(), "REDACTED")
+		}
+	}
+
+	q := r.Query()
+	redacted := false
+	for name := range q {
+		if isSensitiveParam(name) {
+			q.Set(name, "REDACTED")
+			redacted = true
+		}
+	}
+	if redacted {
+		r.RawQuery = q.Encode()
+	}
+	return r.String()
+}
+
+func isSensitiveParam(name string) bool {
+	name = strings.ToLower(name)
+	for _, s := range sensitiveParams {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func requestID(h http.Header) string {
+	for _, name := range requestIDHeaders {
+		if v := h.Get(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func truncateBody(body []byte) string {
+	s := strings.TrimSpace(string(body))
+	if len(s) <= MaxErrorBody {
+		return s
+	}
+	n := MaxErrorBody
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + "...(truncated)"
+}
@@ -21,14 +21,43 @@ var (
 	ErrValidation     = errors.New("validation error")
 	ErrGraphQL        = errors.New("GraphQL error")
 	ErrRateLimited    = errors.New("RateLimiting error")
+	ErrDestination    = errors.New("destination error")
+	ErrAPI            = errors.New("API error")
+	ErrCheckpoint     = errors.New("checkpoint error")
 )
 
+// PartialError reports a run that failed after some records had already
+// been extracted. It unwraps to the error that stopped the run.
+type PartialError struct {
+	Records int // Records extracted before the failure
+	Pages   int // Pages fully processed before the failure
+	Err     error
+}
+
+func (e *PartialError) Error() string {
+	return fmt.Sprintf("partial results (%d records from %d pages): %v", e.Records, e.Pages, e.Err)
+}
+
+// Unwrap returns the error that stopped the run.
+func (e *PartialError) Unwrap() error {
+	return e.Err
+}
+
 // GraphQLError represents a single GraphQL error
 type GraphQLError struct {
 	Message    string                 `json:"message"`
 	Path       []interface{}          `json:"path,omitempty"`
 	Extensions map[string]interface{} `json:"extensions,omitempty"`
 	Locations  []ErrorLocation        `json:"locations,omitempty"`
+	Type       string                 `json:"type,omitempty"` // GitHub puts its error code here
+}
+
+// Code returns extensions.code, falling back to the top-level type.
+func (e GraphQLError) Code() string {
+	if code, ok := e.Extensions["code"].(string); ok && code != "" {
+		return code
+	}
+	return e.Type
 }
 
 // ErrorLocation represents the location of an error in the query
@@ -72,10 +101,11 @@ func CheckGraphQLErrors(body []byte) error {
 	)
 }
 
-// WrapError wraps an error with a standard error type and a message
+// WrapError wraps an error with a standard error type and a message.
+// Both errType and err stay reachable through errors.Is and errors.As.
 func WrapError(err error, errType error, message string) error {
 	wrapped := fmt.Errorf("%s: %w", message, err)
-	return fmt.Errorf("%w: %v", errType, wrapped)
+	return fmt.Errorf("%w: %w", errType, wrapped)
 }
 
 // Is checks if an error is or contains the target error
@@ -83,8 +113,15 @@ func Is(err, target error) bool {
 	return errors.Is(err, target)
 }
 
-// Unwrap returns the wrapped error
+// Unwrap returns the wrapped error. For errors wrapping several errors, such
+// as those from WrapError, it returns the first one (the error type).
 func Unwrap(err error) error {
+	if multi, ok := err.(interface{ Unwrap() []error }); ok {
+		if errs := multi.Unwrap(); len(errs) > 0 {
+			return errs[0]
+		}
+		return nil
+	}
 	return errors.Unwrap(err)
 }
 
@@ -0,0 +1,450 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// expr is a boolean filter expression evaluated against the current node
+// ("@") and the document root ("$").
+type expr interface {
+	test(node, root interface{}) bool
+}
+
+// operand is one side of a comparison.
+type operand interface {
+	values(node, root interface{}) []interface{}
+}
+
+type orExpr struct{ left, right expr }
+
+func (e orExpr) test(node, root interface{}) bool {
+	return e.left.test(node, root) || e.right.test(node, root)
+}
+
+type andExpr struct{ left, right expr }
+
+func (e andExpr) test(node, root interface{}) bool {
+	return e.left.test(node, root) && e.right.test(node, root)
+}
+
+type notExpr struct{ inner expr }
+
+func (e notExpr) test(node, root interface{}) bool {
+	return !e.inner.test(node, root)
+}
+
+// existsExpr holds when the path selects anything, e.g. "[?(@.email)]".
+type existsExpr struct{ path pathOperand }
+
+func (e existsExpr) test(node, root interface{}) bool {
+	return len(e.path.values(node, root)) > 0
+}
+
+// literalExpr is a bare true or false.
+type literalExpr bool
+
+func (e literalExpr) test(_, _ interface{}) bool {
+	return bool(e)
+}
+
+type compareExpr struct {
+	op          string
+	left, right operand
+}
+
+// test compares single values. A side that selects nothing or several
+// values only satisfies "==" when both sides select nothing.
+func (e compareExpr) test(node, root interface{}) bool {
+	lv := e.left.values(node, root)
+	rv := e.right.values(node, root)
+	if len(lv) != 1 || len(rv) != 1 {
+		equal := len(lv) == 0 && len(rv) == 0
+		switch e.op {
+		case "==":
+			return equal
+		case "!=":
+			return !equal
+		}
+		return false
+	}
+	return compareValues(e.op, lv[0], rv[0])
+}
+
+type matchExpr struct {
+	left operand
+	re   *regexp.Regexp
+}
+
+func (e matchExpr) test(node, root interface{}) bool {
+	vals := e.left.values(node, root)
+	if len(vals) != 1 {
+		return false
+	}
+	s, ok := vals[0].(string)
+	return ok && e.re.MatchString(s)
+}
+
+// pathOperand is a path relative to "@" or "$".
+type pathOperand struct {
+	absolute bool
+	segments []segment
+}
+
+func (o pathOperand) values(node, root interface{}) []interface{} {
+	start := node
+	if o.absolute {
+		start = root
+	}
+	nodes := []interface{}{start}
+	for _, s := range o.segments {
+		var next []interface{}
+		for _, n := range nodes {
+			next = s.apply(next, n, root)
+		}
+		if len(next) == 0 {
+			return nil
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+type literalOperand struct{ value interface{} }
+
+func (o literalOperand) values(_, _ interface{}) []interface{} {
+	return []interface{}{o.value}
+}
+
+func compareValues(op string, a, b interface{}) bool {
+	if x, ok := toRat(a); ok {
+		if y, ok := toRat(b); ok {
+			c := x.Cmp(y)
+			switch op {
+			case "==":
+				return c == 0
+			case "!=":
+				return c != 0
+			case "<":
+				return c < 0
+			case "<=":
+				return c <= 0
+			case ">":
+				return c > 0
+			case ">=":
+				return c >= 0
+			}
+			return false
+		}
+	}
+
+	if x, ok := a.(string); ok {
+		if y, ok := b.(string); ok {
+			switch op {
+			case "==":
+				return x == y
+			case "!=":
+				return x != y
+			case "<":
+				return x < y
+			case "<=":
+				return x <= y
+			case ">":
+				return x > y
+			case ">=":
+				return x >= y
+			}
+			return false
+		}
+	}
+
+	switch op {
+	case "==":
+		return reflect.DeepEqual(a, b)
+	case "!=":
+		return !reflect.DeepEqual(a, b)
+	}
+	return false
+}
+
+// toRat converts a number exactly, so large integer IDs decoded as
+// json.Number compare correctly.
+func toRat(v interface{}) (*big.Rat, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		return new(big.Rat).SetString(n.String())
+	case float64:
+		if math.IsInf(n, 0) || math.IsNaN(n) {
+			return nil, false
+		}
+		return new(big.Rat).SetFloat64(n), true
+	case int:
+		return new(big.Rat).SetInt64(int64(n)), true
+	case int64:
+		return new(big.Rat).SetInt64(n), true
+	}
+	if f, ok := toFloat(v); ok && !math.IsInf(f, 0) && !math.IsNaN(f) {
+		if s, ok := v.(fmt.Stringer); ok {
+			if r, ok := new(big.Rat).SetString(s.String()); ok {
+				return r, true
+			}
+		}
+		return new(big.Rat).SetFloat64(f), true
+	}
+	return nil, false
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case interface{ Float64() (float64, error) }:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// Filter grammar, lowest precedence first:
+//
+//	or      = and { "||" and }
+//	and     = unary { "&&" unary }
+//	unary   = "!" unary | primary
+//	primary = "(" or ")" | operand [ compOp operand | "=~" regex ]
+
+func (p *parser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !p.hasPrefix("||") {
+			return left, nil
+		}
+		p.pos += 2
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left, right}
+	}
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !p.hasPrefix("&&") {
+			return left, nil
+		}
+		p.pos += 2
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left, right}
+	}
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	p.skipSpace()
+	if p.peek() == '!' && !p.hasPrefix("!=") {
+		p.pos++
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{inner}, nil
+	}
+	return p.parsePrimary()
+}
+
+var compareOps = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+func (p *parser) parsePrimary() (expr, error) {
+	p.skipSpace()
+	if p.peek() == '(' {
+		p.pos++
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.peek() != ')' {
+			return nil, p.errorf("expected ')'")
+		}
+		p.pos++
+		return e, nil
+	}
+
+	start := p.pos
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	if p.hasPrefix("=~") {
+		p.pos += 2
+		p.skipSpace()
+		re, err := p.parseRegex()
+		if err != nil {
+			return nil, err
+		}
+		return matchExpr{left: left, re: re}, nil
+	}
+	for _, op := range compareOps {
+		if p.hasPrefix(op) {
+			p.pos += len(op)
+			right, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			return compareExpr{op: op, left: left, right: right}, nil
+		}
+	}
+
+	switch v := left.(type) {
+	case pathOperand:
+		return existsExpr{v}, nil
+	case literalOperand:
+		if b, ok := v.value.(bool); ok {
+			return literalExpr(b), nil
+		}
+	}
+	p.pos = start
+	return nil, p.errorf("expected comparison")
+}
+
+func (p *parser) parseOperand() (operand, error) {
+	p.skipSpace()
+	switch c := p.peek(); {
+	case c == '@' || c == '$':
+		p.pos++
+		segments, err := p.parseSegments()
+		if err != nil {
+			return nil, err
+		}
+		return pathOperand{absolute: c == '$', segments: segments}, nil
+
+	case c == '\'' || c == '"':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return literalOperand{s}, nil
+
+	case c == '-' || c >= '0' && c <= '9':
+		return p.parseNumber()
+
+	case p.hasKeyword("true"):
+		p.pos += 4
+		return literalOperand{true}, nil
+	case p.hasKeyword("false"):
+		p.pos += 5
+		return literalOperand{false}, nil
+	case p.hasKeyword("null"):
+		p.pos += 4
+		return literalOperand{nil}, nil
+
+	case c == 0:
+		return nil, p.errorf("unexpected end of filter")
+	}
+	return nil, p.errorf("unexpected %q in filter", p.peek())
+}
+
+// hasKeyword reports whether word starts at the current position and is not
+// the prefix of a longer identifier.
+func (p *parser) hasKeyword(word string) bool {
+	if !p.hasPrefix(word) {
+		return false
+	}
+	end := p.pos + len(word)
+	return end == len(p.src) || !isIdentByte(p.src[end])
+}
+
+func (p *parser) parseNumber() (operand, error) {
+	start := p.pos
+	if p.peek() == '-' {
+		p.pos++
+	}
+	for !p.eof() {
+		c := p.src[p.pos]
+		if c >= '0' && c <= '9' || c == '.' || c == 'e' || c == 'E' ||
+			(c == '+' || c == '-') && (p.src[p.pos-1] == 'e' || p.src[p.pos-1] == 'E') {
+			p.pos++
+			continue
+		}
+		break
+	}
+	text := p.src[start:p.pos]
+	if _, err := strconv.ParseFloat(text, 64); err != nil {
+		p.pos = start
+		return nil, p.errorf("invalid number")
+	}
+	return literalOperand{json.Number(text)}, nil
+}
+
+// parseRegex parses "/pattern/flags". Supported flags are i, m and s.
+func (p *parser) parseRegex() (*regexp.Regexp, error) {
+	if p.peek() != '/' {
+		return nil, p.errorf("expected regular expression")
+	}
+	start := p.pos
+	p.pos++
+
+	var b strings.Builder
+	for {
+		if p.eof() {
+			p.pos = start
+			return nil, p.errorf("unterminated regular expression")
+		}
+		c := p.src[p.pos]
+		if c == '/' {
+			p.pos++
+			break
+		}
+		if c == '\\' && p.pos+1 < len(p.src) && p.src[p.pos+1] == '/' {
+			b.WriteByte('/')
+			p.pos += 2
+			continue
+		}
+		b.WriteByte(c)
+		p.pos++
+	}
+
+	flags := ""
+	for !p.eof() && strings.IndexByte("ims", p.src[p.pos]) >= 0 {
+		flags += string(p.src[p.pos])
+		p.pos++
+	}
+
+	pattern := b.String()
+	if flags != "" {
+		pattern = "(?" + flags + ")" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		p.pos = start
+		return nil, p.errorf("invalid regular expression: %v", err)
+	}
+	return re, nil
+}
@@ -0,0 +1,221 @@
+// Package jsonpath compiles and evaluates JSONPath expressions against
+// decoded JSON (map[string]interface{}, []interface{} and scalars).
+//
+// Besides the dotted paths used throughout pipeline configs ("user.name",
+// "items[0]", "items[-1]", "items[*].id"), it supports:
+//   - an optional root: "$.items"
+//   - quoted keys: "['x.y']", `["first name"]`
+//   - wildcards on objects and arrays: "*", ".*", "[*]"
+//   - recursive descent: "..id", "$..book[0]"
+//   - slices: "[0:3]", "[-2:]", "[::2]"
+//   - unions: "[0,2]", "['a','b']"
+//   - filters: "items[?(@.type=='primary')].value", "[?(@.price < 10 && @.tags)]"
+//
+// Dotted numeric segments index into arrays ("data.-1.id") as cursor paths
+// always have.
+//
+// Paths written only with the older dotted syntax (unquoted names, indexes
+// and wildcards, without "$") keep its results: a trailing "[*]" returns the
+// array itself, even when empty, and arrays selected under a wildcard are
+// flattened, so "orders[*].tags" is one list of tags. See Path.Legacy.
+package jsonpath
+
+import (
+	"strings"
+	"sync"
+)
+
+// Path is a compiled JSONPath expression. It is safe for concurrent use.
+type Path struct {
+	src      string
+	segments []segment
+	definite bool
+	legacy   bool
+}
+
+// Compile parses expr. Syntax errors are reported as *SyntaxError and match
+// errors.ErrConfiguration.
+func Compile(expr string) (*Path, error) {
+	p := &parser{src: expr}
+	segments, err := p.parsePath()
+	if err != nil {
+		return nil, err
+	}
+
+	definite := true
+	legacy := expr != "$" && !strings.HasPrefix(expr, "$.") && !strings.HasPrefix(expr, "$[")
+	for _, s := range segments {
+		definite = definite && s.definite()
+		legacy = legacy && s.legacy()
+	}
+	return &Path{src: expr, segments: segments, definite: definite, legacy: legacy}, nil
+}
+
+// MustCompile is like Compile but panics on error.
+func MustCompile(expr string) *Path {
+	p, err := Compile(expr)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// String returns the source expression.
+func (p *Path) String() string {
+	return p.src
+}
+
+// Definite reports whether the path selects at most one value, i.e. it uses
+// no wildcards, slices, unions, filters or recursive descent.
+func (p *Path) Definite() bool {
+	return p.definite
+}
+
+// Legacy reports whether the path only uses the dotted syntax pipeline
+// configs had before JSONPath support, which Get evaluates with that
+// syntax's results.
+func (p *Path) Legacy() bool {
+	return p.legacy
+}
+
+// Names returns the object member names the path selects in turn, e.g.
+// ["result", "items"] for "$.result.items". It reports false when the path
+// uses anything else, including array indexes.
+func (p *Path) Names() ([]string, bool) {
+	names := make([]string, 0, len(p.segments))
+	for _, s := range p.segments {
+		if s.descendant || len(s.selectors) != 1 {
+			return nil, false
+		}
+		sel, ok := s.selectors[0].(nameSelector)
+		if !ok || sel.index != nil {
+			return nil, false
+		}
+		names = append(names, sel.name)
+	}
+	return names, true
+}
+
+// Find returns every value the path selects, in document order.
+func (p *Path) Find(data interface{}) []interface{} {
+	nodes := []interface{}{data}
+	for _, s := range p.segments {
+		var next []interface{}
+		for _, n := range nodes {
+			next = s.apply(next, n, data)
+		}
+		if len(next) == 0 {
+			return nil
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// Get evaluates the path. A definite path returns its single value. Any
+// other path returns the list of matches as []interface{}. ok is false when
+// nothing matched. Legacy paths with wildcards return what they always
+// have; see the package documentation.
+func (p *Path) Get(data interface{}) (interface{}, bool) {
+	if p.legacy && !p.definite {
+		return getLegacy(data, p.segments)
+	}
+	nodes := p.Find(data)
+	if p.definite {
+		if len(nodes) != 1 {
+			return nil, false
+		}
+		return nodes[0], true
+	}
+	if len(nodes) == 0 {
+		return nil, false
+	}
+	return nodes, true
+}
+
+// getLegacy evaluates segments the way the dotted syntax always has. A
+// trailing wildcard returns the array itself, even an empty one; otherwise
+// the rest of the path is applied to each element and array results are
+// flattened into one list.
+func getLegacy(node interface{}, segments []segment) (interface{}, bool) {
+	for i, s := range segments {
+		sel := s.selectors[0]
+		if _, ok := sel.(wildcardSelector); !ok {
+			next := sel.selectInto(nil, node, nil)
+			if len(next) == 0 {
+				return nil, false
+			}
+			node = next[0]
+			continue
+		}
+
+		elems := sel.selectInto(nil, node, nil)
+		if i == len(segments)-1 {
+			if arr, ok := node.([]interface{}); ok {
+				return arr, true
+			}
+			if len(elems) == 0 {
+				return nil, false
+			}
+			return elems, true
+		}
+		var results []interface{}
+		for _, e := range elems {
+			v, ok := getLegacy(e, segments[i+1:])
+			if !ok {
+				continue
+			}
+			if arr, isArr := v.([]interface{}); isArr {
+				results = append(results, arr...)
+			} else {
+				results = append(results, v)
+			}
+		}
+		if len(results) == 0 {
+			return nil, false
+		}
+		return results, true
+	}
+	return node, true
+}
+
+// maxCached bounds the cache used by Get and CompileCached so ad-hoc expressions cannot grow
+// it without limit.
+const maxCached = 1024
+
+var (
+	cacheMu sync.RWMutex
+	cache   = make(map[string]*Path)
+)
+
+// Get compiles expr, caching the result, and evaluates it against data.
+// Invalid expressions match nothing.
+func Get(data interface{}, expr string) (interface{}, bool) {
+	p, err := CompileCached(expr)
+	if err != nil {
+		return nil, false
+	}
+	return p.Get(data)
+}
+
+// CompileCached is like Compile but reuses paths compiled by earlier calls
+// or by Get.
+func CompileCached(expr string) (*Path, error) {
+	cacheMu.RLock()
+	p, ok := cache[expr]
+	cacheMu.RUnlock()
+	if ok {
+		return p, nil
+	}
+
+	p, err := Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	cacheMu.Lock()
+	if len(cache) < maxCached {
+		cache[expr] = p
+	}
+	cacheMu.Unlock()
+	return p, nil
+}
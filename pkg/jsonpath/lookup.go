@@ -0,0 +1,90 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/saturnines/nexus-core/pkg/errors"
+)
+
+// Keys returns a definite path that selects nested object members by their
+// literal names, so keys containing dots or brackets need no quoting.
+func Keys(keys ...string) *Path {
+	var src strings.Builder
+	src.WriteByte('$')
+	segments := make([]segment, len(keys))
+	for i, k := range keys {
+		src.WriteString("['")
+		src.WriteString(strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(k))
+		src.WriteString("']")
+		segments[i] = segment{selectors: []selector{newNameSelector(k, false)}}
+	}
+	return &Path{src: src.String(), segments: segments, definite: true}
+}
+
+// Lookup returns the value a definite path selects, or the first match of
+// any other path. It fails with errors.ErrExtraction when nothing matches.
+func (p *Path) Lookup(data interface{}) (interface{}, error) {
+	nodes := p.Find(data)
+	if len(nodes) == 0 {
+		return nil, errors.WrapError(
+			fmt.Errorf("path %q not found", p.src),
+			errors.ErrExtraction,
+			"look up path",
+		)
+	}
+	return nodes[0], nil
+}
+
+// LookupString is like Lookup but requires a string. A null value yields "".
+func (p *Path) LookupString(data interface{}) (string, error) {
+	v, err := p.Lookup(data)
+	if err != nil || v == nil {
+		return "", err
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", p.typeError("string", v)
+	}
+	return s, nil
+}
+
+// LookupBool is like Lookup but requires a bool.
+func (p *Path) LookupBool(data interface{}) (bool, error) {
+	v, err := p.Lookup(data)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, p.typeError("bool", v)
+	}
+	return b, nil
+}
+
+// LookupInt is like Lookup but requires a number, which is truncated.
+func (p *Path) LookupInt(data interface{}) (int, error) {
+	v, err := p.Lookup(data)
+	if err != nil {
+		return 0, err
+	}
+	if n, ok := v.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			return int(i), nil
+		}
+	}
+	f, ok := toFloat(v)
+	if !ok {
+		return 0, p.typeError("number", v)
+	}
+	return int(f), nil
+}
+
+func (p *Path) typeError(want string, got interface{}) error {
+	return errors.WrapError(
+		fmt.Errorf("path %q is not a %s, got %T", p.src, want, got),
+		errors.ErrExtraction,
+		"look up path",
+	)
+}
@@ -0,0 +1,336 @@
+package jsonpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/saturnines/nexus-core/pkg/errors"
+)
+
+// SyntaxError describes an invalid expression.
+type SyntaxError struct {
+	Path   string // the expression being compiled
+	Offset int    // byte offset of the problem
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("jsonpath: %s at offset %d in %q", e.Msg, e.Offset, e.Path)
+}
+
+type parser struct {
+	src string
+	pos int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return errors.WrapError(
+		&SyntaxError{Path: p.src, Offset: p.pos, Msg: fmt.Sprintf(format, args...)},
+		errors.ErrConfiguration,
+		"compile path",
+	)
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *parser) hasPrefix(s string) bool {
+	return strings.HasPrefix(p.src[p.pos:], s)
+}
+
+func (p *parser) skipSpace() {
+	for !p.eof() && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t' || p.src[p.pos] == '\n' || p.src[p.pos] == '\r') {
+		p.pos++
+	}
+}
+
+// parsePath parses a whole top-level expression.
+func (p *parser) parsePath() ([]segment, error) {
+	if strings.TrimSpace(p.src) == "" {
+		return nil, p.errorf("empty path")
+	}
+
+	var segments []segment
+
+	switch {
+	case p.peek() == '$' && (len(p.src) == 1 || p.src[1] == '.' || p.src[1] == '['):
+		p.pos++
+	case p.peek() != '.' && p.peek() != '[':
+		// Legacy form: the path starts with a bare member name.
+		seg, err := p.parseDotted(false, false)
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, seg)
+	}
+
+	for !p.eof() {
+		seg, err := p.parseSegment(false)
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, seg)
+	}
+	return segments, nil
+}
+
+// parseSegments parses the segments following "@" or "$" inside a filter.
+func (p *parser) parseSegments() ([]segment, error) {
+	var segments []segment
+	for p.peek() == '.' || p.peek() == '[' {
+		seg, err := p.parseSegment(true)
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, seg)
+	}
+	return segments, nil
+}
+
+// parseSegment parses one ".name", ".*", "..x" or "[...]" segment.
+func (p *parser) parseSegment(inFilter bool) (segment, error) {
+	switch {
+	case p.hasPrefix(".."):
+		p.pos += 2
+		if p.peek() == '[' {
+			sels, err := p.parseBracket()
+			return segment{descendant: true, selectors: sels}, err
+		}
+		return p.parseDotted(true, inFilter)
+
+	case p.peek() == '.':
+		p.pos++
+		// "a.[0]" is accepted as "a[0]".
+		if p.peek() == '[' {
+			sels, err := p.parseBracket()
+			return segment{selectors: sels}, err
+		}
+		return p.parseDotted(false, inFilter)
+
+	case p.peek() == '[':
+		sels, err := p.parseBracket()
+		return segment{selectors: sels}, err
+
+	default:
+		return segment{}, p.errorf("unexpected %q", p.peek())
+	}
+}
+
+// parseDotted parses a member name or "*" after a dot. Outside filters a
+// name runs to the next "." or "["; inside filters it must look like an
+// identifier so operators can follow.
+func (p *parser) parseDotted(descendant, inFilter bool) (segment, error) {
+	if p.peek() == '*' {
+		p.pos++
+		return segment{descendant: descendant, selectors: []selector{wildcardSelector{}}}, nil
+	}
+
+	start := p.pos
+	for !p.eof() {
+		c := p.src[p.pos]
+		if c == '.' || c == '[' {
+			break
+		}
+		if inFilter && !isIdentByte(c) {
+			r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+			if r < utf8.RuneSelf || !unicode.IsLetter(r) {
+				break
+			}
+			p.pos += size
+			continue
+		}
+		p.pos++
+	}
+	if p.pos == start {
+		return segment{}, p.errorf("expected member name")
+	}
+	name := p.src[start:p.pos]
+	return segment{descendant: descendant, selectors: []selector{newNameSelector(name, true)}}, nil
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '-' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// parseBracket parses "[...]": a filter, or a comma separated union of
+// quoted names, indexes, slices and "*".
+func (p *parser) parseBracket() ([]selector, error) {
+	p.pos++ // '['
+	p.skipSpace()
+
+	var sels []selector
+	for {
+		p.skipSpace()
+		sel, err := p.parseBracketItem()
+		if err != nil {
+			return nil, err
+		}
+		sels = append(sels, sel)
+
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return sels, nil
+		case 0:
+			return nil, p.errorf("unclosed bracket")
+		default:
+			return nil, p.errorf("unexpected %q in brackets", p.peek())
+		}
+	}
+}
+
+func (p *parser) parseBracketItem() (selector, error) {
+	switch c := p.peek(); {
+	case c == '*':
+		p.pos++
+		return wildcardSelector{}, nil
+
+	case c == '\'' || c == '"':
+		name, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return newNameSelector(name, false), nil
+
+	case c == '?':
+		p.pos++
+		p.skipSpace()
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return filterSelector{expr: e}, nil
+
+	case c == ':' || c == '-' || c >= '0' && c <= '9':
+		return p.parseIndexOrSlice()
+
+	case c == 0:
+		return nil, p.errorf("unclosed bracket")
+
+	default:
+		return nil, p.errorf("unexpected %q in brackets", c)
+	}
+}
+
+func (p *parser) parseIndexOrSlice() (selector, error) {
+	var bounds [3]*int
+	for i := 0; i < 3; i++ {
+		p.skipSpace()
+		if p.peek() == '-' || p.peek() >= '0' && p.peek() <= '9' {
+			n, err := p.parseInt()
+			if err != nil {
+				return nil, err
+			}
+			bounds[i] = &n
+		}
+		p.skipSpace()
+
+		if p.peek() != ':' {
+			if i == 0 {
+				if bounds[0] == nil {
+					return nil, p.errorf("expected index")
+				}
+				return indexSelector(*bounds[0]), nil
+			}
+			break
+		}
+		if i == 2 {
+			return nil, p.errorf("too many ':' in slice")
+		}
+		p.pos++
+	}
+
+	step := 1
+	if bounds[2] != nil {
+		step = *bounds[2]
+	}
+	return sliceSelector{start: bounds[0], end: bounds[1], step: step}, nil
+}
+
+func (p *parser) parseInt() (int, error) {
+	start := p.pos
+	if p.peek() == '-' {
+		p.pos++
+	}
+	for !p.eof() && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+		p.pos++
+	}
+	n, err := strconv.Atoi(p.src[start:p.pos])
+	if err != nil {
+		p.pos = start
+		return 0, p.errorf("invalid integer")
+	}
+	return n, nil
+}
+
+// parseString parses a single or double quoted string with JSON escapes.
+func (p *parser) parseString() (string, error) {
+	quote := p.src[p.pos]
+	start := p.pos
+	p.pos++
+
+	var b strings.Builder
+	for {
+		if p.eof() {
+			p.pos = start
+			return "", p.errorf("unterminated string")
+		}
+		c := p.src[p.pos]
+		switch {
+		case c == quote:
+			p.pos++
+			return b.String(), nil
+		case c == '\\':
+			p.pos++
+			if p.eof() {
+				p.pos = start
+				return "", p.errorf("unterminated string")
+			}
+			esc := p.src[p.pos]
+			switch esc {
+			case '\\', '/', '\'', '"':
+				b.WriteByte(esc)
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'u':
+				if p.pos+5 > len(p.src) {
+					return "", p.errorf("invalid unicode escape")
+				}
+				r, err := strconv.ParseUint(p.src[p.pos+1:p.pos+5], 16, 32)
+				if err != nil {
+					return "", p.errorf("invalid unicode escape")
+				}
+				b.WriteRune(rune(r))
+				p.pos += 4
+			default:
+				return "", p.errorf("invalid escape %q", esc)
+			}
+			p.pos++
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+}
@@ -0,0 +1,231 @@
+package jsonpath
+
+import (
+	"sort"
+	"strconv"
+)
+
+// segment applies its selectors to a node, or to the node and all of its
+// descendants for recursive descent ("..").
+type segment struct {
+	descendant bool
+	selectors  []selector
+}
+
+func (s segment) definite() bool {
+	if s.descendant || len(s.selectors) != 1 {
+		return false
+	}
+	switch s.selectors[0].(type) {
+	case nameSelector, indexSelector:
+		return true
+	}
+	return false
+}
+
+// legacy reports whether the segment is one the dotted syntax supported
+// before JSONPath: an unquoted name, an index or a wildcard.
+func (s segment) legacy() bool {
+	if s.descendant || len(s.selectors) != 1 {
+		return false
+	}
+	switch sel := s.selectors[0].(type) {
+	case nameSelector:
+		return sel.dotted
+	case indexSelector, wildcardSelector:
+		return true
+	}
+	return false
+}
+
+func (s segment) apply(dst []interface{}, node, root interface{}) []interface{} {
+	if !s.descendant {
+		for _, sel := range s.selectors {
+			dst = sel.selectInto(dst, node, root)
+		}
+		return dst
+	}
+
+	walk(node, func(n interface{}) {
+		for _, sel := range s.selectors {
+			dst = sel.selectInto(dst, n, root)
+		}
+	})
+	return dst
+}
+
+// walk visits node and every value nested in it, parents first. Object
+// members are visited in key order so results are deterministic.
+func walk(node interface{}, fn func(interface{})) {
+	fn(node)
+	switch v := node.(type) {
+	case map[string]interface{}:
+		for _, k := range sortedKeys(v) {
+			walk(v[k], fn)
+		}
+	case []interface{}:
+		for _, e := range v {
+			walk(e, fn)
+		}
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// selector picks children of a node.
+type selector interface {
+	selectInto(dst []interface{}, node, root interface{}) []interface{}
+}
+
+// nameSelector selects an object member. Unquoted dotted names that are
+// integers also index arrays, e.g. "data.-1.id".
+type nameSelector struct {
+	name   string
+	index  *int
+	dotted bool // written unquoted, as in "user.name"
+}
+
+func (s nameSelector) selectInto(dst []interface{}, node, _ interface{}) []interface{} {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		if val, ok := v[s.name]; ok {
+			dst = append(dst, val)
+		}
+	case []interface{}:
+		if s.index != nil {
+			return indexSelector(*s.index).selectInto(dst, node, nil)
+		}
+	}
+	return dst
+}
+
+func newNameSelector(name string, legacyIndex bool) nameSelector {
+	s := nameSelector{name: name, dotted: legacyIndex}
+	if legacyIndex {
+		if i, err := strconv.Atoi(name); err == nil {
+			s.index = &i
+		}
+	}
+	return s
+}
+
+// indexSelector selects an array element; negative indexes count from the end.
+type indexSelector int
+
+func (s indexSelector) selectInto(dst []interface{}, node, _ interface{}) []interface{} {
+	arr, ok := node.([]interface{})
+	if !ok {
+		return dst
+	}
+	i := int(s)
+	if i < 0 {
+		i += len(arr)
+	}
+	if i < 0 || i >= len(arr) {
+		return dst
+	}
+	return append(dst, arr[i])
+}
+
+// wildcardSelector selects every array element or object member value.
+type wildcardSelector struct{}
+
+func (wildcardSelector) selectInto(dst []interface{}, node, _ interface{}) []interface{} {
+	switch v := node.(type) {
+	case []interface{}:
+		dst = append(dst, v...)
+	case map[string]interface{}:
+		for _, k := range sortedKeys(v) {
+			dst = append(dst, v[k])
+		}
+	}
+	return dst
+}
+
+// sliceSelector selects array elements from start to end (exclusive) by
+// step, with Python semantics for omitted and negative bounds.
+type sliceSelector struct {
+	start, end *int
+	step       int
+}
+
+func (s sliceSelector) selectInto(dst []interface{}, node, _ interface{}) []interface{} {
+	arr, ok := node.([]interface{})
+	if !ok || s.step == 0 {
+		return dst
+	}
+	n := len(arr)
+
+	normalize := func(i int) int {
+		if i < 0 {
+			return i + n
+		}
+		return i
+	}
+	clamp := func(i, lo, hi int) int {
+		if i < lo {
+			return lo
+		}
+		if i > hi {
+			return hi
+		}
+		return i
+	}
+
+	if s.step > 0 {
+		start, end := 0, n
+		if s.start != nil {
+			start = clamp(normalize(*s.start), 0, n)
+		}
+		if s.end != nil {
+			end = clamp(normalize(*s.end), 0, n)
+		}
+		for i := start; i < end; i += s.step {
+			dst = append(dst, arr[i])
+		}
+		return dst
+	}
+
+	start, end := n-1, -1
+	if s.start != nil {
+		start = clamp(normalize(*s.start), -1, n-1)
+	}
+	if s.end != nil {
+		end = clamp(normalize(*s.end), -1, n-1)
+	}
+	for i := start; i > end; i += s.step {
+		dst = append(dst, arr[i])
+	}
+	return dst
+}
+
+// filterSelector selects the array elements or object member values for
+// which the filter expression holds.
+type filterSelector struct {
+	expr expr
+}
+
+func (s filterSelector) selectInto(dst []interface{}, node, root interface{}) []interface{} {
+	switch v := node.(type) {
+	case []interface{}:
+		for _, e := range v {
+			if s.expr.test(e, root) {
+				dst = append(dst, e)
+			}
+		}
+	case map[string]interface{}:
+		for _, k := range sortedKeys(v) {
+			if s.expr.test(v[k], root) {
+				dst = append(dst, v[k])
+			}
+		}
+	}
+	return dst
+}
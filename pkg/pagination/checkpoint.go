@@ -0,0 +1,144 @@
+package pagination
+
+import (
+	"fmt"
+
+	"github.com/saturnines/nexus-core/pkg/errors"
+)
+
+// State is a serialisable snapshot of a pager's position. It describes the
+// next request the pager would make, so restoring it into a fresh pager
+// continues right after the last committed page.
+type State struct {
+	Type    string `json:"type"`
+	Cursor  string `json:"cursor,omitempty"`
+	Page    int    `json:"page,omitempty"`
+	Offset  int    `json:"offset,omitempty"`
+	NextURL string `json:"next_url,omitempty"`
+	Started bool   `json:"started,omitempty"` // at least one page was fetched
+	Done    bool   `json:"done,omitempty"`    // no more pages
+}
+
+// Checkpointer is implemented by pagers whose position can be saved and
+// restored across runs.
+type Checkpointer interface {
+	Snapshot() State
+	Restore(state State) error
+}
+
+// checkStateType rejects a state taken from a different kind of pager.
+func checkStateType(state State, kind string) error {
+	if state.Type != "" && state.Type != kind {
+		return errors.WrapError(
+			fmt.Errorf("state is for %q pager, not %q", state.Type, kind),
+			errors.ErrPagination,
+			"restore pager state",
+		)
+	}
+	return nil
+}
+
+// Snapshot returns the cursor for the next request.
+func (p *ThreadSafeCursorPager) Snapshot() State {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return State{
+		Type:    "cursor",
+		Cursor:  p.nextCursor,
+		Started: !p.first,
+		Done:    !p.hasMore,
+	}
+}
+
+// Restore positions the pager at a previously saved cursor.
+func (p *ThreadSafeCursorPager) Restore(state State) error {
+	if err := checkStateType(state, "cursor"); err != nil {
+		return err
+	}
+	switch {
+	case state.Done:
+		p.mu.Lock()
+		p.nextCursor = ""
+		p.hasMore = false
+		p.first = false
+		p.mu.Unlock()
+	case state.Started && state.Cursor != "":
+		p.ResumePagination(state.Cursor)
+	default:
+		p.Reset()
+	}
+	return nil
+}
+
+// Snapshot returns the page number of the next request.
+func (p *PagePager) Snapshot() State {
+	next := p.page
+	if !p.first {
+		next++
+	}
+	return State{
+		Type:    "page",
+		Page:    next,
+		Started: !p.first,
+		Done:    !p.first && !p.hasMore,
+	}
+}
+
+// Restore positions the pager so its next request asks for state.Page.
+func (p *PagePager) Restore(state State) error {
+	if err := checkStateType(state, "page"); err != nil {
+		return err
+	}
+	if state.Page >= 1 {
+		p.page = state.Page
+	}
+	p.first = true
+	p.hasMore = true
+	if state.Done {
+		p.first = false
+		p.hasMore = false
+	}
+	return nil
+}
+
+// Snapshot returns the offset of the next request.
+func (p *OffsetPager) Snapshot() State {
+	return State{
+		Type:   "offset",
+		Offset: p.offset,
+		Done:   !p.hasMore,
+	}
+}
+
+// Restore positions the pager so its next request starts at state.Offset.
+func (p *OffsetPager) Restore(state State) error {
+	if err := checkStateType(state, "offset"); err != nil {
+		return err
+	}
+	if state.Offset >= 0 {
+		p.offset = state.Offset
+	}
+	p.hasMore = !state.Done
+	return nil
+}
+
+// Snapshot returns the URL of the next request.
+func (p *LinkPager) Snapshot() State {
+	return State{
+		Type:    "link",
+		NextURL: p.nextURL,
+		Done:    p.nextURL == "",
+	}
+}
+
+// Restore positions the pager so its next request fetches state.NextURL.
+func (p *LinkPager) Restore(state State) error {
+	if err := checkStateType(state, "link"); err != nil {
+		return err
+	}
+	p.nextURL = state.NextURL
+	if state.Done {
+		p.nextURL = ""
+	}
+	return nil
+}
@@ -1,10 +1,12 @@
 package pagination
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/saturnines/nexus-core/pkg/errors"
+	"github.com/saturnines/nexus-core/pkg/jsonpath"
 	"net/http"
-	"strings"
+	"strconv"
 	"sync"
 )
 
@@ -16,6 +18,7 @@ type ThreadSafeCursorPager struct {
 	baseReq     *http.Request
 	cursorParam string
 	nextPath    string
+	next        *jsonpath.Path
 
 	// Mutable state (protected by mutex)
 	mu         sync.RWMutex
@@ -30,94 +33,33 @@ func NewThreadSafeCursorPager(client HTTPDoer, req *http.Request, cursorParam, n
 	if nextPath == "" {
 		return nil, fmt.Errorf("nextPath cannot be empty")
 	}
+	next, err := jsonpath.Compile(nextPath)
+	if err != nil {
+		return nil, err
+	}
 
 	return &ThreadSafeCursorPager{
 		client:      client,
 		baseReq:     req,
 		cursorParam: cursorParam,
 		nextPath:    nextPath,
+		next:        next,
 		hasMore:     true,
 		first:       true,
 	}, nil
 }
 
-// ExtractNestedValue extracts a value from nested data using a path like "data.-1.id"
+// ExtractNestedValue extracts a value from nested data using a path like
+// "data.-1.id" or "data[-1].id".
+//
+// Deprecated: compile the path once with jsonpath.Compile and use
+// (*jsonpath.Path).Lookup.
 func ExtractNestedValue(data interface{}, path string) (interface{}, error) {
-	parts := strings.Split(path, ".")
-	current := data
-
-	for i, part := range parts {
-		switch v := current.(type) {
-		case map[string]interface{}:
-			// case check if this is the last part and the next part is an array index
-			if i < len(parts)-2 && (parts[i+1] == "-1" || isNumeric(parts[i+1])) {
-				// Get the array from the current map
-				if arr, ok := v[part].([]interface{}); ok {
-					current = arr
-					continue
-				}
-			}
-
-			// Normal map access
-			val, ok := v[part]
-			if !ok {
-				return nil, errors.WrapError(
-					fmt.Errorf("key %q not found", part),
-					errors.ErrExtraction,
-					"extract nested value",
-				)
-			}
-			current = val
-
-		case []interface{}:
-			// Handle array indexing
-			if part == "-1" {
-				// Get last element
-				if len(v) == 0 {
-					return nil, errors.WrapError(
-						fmt.Errorf("cannot get last element of empty array"),
-						errors.ErrExtraction,
-						"extract array element",
-					)
-				}
-				current = v[len(v)-1]
-			} else if idx, err := parseInt(part); err == nil {
-				// Numeric index
-				if idx < 0 || idx >= len(v) {
-					return nil, errors.WrapError(
-						fmt.Errorf("array index %d out of bounds", idx),
-						errors.ErrExtraction,
-						"access array index",
-					)
-				}
-				current = v[idx]
-			} else {
-				return nil, errors.WrapError(
-					fmt.Errorf("cannot traverse %T with key %q", current, part),
-					errors.ErrExtraction,
-					"traverse data structure",
-				)
-			}
-
-		default:
-			return nil, fmt.Errorf("cannot traverse %T with key %q", current, part)
-		}
+	p, err := jsonpath.CompileCached(path)
+	if err != nil {
+		return nil, err
 	}
-
-	return current, nil
-}
-
-// Helper to check if string is numeric
-func isNumeric(s string) bool {
-	_, err := parseInt(s)
-	return err == nil
-}
-
-// Helper to parse int
-func parseInt(s string) (int, error) {
-	var n int
-	_, err := fmt.Sscanf(s, "%d", &n)
-	return n, err
+	return p.Lookup(data)
 }
 
 // NextRequest returns the next HTTP request, or nil when there are no more pages.
@@ -165,7 +107,7 @@ func (p *ThreadSafeCursorPager) UpdateState(resp *http.Response) error {
 	}
 
 	// Extract next cursor value using our enhanced extraction
-	nextCursorValue, err := ExtractNestedValue(body, p.nextPath)
+	nextCursorValue, err := p.next.Lookup(body)
 
 	// Update pagination state based on next cursor
 	if err != nil || nextCursorValue == nil {
@@ -175,9 +117,15 @@ func (p *ThreadSafeCursorPager) UpdateState(resp *http.Response) error {
 	} else {
 		// Convert to string and check if it's empty
 		var cursorStr string
-		if strVal, ok := nextCursorValue.(string); ok {
-			cursorStr = strVal
-		} else {
+		switch v := nextCursorValue.(type) {
+		case string:
+			cursorStr = v
+		case json.Number:
+			// Numeric cursors such as 64-bit IDs are sent back verbatim
+			cursorStr = v.String()
+		case float64:
+			cursorStr = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
 			// Try to convert to string
 			cursorStr = fmt.Sprintf("%v", nextCursorValue)
 		}
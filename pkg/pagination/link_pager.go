@@ -4,21 +4,44 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+
+	"github.com/saturnines/nexus-core/pkg/jsonpath"
 )
 
 // LinkPager drives Link based pagination in a REST client.
+// The next URL comes from the RFC 5988 Link header, or from the JSON body
+// when NextLinkPath is set (e.g. "nextRecordsUrl", "@odata.nextLink",
+// "_links.next.href").
 type LinkPager struct {
-	Client  HTTPDoer
-	BaseReq *http.Request
-	nextURL string
+	Client       HTTPDoer
+	BaseReq      *http.Request
+	NextLinkPath string         // e.g. "_links.next.href"
+	UseHeader    bool           // also fall back to the Link header when NextLinkPath is set
+	nextLink     *jsonpath.Path // compiled NextLinkPath
+	nextURL      string
 }
 
 // NewLinkPager starts at the BaseReq’s URL.
 func NewLinkPager(client HTTPDoer, req *http.Request) *LinkPager {
 	return &LinkPager{
-		Client:  client,
-		BaseReq: req,
-		nextURL: req.URL.String(),
+		Client:    client,
+		BaseReq:   req,
+		UseHeader: true,
+		nextURL:   req.URL.String(),
+	}
+}
+
+// NewLinkPagerWithNextLinkPath builds a LinkPager that follows the URL found
+// at nextLinkPath in the response body. If useHeader is true the Link header
+// is used whenever the body has no next link.
+func NewLinkPagerWithNextLinkPath(client HTTPDoer, req *http.Request, nextLinkPath string, useHeader bool) *LinkPager {
+	return &LinkPager{
+		Client:       client,
+		BaseReq:      req,
+		NextLinkPath: nextLinkPath,
+		UseHeader:    useHeader,
+		nextLink:     optionalPath(nextLinkPath),
+		nextURL:      req.URL.String(),
 	}
 }
 
@@ -62,14 +85,43 @@ func (p *LinkPager) NextRequest() (*http.Request, error) {
 	return req, nil
 }
 
-// UpdateState reads the Link header and sets p.nextURL to the next link or empty
+// UpdateState sets p.nextURL to the next link from the body and/or Link header, or empty.
 func (p *LinkPager) UpdateState(resp *http.Response) error {
-	header := resp.Header.Get("Link")
-	links := parseLinkHeader(header)
-	p.nextURL = links["next"]
+	p.nextURL = ""
+
+	if p.NextLinkPath != "" {
+		body, err := parseBody(resp)
+		if err != nil {
+			return err
+		}
+		p.nextURL = p.lookupNextLink(body)
+	}
+
+	if p.nextURL == "" && p.UseHeader {
+		header := resp.Header.Get("Link")
+		links := parseLinkHeader(header)
+		p.nextURL = links["next"]
+	}
 	return nil
 }
 
+// lookupNextLink finds the next URL at path. Keys that themselves contain
+// dots, such as "@odata.nextLink", are matched literally first.
+// A missing, null or non-string value means there is no next page.
+func (p *LinkPager) lookupNextLink(body map[string]interface{}) string {
+	if v, ok := body[p.NextLinkPath].(string); ok {
+		return v
+	}
+	if p.nextLink == nil {
+		return ""
+	}
+	next, err := p.nextLink.LookupString(body)
+	if err != nil {
+		return ""
+	}
+	return next
+}
+
 // parseLinkHeader splits “<url>; rel=\"next\", <url2>; rel=\"last\"”
 // into a map: { "next": "url", "last": "url2" }.
 // Ignores segments without a rel= value.
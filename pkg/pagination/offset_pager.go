@@ -3,6 +3,8 @@ package pagination
 import (
 	"fmt"
 	"net/http"
+
+	"github.com/saturnines/nexus-core/pkg/jsonpath"
 )
 
 // OffsetPager handles offset–limit pagination.
@@ -14,6 +16,9 @@ type OffsetPager struct {
 	HasMorePath    string // e.g. "meta.has_more"
 	TotalCountPath string // e.g. "meta.total_count" - NEW FIELD
 
+	hasMoreExpr    *jsonpath.Path // compiled HasMorePath
+	totalCountExpr *jsonpath.Path // compiled TotalCountPath
+
 	offset     int
 	size       int
 	hasMore    bool
@@ -40,6 +45,7 @@ func NewOffsetPager(
 		OffsetParam: offsetParam,
 		SizeParam:   sizeParam,
 		HasMorePath: hasMorePath,
+		hasMoreExpr: optionalPath(hasMorePath),
 		offset:      initOffset,
 		size:        pageSize,
 		hasMore:     true,
@@ -68,6 +74,8 @@ func NewOffsetPagerWithTotalCount(
 		SizeParam:      sizeParam,
 		HasMorePath:    hasMorePath,
 		TotalCountPath: totalCountPath, // NEW
+		hasMoreExpr:    optionalPath(hasMorePath),
+		totalCountExpr: optionalPath(totalCountPath),
 		offset:         initOffset,
 		size:           pageSize,
 		hasMore:        true,
@@ -80,12 +88,7 @@ func (p *OffsetPager) NextRequest() (*http.Request, error) {
 	if !p.hasMore {
 		return nil, nil
 	}
-	req := p.BaseReq.Clone(p.BaseReq.Context())
-	q := req.URL.Query()
-	q.Set(p.OffsetParam, fmt.Sprint(p.offset))
-	q.Set(p.SizeParam, fmt.Sprint(p.size))
-	req.URL.RawQuery = q.Encode()
-	return req, nil
+	return p.requestFor(p.offset), nil
 }
 
 // UpdateState reads response and determines if more pages exist.
@@ -101,8 +104,8 @@ func (p *OffsetPager) UpdateState(resp *http.Response) error {
 	}
 
 	//  Check total_count field
-	if p.TotalCountPath != "" {
-		totalCount, err := lookupInt(body, p.TotalCountPath)
+	if p.totalCountExpr != nil {
+		totalCount, err := p.totalCountExpr.LookupInt(body)
 		if err != nil {
 			// If total_count field is missing/invalid, fall back to other methods
 			// Don't return error, just continue to next method
@@ -121,8 +124,8 @@ func (p *OffsetPager) UpdateState(resp *http.Response) error {
 	}
 
 	// Priority 2 - Check has_more field (existing logic)
-	if p.HasMorePath != "" {
-		more, err := lookupBool(body, p.HasMorePath)
+	if p.hasMoreExpr != nil {
+		more, err := p.hasMoreExpr.LookupBool(body)
 		if err != nil {
 			// Missing or invalid field → assume no more pages
 			p.hasMore = false
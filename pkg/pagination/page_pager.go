@@ -3,8 +3,8 @@ package pagination
 import (
 	"fmt"
 	"github.com/saturnines/nexus-core/pkg/errors"
+	"github.com/saturnines/nexus-core/pkg/jsonpath"
 	"net/http"
-	"strings"
 )
 
 // PagePager for "page + page_size + has_more" pagination.
@@ -16,6 +16,9 @@ type PagePager struct {
 	HasMorePath    string // e.g. "meta.has_more"
 	TotalPagesPath string // e.g. "meta.total_pages" - NEW FIELD
 
+	hasMoreExpr    *jsonpath.Path // compiled HasMorePath
+	totalPagesExpr *jsonpath.Path // compiled TotalPagesPath
+
 	page       int
 	size       int
 	first      bool
@@ -44,6 +47,7 @@ func NewPagePager(
 		PageParam:   pageParam,
 		SizeParam:   sizeParam,
 		HasMorePath: hasMorePath,
+		hasMoreExpr: optionalPath(hasMorePath),
 		page:        startPage,
 		size:        pageSize,
 		first:       true,
@@ -72,6 +76,8 @@ func NewPagePagerWithTotalPages(
 		SizeParam:      sizeParam,
 		HasMorePath:    hasMorePath,
 		TotalPagesPath: totalPagesPath, // NEW
+		hasMoreExpr:    optionalPath(hasMorePath),
+		totalPagesExpr: optionalPath(totalPagesPath),
 		page:           startPage,
 		size:           pageSize,
 		first:          true,
@@ -93,11 +99,7 @@ func (p *PagePager) NextRequest() (*http.Request, error) {
 	}
 
 	// Build a fresh request with new query params.
-	req := p.BaseReq.Clone(p.BaseReq.Context())
-	q := req.URL.Query()
-	q.Set(p.PageParam, fmt.Sprint(p.page))
-	q.Set(p.SizeParam, fmt.Sprint(p.size))
-	req.URL.RawQuery = q.Encode()
+	req := p.requestFor(p.page)
 
 	p.first = false
 	return req, nil
@@ -122,8 +124,8 @@ func (p *PagePager) UpdateState(resp *http.Response) error {
 	}
 
 	// NCheck total_pages field
-	if p.TotalPagesPath != "" {
-		totalPages, err := lookupInt(body, p.TotalPagesPath)
+	if p.totalPagesExpr != nil {
+		totalPages, err := p.totalPagesExpr.LookupInt(body)
 		if err != nil {
 			// If total_pages field is missing/invalid, fall back to other methods
 			// Don't return error, just continue to next method
@@ -135,8 +137,8 @@ func (p *PagePager) UpdateState(resp *http.Response) error {
 	}
 
 	// Priority 2 - Check has_more field
-	if p.HasMorePath != "" {
-		more, err := lookupBool(body, p.HasMorePath)
+	if p.hasMoreExpr != nil {
+		more, err := p.hasMoreExpr.LookupBool(body)
 		if err != nil {
 			// Missing or invalid field safely degrade and assume no more pages.
 			p.hasMore = false
@@ -158,43 +160,3 @@ func (p *PagePager) UpdateState(resp *http.Response) error {
 
 	return nil
 }
-
-// lookupInt helper function to extract integer from nested JSON path
-func lookupInt(body map[string]interface{}, path string) (int, error) {
-	parts := strings.Split(path, ".")
-	var cur interface{} = body
-	for _, key := range parts {
-		m, ok := cur.(map[string]interface{})
-		if !ok {
-			return 0, errors.WrapError(
-				fmt.Errorf("lookupInt: %q is not an object", key),
-				errors.ErrExtraction,
-				"traverse object",
-			)
-		}
-		cur, ok = m[key]
-		if !ok {
-			return 0, errors.WrapError(
-				fmt.Errorf("lookupInt: missing field %q", key),
-				errors.ErrExtraction,
-				"find field",
-			)
-		}
-	}
-
-	// Handle different number types from JSON
-	switch v := cur.(type) {
-	case float64:
-		return int(v), nil
-	case int:
-		return v, nil
-	case int64:
-		return int(v), nil
-	default:
-		return 0, errors.WrapError(
-			fmt.Errorf("lookupInt: field %q is not a number, got %T", path, v),
-			errors.ErrExtraction,
-			"convert to integer",
-		)
-	}
-}
@@ -0,0 +1,93 @@
+package pagination
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// PlannedRequest is the request for a later page together with the pager
+// state to checkpoint once that page has been consumed.
+type PlannedRequest struct {
+	Request *http.Request
+	State   State
+}
+
+// Planner is implemented by pagers that can enumerate every remaining
+// request once a response has told them how large the dataset is, so the
+// pages can be fetched concurrently. Plan returns nil while the total is
+// unknown. After a non-empty plan the pager reports no further requests.
+type Planner interface {
+	Plan() ([]PlannedRequest, error)
+}
+
+// Plan lists the requests for pages after the current one up to the total
+// learned from TotalPagesPath.
+func (p *PagePager) Plan() ([]PlannedRequest, error) {
+	if p.first || !p.hasMore || p.totalPages <= 0 {
+		return nil, nil
+	}
+
+	var planned []PlannedRequest
+	for page := p.page + 1; page <= p.totalPages; page++ {
+		planned = append(planned, PlannedRequest{
+			Request: p.requestFor(page),
+			State: State{
+				Type:    "page",
+				Page:    page + 1,
+				Started: true,
+				Done:    page == p.totalPages,
+			},
+		})
+	}
+	if len(planned) > 0 {
+		p.page = p.totalPages
+		p.hasMore = false
+	}
+	return planned, nil
+}
+
+// requestFor builds the request for page.
+func (p *PagePager) requestFor(page int) *http.Request {
+	req := p.BaseReq.Clone(p.BaseReq.Context())
+	q := req.URL.Query()
+	q.Set(p.PageParam, fmt.Sprint(page))
+	q.Set(p.SizeParam, fmt.Sprint(p.size))
+	req.URL.RawQuery = q.Encode()
+	return req
+}
+
+// Plan lists the requests for offsets from the next one up to the total
+// learned from TotalCountPath.
+func (p *OffsetPager) Plan() ([]PlannedRequest, error) {
+	if !p.hasMore || p.totalCount < 0 {
+		return nil, nil
+	}
+
+	var planned []PlannedRequest
+	for offset := p.offset; offset < p.totalCount; offset += p.size {
+		next := offset + p.size
+		planned = append(planned, PlannedRequest{
+			Request: p.requestFor(offset),
+			State: State{
+				Type:   "offset",
+				Offset: next,
+				Done:   next >= p.totalCount,
+			},
+		})
+	}
+	if len(planned) > 0 {
+		p.offset = p.totalCount
+		p.hasMore = false
+	}
+	return planned, nil
+}
+
+// requestFor builds the request for offset.
+func (p *OffsetPager) requestFor(offset int) *http.Request {
+	req := p.BaseReq.Clone(p.BaseReq.Context())
+	q := req.URL.Query()
+	q.Set(p.OffsetParam, fmt.Sprint(offset))
+	q.Set(p.SizeParam, fmt.Sprint(p.size))
+	req.URL.RawQuery = q.Encode()
+	return req
+}
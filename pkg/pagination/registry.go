@@ -28,6 +28,10 @@ func cursorCreator(c HTTPDoer, r *http.Request, opts map[string]interface{}) (Pa
 		return nil, err
 	}
 
+	if err := checkPaths("cursor pagination", np); err != nil {
+		return nil, err
+	}
+
 	// Use the thread-safe version now
 	pager, err := NewThreadSafeCursorPager(c, r, cp, np)
 	if err != nil {
@@ -53,6 +57,10 @@ func pageCreator(c HTTPDoer, r *http.Request, opts map[string]interface{}) (Page
 	// Add support for totalPagesPath (optional)
 	tp := getOptionalStringOption(opts, "totalPagesPath")
 
+	if err := checkPaths("page pagination", hm, tp); err != nil {
+		return nil, err
+	}
+
 	sp, err := getIntOption(opts, "startPage", "page pagination")
 	if err != nil {
 		return nil, err
@@ -88,6 +96,10 @@ func offsetCreator(c HTTPDoer, r *http.Request, opts map[string]interface{}) (Pa
 	// NEW: Add support for totalCountPath (optional)
 	tc := getOptionalStringOption(opts, "totalCountPath")
 
+	if err := checkPaths("offset pagination", hm, tc); err != nil {
+		return nil, err
+	}
+
 	io, err := getIntOption(opts, "initOffset", "offset pagination")
 	if err != nil {
 		return nil, err
@@ -107,6 +119,15 @@ func offsetCreator(c HTTPDoer, r *http.Request, opts map[string]interface{}) (Pa
 }
 
 func linkCreator(c HTTPDoer, r *http.Request, opts map[string]interface{}) (Pager, error) {
+	// nextLinkPath is optional, without it only the Link header is used
+	nl := getOptionalStringOption(opts, "nextLinkPath")
+	if err := checkPaths("link pagination", nl); err != nil {
+		return nil, err
+	}
+	if nl != "" {
+		// Body links win, but the Link header still applies when the body has none
+		return NewLinkPagerWithNextLinkPath(c, r, nl, true), nil
+	}
 	return NewLinkPager(c, r), nil
 }
 
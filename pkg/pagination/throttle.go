@@ -0,0 +1,9 @@
+package pagination
+
+import "time"
+
+// Throttler is implemented by pagers that learn from a response how long to
+// wait before sending the next request, e.g. from GraphQL query cost hints.
+type Throttler interface {
+	Delay() time.Duration
+}
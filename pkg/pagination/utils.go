@@ -1,25 +1,62 @@
 package pagination
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"github.com/saturnines/nexus-core/pkg/errors"
+	"github.com/saturnines/nexus-core/pkg/jsonpath"
 	"net/http"
-	"strings"
 )
 
+// DecodedBody is a response body that was already decoded, e.g. while
+// streaming items out of it. Pagers use the document as-is instead of
+// parsing the body again; any other reader sees it re-encoded as JSON.
+type DecodedBody struct {
+	Doc interface{}
+	r   *bytes.Reader
+}
+
+// NewDecodedBody wraps doc, which should hold numbers as json.Number.
+func NewDecodedBody(doc interface{}) *DecodedBody {
+	return &DecodedBody{Doc: doc}
+}
+
+func (b *DecodedBody) Read(p []byte) (int, error) {
+	if b.r == nil {
+		data, err := json.Marshal(b.Doc)
+		if err != nil {
+			return 0, err
+		}
+		b.r = bytes.NewReader(data)
+	}
+	return b.r.Read(p)
+}
+
+func (b *DecodedBody) Close() error {
+	return nil
+}
+
 // if for some reason an api has unexpected pagination handling just add it here.
 // parseBody reads and parses JSON into a generic map.
 func parseBody(resp *http.Response) (map[string]interface{}, error) {
 	defer resp.Body.Close()
 
 	var raw interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
-		return nil, errors.WrapError(
-			fmt.Errorf("unexpected response type: %T", raw),
-			errors.ErrHTTPResponse,
-			"parse response body",
-		)
+	if db, ok := resp.Body.(*DecodedBody); ok {
+		raw = db.Doc
+	} else {
+		// Numbers stay json.Number so numeric cursors are sent back exactly.
+		dec := json.NewDecoder(resp.Body)
+		dec.UseNumber()
+
+		if err := dec.Decode(&raw); err != nil {
+			return nil, errors.WrapError(
+				fmt.Errorf("unexpected response type: %T", raw),
+				errors.ErrHTTPResponse,
+				"parse response body",
+			)
+		}
 	}
 
 	// If it's already an object, return it
@@ -37,62 +74,28 @@ func parseBody(resp *http.Response) (map[string]interface{}, error) {
 	return nil, fmt.Errorf("unexpected response type: %T", raw)
 }
 
-// This isn't used might be used in the future I guess
-func lookupString(body map[string]interface{}, path string) (string, error) {
-	parts := strings.Split(path, ".")
-	var cur interface{} = body
-	for _, key := range parts {
-		m, ok := cur.(map[string]interface{})
-		if !ok {
-			return "", fmt.Errorf("lookupString: %q is not an object", key)
-		}
-		cur, ok = m[key]
-		if !ok {
-			return "", fmt.Errorf("lookupString: missing field %q", key)
-		}
-	}
-
-	// Handle null values
-	if cur == nil {
-		return "", nil // Treat null as empty string should stop pagination
+// compilePath compiles a pagination path. An empty path yields nil.
+func compilePath(path string) (*jsonpath.Path, error) {
+	if path == "" {
+		return nil, nil
 	}
+	return jsonpath.Compile(path)
+}
 
-	s, ok := cur.(string)
-	if !ok {
-		return "", fmt.Errorf("lookupString: field %q is not a string", path)
-	}
-	return s, nil
+// optionalPath is compilePath for constructors that cannot fail. Creators
+// validate paths with checkPaths first, so an invalid path here only
+// disables that lookup.
+func optionalPath(path string) *jsonpath.Path {
+	p, _ := compilePath(path)
+	return p
 }
 
-// lookupBool drills into a nested map by a dotted path and returns a bool.
-func lookupBool(body map[string]interface{}, path string) (bool, error) {
-	parts := strings.Split(path, ".")
-	var cur interface{} = body
-	for _, key := range parts {
-		m, ok := cur.(map[string]interface{})
-		if !ok {
-			return false, errors.WrapError(
-				fmt.Errorf("lookupBool: %q is not an object", key),
-				errors.ErrExtraction,
-				"traverse object",
-			)
+// checkPaths reports the first of paths that does not compile.
+func checkPaths(ctx string, paths ...string) error {
+	for _, path := range paths {
+		if _, err := compilePath(path); err != nil {
+			return errors.WrapError(err, errors.ErrConfiguration, ctx)
 		}
-		cur, ok = m[key]
-		if !ok {
-			return false, errors.WrapError(
-				fmt.Errorf("lookupBool: missing field %q", key),
-				errors.ErrExtraction,
-				"find field",
-			)
-		}
-	}
-	b, ok := cur.(bool)
-	if !ok {
-		return false, errors.WrapError(
-			fmt.Errorf("lookupBool: field %q is not a bool", path),
-			errors.ErrExtraction,
-			"convert to boolean",
-		)
 	}
-	return b, nil
+	return nil
 }
@@ -0,0 +1,95 @@
+package sink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/errors"
+)
+
+// JSONLSink appends records as JSON lines to a file, or to stdout when the
+// path is "-". It needs no schema, which makes it a handy dead-letter sink.
+type JSONLSink struct {
+	path string
+
+	mu   sync.Mutex
+	file *os.File
+	w    *bufio.Writer
+}
+
+// NewJSONLSink creates a sink for the file at dest.DSN.
+func NewJSONLSink(dest *config.Destination) (Sink, error) {
+	if dest.DSN == "" {
+		return nil, fmt.Errorf(`jsonl destination requires dsn (a file path or "-")`)
+	}
+	return &JSONLSink{path: dest.DSN}, nil
+}
+
+// Init opens the file for appending. Calling it again is a no-op.
+func (s *JSONLSink) Init(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.w != nil {
+		return nil
+	}
+	if s.path == "-" {
+		s.w = bufio.NewWriter(os.Stdout)
+		return nil
+	}
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrDestination, "open jsonl file")
+	}
+	s.file = f
+	s.w = bufio.NewWriter(f)
+	return nil
+}
+
+// Write appends one line per record and flushes the batch.
+func (s *JSONLSink) Write(ctx context.Context, records []map[string]interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.w == nil {
+		return errors.WrapError(fmt.Errorf("sink not initialised"), errors.ErrDestination, "write jsonl")
+	}
+	enc := json.NewEncoder(s.w)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			return errors.WrapError(err, errors.ErrDestination, "encode record")
+		}
+	}
+	if err := s.w.Flush(); err != nil {
+		return errors.WrapError(err, errors.ErrDestination, "write jsonl")
+	}
+	return nil
+}
+
+// Close flushes and closes the file.
+func (s *JSONLSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var err error
+	if s.w != nil {
+		err = s.w.Flush()
+		s.w = nil
+	}
+	if s.file != nil {
+		if cerr := s.file.Close(); err == nil {
+			err = cerr
+		}
+		s.file = nil
+	}
+	return err
+}
+
+func init() {
+	_ = DefaultRegistry.Register(config.DestinationJSONL, NewJSONLSink)
+}
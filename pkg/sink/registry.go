@@ -0,0 +1,95 @@
+package sink
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/errors"
+)
+
+// Creator builds a Sink from destination config.
+type Creator func(dest *config.Destination) (Sink, error)
+
+// Registry holds a registry of Sink creators keyed by destination type.
+type Registry struct {
+	mu       sync.RWMutex
+	creators map[config.DestinationType]Creator
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		creators: make(map[config.DestinationType]Creator),
+	}
+}
+
+// Register adds a new Sink creator.
+// It errors if something is already registered for kind.
+func (r *Registry) Register(kind config.DestinationType, creator Creator) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.creators[kind]; exists {
+		return errors.WrapError(
+			fmt.Errorf("sink %q already registered", kind),
+			errors.ErrConfiguration,
+			"register sink",
+		)
+	}
+	r.creators[kind] = creator
+	return nil
+}
+
+// Create looks up and invokes the creator for dest.Type.
+func (r *Registry) Create(dest *config.Destination) (Sink, error) {
+	if dest == nil {
+		return nil, errors.WrapError(
+			fmt.Errorf("destination config missing"),
+			errors.ErrConfiguration,
+			"create sink",
+		)
+	}
+
+	r.mu.RLock()
+	creator, ok := r.creators[dest.Type]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, errors.WrapError(
+			fmt.Errorf("unsupported destination type: %s", dest.Type),
+			errors.ErrConfiguration,
+			"create sink",
+		)
+	}
+
+	s, err := creator(dest)
+	if err != nil {
+		return nil, errors.WrapError(
+			err,
+			errors.ErrConfiguration,
+			fmt.Sprintf("creating %q sink", dest.Type),
+		)
+	}
+	return s, nil
+}
+
+// GetAvailableSinks returns a sorted list of registered destination types.
+func (r *Registry) GetAvailableSinks() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	kinds := make([]string, 0, len(r.creators))
+	for kind := range r.creators {
+		kinds = append(kinds, string(kind))
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// DefaultRegistry is the global registry. Dialect packages such as
+// pkg/sink/sqlite register themselves here when imported.
+var DefaultRegistry = NewRegistry()
+
+// Create builds a Sink using the default registry.
+func Create(dest *config.Destination) (Sink, error) {
+	return DefaultRegistry.Create(dest)
+}
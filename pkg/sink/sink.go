@@ -0,0 +1,65 @@
+package sink
+
+import (
+	"context"
+	"iter"
+)
+
+// DefaultBatchSize is used when the destination does not set batch_size.
+const DefaultBatchSize = 500
+
+// Sink writes extracted records to a destination.
+type Sink interface {
+	// Init prepares the destination (tables, indexes). It must be safe to call on every run.
+	Init(ctx context.Context) error
+	// Write stores one batch of records.
+	Write(ctx context.Context, records []map[string]interface{}) error
+	// Close releases any resources held by the sink.
+	Close() error
+}
+
+// Load initialises s and drains records into it in batches of batchSize.
+// It returns the number of records written.
+func Load(
+	ctx context.Context,
+	s Sink,
+	records iter.Seq2[map[string]interface{}, error],
+	batchSize int,
+) (int, error) {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	if err := s.Init(ctx); err != nil {
+		return 0, err
+	}
+
+	written := 0
+	batch := make([]map[string]interface{}, 0, batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := s.Write(ctx, batch); err != nil {
+			return err
+		}
+		written += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	for record, err := range records {
+		if err != nil {
+			return written, err
+		}
+		batch = append(batch, record)
+		if len(batch) >= batchSize {
+			if err := flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return written, err
+	}
+	return written, nil
+}
@@ -0,0 +1,242 @@
+package sink
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/errors"
+)
+
+// Dialect captures the SQL differences between databases.
+type Dialect interface {
+	// QuoteIdent quotes a table or column name.
+	QuoteIdent(name string) string
+	// Placeholder returns the bind parameter for the n-th (1-based) argument.
+	Placeholder(n int) string
+	// ColumnType maps a schema type (string, integer, ...) to a column type.
+	ColumnType(schemaType string) (string, error)
+}
+
+// SQLSink writes records into a single table, upserting on the primary key.
+type SQLSink struct {
+	db      *sql.DB
+	dialect Dialect
+	table   string
+	columns []config.Schema
+	keys    []string
+}
+
+// NewSQLSink builds a SQLSink for dest. The sink takes ownership of db and
+// closes it in Close.
+func NewSQLSink(db *sql.DB, dialect Dialect, dest *config.Destination) (*SQLSink, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db cannot be nil")
+	}
+	if dest.Table == "" {
+		return nil, fmt.Errorf("destination table is required")
+	}
+	if len(dest.Schema) == 0 {
+		return nil, fmt.Errorf("destination schema must define at least one column")
+	}
+
+	s := &SQLSink{
+		db:      db,
+		dialect: dialect,
+		table:   dest.Table,
+		columns: dest.Schema,
+	}
+	seen := make(map[string]struct{}, len(dest.Schema))
+	for _, col := range dest.Schema {
+		if col.Name == "" {
+			return nil, fmt.Errorf("schema column name is required")
+		}
+		if _, dup := seen[col.Name]; dup {
+			return nil, fmt.Errorf("duplicate schema column %q", col.Name)
+		}
+		seen[col.Name] = struct{}{}
+		if _, err := dialect.ColumnType(col.Type); err != nil {
+			return nil, fmt.Errorf("column %q: %w", col.Name, err)
+		}
+		if col.PrimaryKey {
+			s.keys = append(s.keys, col.Name)
+		}
+	}
+	return s, nil
+}
+
+// DDL returns the CREATE TABLE and CREATE INDEX statements for the schema.
+func (s *SQLSink) DDL() []string {
+	q := s.dialect.QuoteIdent
+
+	defs := make([]string, 0, len(s.columns)+1)
+	for _, col := range s.columns {
+		colType, _ := s.dialect.ColumnType(col.Type) // validated in NewSQLSink
+		def := q(col.Name) + " " + colType
+		if col.Required || col.PrimaryKey {
+			def += " NOT NULL"
+		}
+		if col.Unique && !col.PrimaryKey {
+			def += " UNIQUE"
+		}
+		defs = append(defs, def)
+	}
+	if len(s.keys) > 0 {
+		defs = append(defs, fmt.Sprintf("PRIMARY KEY (%s)", s.quoteAll(s.keys)))
+	}
+
+	stmts := []string{
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", q(s.table), strings.Join(defs, ", ")),
+	}
+	for _, col := range s.columns {
+		if !col.Index || col.PrimaryKey || col.Unique {
+			continue
+		}
+		stmts = append(stmts, fmt.Sprintf(
+			"CREATE INDEX IF NOT EXISTS %s ON %s (%s)",
+			q("idx_"+s.table+"_"+col.Name), q(s.table), q(col.Name),
+		))
+	}
+	return stmts
+}
+
+// Init creates the table and indexes if they don't exist.
+func (s *SQLSink) Init(ctx context.Context) error {
+	for _, stmt := range s.DDL() {
+		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+			return errors.WrapError(err, errors.ErrDestination, "create table")
+		}
+	}
+	return nil
+}
+
+// upsertSQL builds the INSERT statement, adding an ON CONFLICT clause
+// when the schema declares a primary key.
+func (s *SQLSink) upsertSQL() string {
+	q := s.dialect.QuoteIdent
+
+	names := make([]string, len(s.columns))
+	binds := make([]string, len(s.columns))
+	for i, col := range s.columns {
+		names[i] = col.Name
+		binds[i] = s.dialect.Placeholder(i + 1)
+	}
+
+	stmt := fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES (%s)",
+		q(s.table), s.quoteAll(names), strings.Join(binds, ", "),
+	)
+	if len(s.keys) == 0 {
+		return stmt
+	}
+
+	isKey := make(map[string]bool, len(s.keys))
+	for _, k := range s.keys {
+		isKey[k] = true
+	}
+	var sets []string
+	for _, col := range s.columns {
+		if !isKey[col.Name] {
+			sets = append(sets, fmt.Sprintf("%s = excluded.%s", q(col.Name), q(col.Name)))
+		}
+	}
+	if len(sets) == 0 {
+		return stmt + fmt.Sprintf(" ON CONFLICT (%s) DO NOTHING", s.quoteAll(s.keys))
+	}
+	return stmt + fmt.Sprintf(
+		" ON CONFLICT (%s) DO UPDATE SET %s",
+		s.quoteAll(s.keys), strings.Join(sets, ", "),
+	)
+}
+
+// Write upserts records in a single transaction.
+func (s *SQLSink) Write(ctx context.Context, records []map[string]interface{}) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WrapError(err, errors.ErrDestination, "begin transaction")
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, s.upsertSQL())
+	if err != nil {
+		return errors.WrapError(err, errors.ErrDestination, "prepare upsert")
+	}
+	defer stmt.Close()
+
+	for i, record := range records {
+		args, err := s.row(record)
+		if err != nil {
+			return errors.WrapError(err, errors.ErrValidation, fmt.Sprintf("record at index %d", i))
+		}
+		if _, err := stmt.ExecContext(ctx, args...); err != nil {
+			return errors.WrapError(err, errors.ErrDestination, fmt.Sprintf("upsert record at index %d", i))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.WrapError(err, errors.ErrDestination, "commit transaction")
+	}
+	return nil
+}
+
+// row maps a record onto the schema columns using Schema.Source
+// (falling back to the column name).
+func (s *SQLSink) row(record map[string]interface{}) ([]interface{}, error) {
+	args := make([]interface{}, len(s.columns))
+	for i, col := range s.columns {
+		source := col.Source
+		if source == "" {
+			source = col.Name
+		}
+		v, ok := record[source]
+		if (!ok || v == nil) && (col.Required || col.PrimaryKey) {
+			return nil, fmt.Errorf("required field %q is missing", source)
+		}
+		bound, err := bindValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", source, err)
+		}
+		args[i] = bound
+	}
+	return args, nil
+}
+
+// bindValue converts nested values into something database/sql can bind.
+func bindValue(v interface{}) (interface{}, error) {
+	switch x := v.(type) {
+	case map[string]interface{}, []interface{}, []string:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	case json.Number:
+		// Integers bind exactly; anything else keeps its text.
+		if n, err := x.Int64(); err == nil {
+			return n, nil
+		}
+		return x.String(), nil
+	default:
+		return v, nil
+	}
+}
+
+// Close closes the underlying database.
+func (s *SQLSink) Close() error {
+	return s.db.Close()
+}
+
+func (s *SQLSink) quoteAll(names []string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = s.dialect.QuoteIdent(n)
+	}
+	return strings.Join(quoted, ", ")
+}
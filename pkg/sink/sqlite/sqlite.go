@@ -0,0 +1,76 @@
+// Package sqlite registers a SQLite destination with sink.DefaultRegistry.
+// Import it for side effects:
+//
+//	import _ "github.com/saturnines/nexus-core/pkg/sink/sqlite"
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/sink"
+)
+
+// Dialect implements sink.Dialect for SQLite.
+type Dialect struct{}
+
+// QuoteIdent wraps name in double quotes.
+func (Dialect) QuoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// Placeholder returns "?" for every argument.
+func (Dialect) Placeholder(int) string {
+	return "?"
+}
+
+// ColumnType maps schema types onto SQLite storage classes.
+func (Dialect) ColumnType(schemaType string) (string, error) {
+	switch strings.ToLower(schemaType) {
+	case "", "string", "text":
+		return "TEXT", nil
+	case "integer", "int", "bigint":
+		return "INTEGER", nil
+	case "float", "double", "number":
+		return "REAL", nil
+	case "decimal", "numeric":
+		return "NUMERIC", nil
+	case "boolean", "bool":
+		return "INTEGER", nil
+	case "timestamp", "datetime", "date":
+		return "TEXT", nil
+	case "json", "object", "array":
+		return "TEXT", nil
+	default:
+		return "", fmt.Errorf("unsupported column type: %s", schemaType)
+	}
+}
+
+// New opens dest.DSN and returns a SQL sink for it.
+func New(dest *config.Destination) (sink.Sink, error) {
+	if dest.DSN == "" {
+		return nil, fmt.Errorf("sqlite destination requires dsn")
+	}
+	db, err := sql.Open("sqlite3", dest.DSN)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite serialises writers anyway, and a single connection keeps
+	// ":memory:" databases from splitting across the pool.
+	db.SetMaxOpenConns(1)
+
+	s, err := sink.NewSQLSink(db, Dialect{}, dest)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func init() {
+	_ = sink.DefaultRegistry.Register(config.DestinationSQLite, New)
+}
@@ -0,0 +1,147 @@
+package transform
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact decimal number in plain notation, e.g. "12.50". It
+// marshals to JSON as a number and binds to SQL as text, so money amounts
+// and large IDs reach the destination without float rounding.
+type Decimal string
+
+// ParseDecimal parses s, which may use an exponent ("1.5e3"), into a Decimal.
+func ParseDecimal(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	r, ok := parseRat(s)
+	if !ok {
+		return "", fmt.Errorf("invalid decimal: %q", s)
+	}
+	places := decimalPlaces(r)
+	if dot := strings.IndexByte(s, '.'); dot >= 0 && !strings.ContainsAny(s, "eE") {
+		// Keep trailing zeros such as the cents in "12.50".
+		places = len(s) - dot - 1
+	}
+	return Decimal(r.FloatString(places)), nil
+}
+
+func parseRat(s string) (*big.Rat, bool) {
+	// big.Rat also accepts fractions such as "1/3", which are not decimals.
+	if s == "" || strings.Contains(s, "/") {
+		return nil, false
+	}
+	return new(big.Rat).SetString(s)
+}
+
+// decimalPlaces returns how many digits after the point r needs. r must
+// come from a decimal string, so its denominator divides a power of ten.
+func decimalPlaces(r *big.Rat) int {
+	ten := big.NewInt(10)
+	denom := new(big.Int).Set(r.Denom())
+	places := 0
+	for denom.Cmp(big.NewInt(1)) != 0 {
+		g := new(big.Int).GCD(nil, nil, denom, ten)
+		if g.Cmp(big.NewInt(1)) == 0 {
+			break
+		}
+		denom.Quo(denom, g)
+		places++
+	}
+	return places
+}
+
+// String returns the decimal text.
+func (d Decimal) String() string {
+	return string(d)
+}
+
+// Float64 returns the nearest float64.
+func (d Decimal) Float64() (float64, error) {
+	return strconv.ParseFloat(string(d), 64)
+}
+
+// Round returns d rounded to scale digits after the point, halves away
+// from zero.
+func (d Decimal) Round(scale int) Decimal {
+	r, ok := parseRat(string(d))
+	if !ok {
+		return d
+	}
+	return Decimal(r.FloatString(scale))
+}
+
+// MarshalJSON writes the decimal as a bare JSON number.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	if d == "" {
+		return []byte("null"), nil
+	}
+	return []byte(d), nil
+}
+
+// Value implements driver.Valuer.
+func (d Decimal) Value() (driver.Value, error) {
+	return string(d), nil
+}
+
+// DecimalTransform converts values to Decimal, optionally rounding to Scale
+// digits after the point.
+type DecimalTransform struct {
+	Scale *int
+}
+
+func decimalTransformCreator(config map[string]interface{}) (Transformer, error) {
+	t := &DecimalTransform{}
+	switch v := config["scale"].(type) {
+	case nil:
+	case int:
+		t.Scale = &v
+	case float64:
+		scale := int(v)
+		t.Scale = &scale
+	default:
+		return nil, fmt.Errorf("decimal scale must be a number, got %T", v)
+	}
+	if t.Scale != nil && *t.Scale < 0 {
+		return nil, fmt.Errorf("decimal scale cannot be negative")
+	}
+	return t, nil
+}
+
+func (t *DecimalTransform) Transform(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	var d Decimal
+	var err error
+	switch v := value.(type) {
+	case Decimal:
+		d = v
+	case json.Number:
+		d, err = ParseDecimal(v.String())
+	case string:
+		d, err = ParseDecimal(v)
+	case float64:
+		d, err = ParseDecimal(strconv.FormatFloat(v, 'f', -1, 64))
+	case float32:
+		d, err = ParseDecimal(strconv.FormatFloat(float64(v), 'f', -1, 32))
+	case int:
+		d = Decimal(strconv.Itoa(v))
+	case int64:
+		d = Decimal(strconv.FormatInt(v, 10))
+	default:
+		return nil, fmt.Errorf("cannot convert %T to decimal", value)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Scale != nil {
+		d = d.Round(*t.Scale)
+	}
+	return d, nil
+}
@@ -2,6 +2,7 @@
 package transform
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -38,6 +39,7 @@ func NewRegistry() *Registry {
 	r.Register("upper", upperTransformCreator)
 	r.Register("lower", lowerTransformCreator)
 	r.Register("trim", trimTransformCreator)
+	r.Register("decimal", decimalTransformCreator)
 
 	return r
 }
@@ -89,6 +91,10 @@ func (t *IntTransform) Transform(value interface{}) (interface{}, error) {
 		return int(v), nil
 	case float64:
 		return int(v), nil
+	case json.Number:
+		return numberToInt(v.String())
+	case Decimal:
+		return numberToInt(v.String())
 	case string:
 		return strconv.Atoi(v)
 	default:
@@ -96,6 +102,19 @@ func (t *IntTransform) Transform(value interface{}) (interface{}, error) {
 	}
 }
 
+// numberToInt converts JSON number text to an int exactly when it is an
+// integer, truncating anything else.
+func numberToInt(s string) (int, error) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return int(n), nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("cannot convert %q to int", s)
+	}
+	return int(f), nil
+}
+
 // FloatTransform converts values to floats
 type FloatTransform struct{}
 
@@ -115,6 +134,10 @@ func (t *FloatTransform) Transform(value interface{}) (interface{}, error) {
 		return float64(v), nil
 	case int:
 		return float64(v), nil
+	case json.Number:
+		return v.Float64()
+	case Decimal:
+		return v.Float64()
 	case string:
 		return strconv.ParseFloat(v, 64)
 	default:
@@ -143,6 +166,9 @@ func (t *BoolTransform) Transform(value interface{}) (interface{}, error) {
 		return v != 0, nil
 	case float64:
 		return v != 0, nil
+	case json.Number:
+		f, err := v.Float64()
+		return f != 0, err
 	default:
 		return false, fmt.Errorf("cannot convert %T to bool", value)
 	}
@@ -189,6 +215,12 @@ func (t *DateTransform) Transform(value interface{}) (interface{}, error) {
 		inputTime = time.Unix(int64(v), 0).UTC()
 	case int64:
 		inputTime = time.Unix(v, 0).UTC()
+	case json.Number:
+		secs, err := numberToInt(v.String())
+		if err != nil {
+			return nil, err
+		}
+		inputTime = time.Unix(int64(secs), 0).UTC()
 	default:
 		return nil, fmt.Errorf("cannot parse date from %T", value)
 	}
@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/saturnines/nexus-core/pkg/errors"
+	"github.com/saturnines/nexus-core/pkg/jsonpath"
 	"net/http"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/saturnines/nexus-core/pkg/pagination"
 )
@@ -17,24 +20,52 @@ type GraphQLPager struct {
 	builder     *Builder
 	client      *Client
 	cursorKey   string
-	nextPath    []string
-	hasNextPath []string
+	nextPath    *jsonpath.Path
+	hasNextPath *jsonpath.Path
 
 	// Mutable state (protected by mutex)
 	mu         sync.RWMutex
 	hasNext    bool
 	first      bool
 	nextCursor string
+	notBefore  time.Time // earliest time for the next request, from cost hints
 }
 
-// NewPager returns a pagination.Pager for GraphQL cursor paging.
-// Does NOT execute any requests during creation.
+// NewPager returns a pagination.Pager for GraphQL cursor paging, with the
+// cursor and hasNextPage locations given as object keys from the response
+// root. Does NOT execute any requests during creation.
 func NewPager(
 	ctx context.Context,
 	builder *Builder,
 	client *Client,
 	cursorKey string,
 	nextPath, hasNextPath []string,
+) (pagination.Pager, error) {
+	if len(nextPath) == 0 {
+		return nil, errors.WrapError(
+			fmt.Errorf("nextPath cannot be empty"),
+			errors.ErrConfiguration,
+			"create GraphQL pager",
+		)
+	}
+	if len(hasNextPath) == 0 {
+		return nil, errors.WrapError(
+			fmt.Errorf("hasNextPath cannot be empty"),
+			errors.ErrConfiguration,
+			"create GraphQL pager",
+		)
+	}
+	return NewPagerWithPaths(ctx, builder, client, cursorKey, jsonpath.Keys(nextPath...), jsonpath.Keys(hasNextPath...))
+}
+
+// NewPagerWithPaths is like NewPager but takes compiled paths, evaluated
+// against the whole response body (including "data").
+func NewPagerWithPaths(
+	ctx context.Context,
+	builder *Builder,
+	client *Client,
+	cursorKey string,
+	nextPath, hasNextPath *jsonpath.Path,
 ) (pagination.Pager, error) {
 	// Validate inputs
 	if builder == nil {
@@ -58,14 +89,14 @@ func NewPager(
 			"create GraphQL pager",
 		)
 	}
-	if len(nextPath) == 0 {
+	if nextPath == nil {
 		return nil, errors.WrapError(
 			fmt.Errorf("nextPath cannot be empty"),
 			errors.ErrConfiguration,
 			"create GraphQL pager",
 		)
 	}
-	if len(hasNextPath) == 0 {
+	if hasNextPath == nil {
 		return nil, errors.WrapError(
 			fmt.Errorf("hasNextPath cannot be empty"),
 			errors.ErrConfiguration,
@@ -123,8 +154,11 @@ func (p *GraphQLPager) NextRequest() (*http.Request, error) {
 func (p *GraphQLPager) UpdateState(resp *http.Response) error {
 	defer resp.Body.Close()
 
+	// Numbers stay json.Number so numeric cursors are sent back exactly.
 	var data map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+	dec := json.NewDecoder(resp.Body)
+	dec.UseNumber()
+	if err := dec.Decode(&data); err != nil {
 		return errors.WrapError(err, errors.ErrHTTPResponse, "decode GraphQL pager response")
 	}
 
@@ -144,18 +178,18 @@ func (p *GraphQLPager) UpdateState(resp *http.Response) error {
 	// Mark that we've made the first request
 	p.first = false
 
+	now := time.Now()
+	p.notBefore = now.Add(ThrottleDelay(data, now))
+
 	// Extract endCursor and store it separately (don't mutate builder)
-	endCursor := traverse(data, p.nextPath...)
-	if str, ok := endCursor.(string); ok && str != "" {
-		p.nextCursor = str
-	} else {
-		p.nextCursor = ""
+	p.nextCursor = ""
+	if endCursor, err := p.nextPath.Lookup(data); err == nil {
+		p.nextCursor = cursorString(endCursor)
 	}
 
 	// Extract hasNextPage
-	hasNext := traverse(data, p.hasNextPath...)
-	if b, ok := hasNext.(bool); ok {
-		p.hasNext = b
+	if hasNext, err := p.hasNextPath.LookupBool(data); err == nil {
+		p.hasNext = hasNext
 	} else {
 		// If we can't determine hasNext, assume no more pages
 		p.hasNext = false
@@ -164,6 +198,24 @@ func (p *GraphQLPager) UpdateState(resp *http.Response) error {
 	return nil
 }
 
+// cursorString converts an endCursor to the string sent back as the cursor
+// variable. Numeric cursors such as 64-bit IDs keep every digit; null
+// yields "".
+func cursorString(v interface{}) string {
+	switch c := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return c
+	case json.Number:
+		return c.String()
+	case float64:
+		return strconv.FormatFloat(c, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", c)
+	}
+}
+
 // HasMore returns whether more pages are available (thread-safe).
 func (p *GraphQLPager) HasMore() bool {
 	p.mu.RLock()
@@ -171,6 +223,17 @@ func (p *GraphQLPager) HasMore() bool {
 	return p.hasNext
 }
 
+// Delay returns how long to wait before the next page so the query cost
+// budget reported by the last response is not exceeded.
+func (p *GraphQLPager) Delay() time.Duration {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if d := time.Until(p.notBefore); d > 0 {
+		return d
+	}
+	return 0
+}
+
 // Reset resets pagination to start from the beginning.
 func (p *GraphQLPager) Reset() {
 	p.mu.Lock()
@@ -179,17 +242,36 @@ func (p *GraphQLPager) Reset() {
 	p.hasNext = true
 	p.first = true
 	p.nextCursor = ""
+	p.notBefore = time.Time{}
 }
 
-// traverse digs into nested maps via a path of keys.
-func traverse(m map[string]interface{}, path ...string) interface{} {
-	cur := interface{}(m)
-	for _, key := range path {
-		if mp, ok := cur.(map[string]interface{}); ok {
-			cur = mp[key]
-		} else {
-			return nil
-		}
+// Snapshot returns the cursor for the next request.
+func (p *GraphQLPager) Snapshot() pagination.State {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return pagination.State{
+		Type:    "graphql",
+		Cursor:  p.nextCursor,
+		Started: !p.first,
+		Done:    !p.first && !p.hasNext,
+	}
+}
+
+// Restore positions the pager at a previously saved cursor.
+func (p *GraphQLPager) Restore(state pagination.State) error {
+	if state.Type != "" && state.Type != "graphql" {
+		return errors.WrapError(
+			fmt.Errorf("state is for %q pager, not %q", state.Type, "graphql"),
+			errors.ErrPagination,
+			"restore GraphQL pager state",
+		)
 	}
-	return cur
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.nextCursor = state.Cursor
+	p.first = !state.Started
+	p.hasNext = !state.Done
+	return nil
 }
@@ -0,0 +1,87 @@
+package graphql
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/saturnines/nexus-core/pkg/jsonpath"
+)
+
+var (
+	shopifyCostPath     = jsonpath.MustCompile("extensions.cost")
+	githubRateLimitPath = jsonpath.MustCompile("data.rateLimit")
+)
+
+// ThrottleDelay reads query cost hints from a decoded GraphQL response and
+// returns how long to wait before the next request, or 0 if there is budget
+// left. It understands Shopify's extensions.cost.throttleStatus and GitHub's
+// rateLimit { remaining resetAt } (when the query selects it).
+func ThrottleDelay(data map[string]interface{}, now time.Time) time.Duration {
+	if d := shopifyDelay(data); d > 0 {
+		return d
+	}
+	return githubDelay(data, now)
+}
+
+// shopifyDelay waits until enough points are restored for the requested
+// query cost to fit into what is currently available.
+func shopifyDelay(data map[string]interface{}) time.Duration {
+	node, _ := shopifyCostPath.Get(data)
+	cost, ok := node.(map[string]interface{})
+	if !ok {
+		return 0
+	}
+	status, ok := cost["throttleStatus"].(map[string]interface{})
+	if !ok {
+		return 0
+	}
+
+	requested, _ := number(cost["requestedQueryCost"])
+	available, okAvail := number(status["currentlyAvailable"])
+	restoreRate, okRate := number(status["restoreRate"])
+	if !okAvail || !okRate || restoreRate <= 0 || available >= requested {
+		return 0
+	}
+	return time.Duration((requested - available) / restoreRate * float64(time.Second))
+}
+
+// githubDelay waits until resetAt once the remaining points no longer cover
+// the cost of the last query.
+func githubDelay(data map[string]interface{}, now time.Time) time.Duration {
+	node, _ := githubRateLimitPath.Get(data)
+	rl, ok := node.(map[string]interface{})
+	if !ok {
+		return 0
+	}
+	remaining, ok := number(rl["remaining"])
+	if !ok {
+		return 0
+	}
+	cost, _ := number(rl["cost"])
+	if remaining > 0 && remaining >= cost {
+		return 0
+	}
+
+	resetAt, _ := rl["resetAt"].(string)
+	at, err := time.Parse(time.RFC3339, resetAt)
+	if err != nil {
+		return 0
+	}
+	if d := at.Sub(now); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// number reads a float64 or, from responses decoded with UseNumber, a
+// json.Number.
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -2,7 +2,9 @@
 package rest
 
 import (
+	"bytes"
 	"context"
+	"io"
 	"net/http"
 	"os"
 	"regexp"
@@ -18,6 +20,7 @@ type Builder struct {
 	Headers     map[string]string
 	QueryParams map[string]string
 	AuthHandler auth.Handler
+	SOAP        *SOAP // when set, requests are POSTed as SOAP envelopes
 }
 
 // NewBuilder constructs a Builder.
@@ -44,11 +47,27 @@ func (b *Builder) Build(ctx context.Context) (*http.Request, error) {
 	// Substitute template variables in the URL
 	url := b.substituteTemplateVariables(b.URL)
 	
-	req, err := http.NewRequestWithContext(ctx, b.Method, url, nil)
+	method, body := b.Method, io.Reader(nil)
+	if b.SOAP != nil {
+		method = http.MethodPost
+		body = bytes.NewReader(b.SOAP.envelope(
+			b.substitute(b.SOAP.Header, xmlEscape),
+			b.substitute(b.SOAP.Body, xmlEscape),
+		))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, err
 	}
 
+	if b.SOAP != nil {
+		req.Header.Set("Content-Type", b.SOAP.contentType())
+		if b.SOAP.Version != "1.2" {
+			req.Header.Set("SOAPAction", `"`+b.SOAP.Action+`"`)
+		}
+	}
+
 	for k, v := range b.Headers {
 		// Also substitute template variables in header values
 		req.Header.Set(k, b.substituteTemplateVariables(v))
@@ -74,6 +93,12 @@ func (b *Builder) Build(ctx context.Context) (*http.Request, error) {
 
 // substituteTemplateVariables replaces {{VAR_NAME}} with environment variable values
 func (b *Builder) substituteTemplateVariables(text string) string {
+	return b.substitute(text, nil)
+}
+
+// substitute is substituteTemplateVariables with values passed through
+// escape, e.g. for XML templates. A nil escape inserts values as-is.
+func (b *Builder) substitute(text string, escape func(string) string) string {
 	// This regex matches {{VARIABLE_NAME}} patterns
 	templatePattern := regexp.MustCompile(`\{\{([^}]+)\}\}`)
 	
@@ -83,6 +108,9 @@ func (b *Builder) substituteTemplateVariables(text string) string {
 		
 		// Get environment variable value
 		if value := os.Getenv(varName); value != "" {
+			if escape != nil {
+				return escape(value)
+			}
 			return value
 		}
 		return match
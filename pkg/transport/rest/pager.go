@@ -59,6 +59,9 @@ func paginationConfigToOptions(p *config.Pagination) map[string]interface{} {
 	case config.PaginationTypeCursor:
 		opts["cursorParam"] = p.CursorParam
 		opts["nextPath"] = p.CursorPath
+
+	case config.PaginationTypeLink:
+		opts["nextLinkPath"] = p.NextLinkPath
 	}
 
 	return opts
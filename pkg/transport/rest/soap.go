@@ -0,0 +1,60 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+)
+
+// SOAP envelope namespaces.
+const (
+	SOAP11Namespace = "http://schemas.xmlsoap.org/soap/envelope/"
+	SOAP12Namespace = "http://www.w3.org/2003/05/soap-envelope"
+)
+
+// SOAP makes a Builder POST its requests as SOAP envelopes.
+type SOAP struct {
+	Version string // "1.1" (default) or "1.2"
+	Action  string // SOAPAction of the operation
+	Header  string // XML placed in the envelope header
+	Body    string // XML placed in the envelope body
+}
+
+// envelope wraps header and body, in which {{VAR}} has already been
+// replaced, in a SOAP envelope.
+func (s *SOAP) envelope(header, body string) []byte {
+	ns := SOAP11Namespace
+	if s.Version == "1.2" {
+		ns = SOAP12Namespace
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<soap:Envelope xmlns:soap="` + ns + `">`)
+	if strings.TrimSpace(header) != "" {
+		buf.WriteString("<soap:Header>" + header + "</soap:Header>")
+	}
+	buf.WriteString("<soap:Body>" + body + "</soap:Body>")
+	buf.WriteString("</soap:Envelope>")
+	return buf.Bytes()
+}
+
+// contentType returns the request Content-Type. SOAP 1.2 carries the
+// action in it instead of a SOAPAction header.
+func (s *SOAP) contentType() string {
+	if s.Version == "1.2" {
+		ct := "application/soap+xml; charset=utf-8"
+		if s.Action != "" {
+			ct += `; action="` + s.Action + `"`
+		}
+		return ct
+	}
+	return "text/xml; charset=utf-8"
+}
+
+// xmlEscape escapes s for use in XML text and attribute values.
+func xmlEscape(s string) string {
+	var buf strings.Builder
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
@@ -0,0 +1,235 @@
+package checkpoint_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/saturnines/nexus-core/pkg/checkpoint"
+	"github.com/saturnines/nexus-core/pkg/checkpoint/sqlite"
+	"github.com/saturnines/nexus-core/pkg/errors"
+	"github.com/saturnines/nexus-core/pkg/pagination"
+	"github.com/saturnines/nexus-core/pkg/transport/graphql"
+)
+
+func testStores(t *testing.T) map[string]checkpoint.StateStore {
+	t.Helper()
+	fileStore, err := checkpoint.NewFileStore(filepath.Join(t.TempDir(), "state"))
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	sqliteStore, err := sqlite.Open(filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatalf("sqlite.Open: %v", err)
+	}
+	t.Cleanup(func() { sqliteStore.Close() })
+
+	return map[string]checkpoint.StateStore{
+		"file":   fileStore,
+		"sqlite": sqliteStore,
+	}
+}
+
+func TestStateStore_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			state, err := store.Load(ctx, "orders/us")
+			if err != nil || state != nil {
+				t.Fatalf("Expected no state for new key, got %+v, %v", state, err)
+			}
+
+			want := pagination.State{Type: "cursor", Cursor: "abc", Started: true}
+			if err := store.Save(ctx, "orders/us", want); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+			want.Cursor = "def"
+			if err := store.Save(ctx, "orders/us", want); err != nil {
+				t.Fatalf("Save overwrite: %v", err)
+			}
+
+			got, err := store.Load(ctx, "orders/us")
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if got == nil || *got != want {
+				t.Errorf("Expected %+v, got %+v", want, got)
+			}
+
+			if err := store.Delete(ctx, "orders/us"); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+			if err := store.Delete(ctx, "orders/us"); err != nil {
+				t.Errorf("Deleting a missing key should not fail: %v", err)
+			}
+			if got, _ := store.Load(ctx, "orders/us"); got != nil {
+				t.Errorf("Expected state to be deleted, got %+v", got)
+			}
+		})
+	}
+}
+
+func jsonResponse(body string, header http.Header) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestPagers_SnapshotRestore(t *testing.T) {
+	base, _ := http.NewRequest(http.MethodGet, "http://api.test/items", nil)
+
+	tests := []struct {
+		name      string
+		newPager  func() pagination.Pager
+		response  *http.Response
+		wantQuery string
+	}{
+		{
+			name: "cursor",
+			newPager: func() pagination.Pager {
+				p, _ := pagination.NewThreadSafeCursorPager(nil, base, "cursor", "next")
+				return p
+			},
+			response:  jsonResponse(`{"data": [1], "next": "c2"}`, nil),
+			wantQuery: "cursor=c2",
+		},
+		{
+			name: "page",
+			newPager: func() pagination.Pager {
+				return pagination.NewPagePager(nil, base, "page", "size", "more", 1, 10)
+			},
+			response:  jsonResponse(`{"more": true}`, nil),
+			wantQuery: "page=2&size=10",
+		},
+		{
+			name: "offset",
+			newPager: func() pagination.Pager {
+				return pagination.NewOffsetPager(nil, base, "offset", "limit", "more", 0, 10)
+			},
+			response:  jsonResponse(`{"more": true}`, nil),
+			wantQuery: "limit=10&offset=10",
+		},
+		{
+			name: "link",
+			newPager: func() pagination.Pager {
+				return pagination.NewLinkPagerWithNextLinkPath(nil, base, "next", false)
+			},
+			response:  jsonResponse(`{"next": "/items?after=x"}`, nil),
+			wantQuery: "after=x",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first := tt.newPager()
+			if _, err := first.NextRequest(); err != nil {
+				t.Fatalf("NextRequest: %v", err)
+			}
+			if err := first.UpdateState(tt.response); err != nil {
+				t.Fatalf("UpdateState: %v", err)
+			}
+			state := first.(pagination.Checkpointer).Snapshot()
+			if state.Type != tt.name || state.Done {
+				t.Fatalf("Unexpected snapshot %+v", state)
+			}
+
+			resumed := tt.newPager()
+			if err := resumed.(pagination.Checkpointer).Restore(state); err != nil {
+				t.Fatalf("Restore: %v", err)
+			}
+			req, err := resumed.NextRequest()
+			if err != nil || req == nil {
+				t.Fatalf("Expected a resumed request, got %v, %v", req, err)
+			}
+			if req.URL.RawQuery != tt.wantQuery {
+				t.Errorf("Expected query %q, got %q", tt.wantQuery, req.URL.RawQuery)
+			}
+		})
+	}
+}
+
+func TestPagers_RestoreDoneAndMismatchedType(t *testing.T) {
+	base, _ := http.NewRequest(http.MethodGet, "http://api.test/items", nil)
+	pager := pagination.NewPagePager(nil, base, "page", "size", "", 1, 10)
+
+	if err := pager.Restore(pagination.State{Type: "page", Page: 5, Started: true, Done: true}); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if req, _ := pager.NextRequest(); req != nil {
+		t.Errorf("Expected no request after restoring a finished state, got %s", req.URL)
+	}
+
+	err := pager.Restore(pagination.State{Type: "cursor", Cursor: "abc"})
+	if !errors.Is(err, errors.ErrPagination) {
+		t.Errorf("Expected ErrPagination for mismatched state, got %v", err)
+	}
+}
+
+func TestGraphQLPager_SnapshotRestore(t *testing.T) {
+	newPager := func() pagination.Pager {
+		builder := graphql.NewBuilder("http://api.test/graphql", `query($after: String) { items }`, nil, nil, nil)
+		p, err := graphql.NewPager(context.Background(), builder, graphql.NewClient(http.DefaultClient),
+			"after", []string{"data", "pageInfo", "endCursor"}, []string{"data", "pageInfo", "hasNextPage"})
+		if err != nil {
+			t.Fatalf("NewPager: %v", err)
+		}
+		return p
+	}
+
+	first := newPager()
+	if err := first.UpdateState(jsonResponse(`{"data": {"pageInfo": {"endCursor": "e1", "hasNextPage": true}}}`, nil)); err != nil {
+		t.Fatalf("UpdateState: %v", err)
+	}
+	state := first.(pagination.Checkpointer).Snapshot()
+	want := pagination.State{Type: "graphql", Cursor: "e1", Started: true}
+	if state != want {
+		t.Fatalf("Expected snapshot %+v, got %+v", want, state)
+	}
+
+	resumed := newPager()
+	if err := resumed.(pagination.Checkpointer).Restore(state); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	req, err := resumed.NextRequest()
+	if err != nil || req == nil {
+		t.Fatalf("Expected a resumed request, got %v, %v", req, err)
+	}
+	var body struct {
+		Variables map[string]interface{} `json:"variables"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		t.Fatalf("decode request body: %v", err)
+	}
+	if body.Variables["after"] != "e1" {
+		t.Errorf("Expected after=e1, got %v", body.Variables)
+	}
+}
+
+func TestWatermarkStore_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ws := store.(checkpoint.WatermarkStore)
+			if v, err := ws.LoadWatermark(ctx, "orders"); err != nil || v != "" {
+				t.Fatalf("Expected empty watermark for new key, got %q, %v", v, err)
+			}
+			for _, v := range []string{"2024-01-01T00:00:00Z", "2024-02-01T00:00:00Z"} {
+				if err := ws.SaveWatermark(ctx, "orders", v); err != nil {
+					t.Fatalf("SaveWatermark: %v", err)
+				}
+			}
+			if v, err := ws.LoadWatermark(ctx, "orders"); err != nil || v != "2024-02-01T00:00:00Z" {
+				t.Errorf("Expected latest watermark, got %q, %v", v, err)
+			}
+		})
+	}
+}
@@ -609,3 +609,69 @@ func TestGraphQL_CursorPagination_SpecialCharacters(t *testing.T) {
 
 	t.Logf("Successfully handled cursor with special characters")
 }
+
+// TEST 6: Numeric endCursor values are sent back with every digit
+func TestGraphQL_CursorPagination_NumericCursor(t *testing.T) {
+	var cursors []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var gqlReq map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&gqlReq)
+		variables, _ := gqlReq["variables"].(map[string]interface{})
+		cursor, _ := variables["cursor"].(string)
+		cursors = append(cursors, cursor)
+
+		w.Header().Set("Content-Type", "application/json")
+		switch cursor {
+		case "":
+			w.Write([]byte(`{"data": {"items": {"edges": [{"node": {"id": "1"}}],
+				"pageInfo": {"endCursor": 12345678901234567890, "hasNextPage": true}}}}`))
+		case "12345678901234567890":
+			w.Write([]byte(`{"data": {"items": {"edges": [{"node": {"id": "2"}}],
+				"pageInfo": {"endCursor": null, "hasNextPage": false}}}}`))
+		default:
+			t.Errorf("Unexpected cursor: %q", cursor)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer mockServer.Close()
+
+	cfg := &config.Pipeline{
+		Name: "graphql-cursor-numeric-test",
+		Source: config.Source{
+			Type: config.SourceTypeGraphQL,
+			GraphQLConfig: &config.GraphQLSource{
+				Endpoint: mockServer.URL,
+				Query:    `query($cursor: String) { items(after: $cursor) { edges { node { id } } pageInfo { endCursor hasNextPage } } }`,
+				ResponseMapping: config.ResponseMapping{
+					RootPath: "items.edges",
+					Fields: []config.Field{
+						{Name: "id", Path: "node.id"},
+					},
+				},
+			},
+		},
+		Pagination: &config.Pagination{
+			Type:        config.PaginationTypeCursor,
+			CursorParam: "cursor",
+			CursorPath:  "data.items.pageInfo.endCursor",
+			HasMorePath: "data.items.pageInfo.hasNextPage",
+		},
+	}
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+
+	results, err := connector.Extract(context.Background())
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Errorf("Expected 2 results, got %d", len(results))
+	}
+	if len(cursors) != 2 || cursors[1] != "12345678901234567890" {
+		t.Errorf("Expected the numeric cursor to be sent back exactly, got %q", cursors)
+	}
+}
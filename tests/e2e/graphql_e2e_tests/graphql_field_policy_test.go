@@ -0,0 +1,74 @@
+package graphql_e2e_tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/core"
+	"github.com/saturnines/nexus-core/pkg/errors"
+)
+
+// TestGraphQL_FieldPolicies checks that missing and null values are handled
+// exactly as in REST sources.
+func TestGraphQL_FieldPolicies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"users": [
+			{"id": "1", "email": null},
+			{"id": "2", "email": "b@example.com"}
+		]}}`))
+	}))
+	defer server.Close()
+
+	newConfig := func(fields ...config.Field) *config.Pipeline {
+		return &config.Pipeline{
+			Name: "graphql-field-policy-test",
+			Source: config.Source{
+				Type: config.SourceTypeGraphQL,
+				GraphQLConfig: &config.GraphQLSource{
+					Endpoint: server.URL,
+					Query:    `{ users { id email } }`,
+					ResponseMapping: config.ResponseMapping{
+						RootPath: "users",
+						Fields:   fields,
+					},
+				},
+			},
+		}
+	}
+
+	cfg := newConfig(
+		config.Field{Name: "id", Path: "id"},
+		config.Field{Name: "email", Path: "email", OnNull: config.FieldPolicyNull},
+		config.Field{Name: "phone", Path: "phone", OnMissing: config.FieldPolicyDefault, DefaultValue: "n/a"},
+	)
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	results, err := connector.Extract(context.Background())
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	want := []map[string]interface{}{
+		{"id": "1", "email": nil, "phone": "n/a"},
+		{"id": "2", "email": "b@example.com", "phone": "n/a"},
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("Expected %v, got %v", want, results)
+	}
+
+	cfg = newConfig(config.Field{Name: "email", Path: "emails"})
+	cfg.Strict = true
+	connector, err = core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	if _, err := connector.Extract(context.Background()); !errors.Is(err, errors.ErrExtraction) {
+		t.Errorf("Expected strict mode to fail with ErrExtraction, got %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package graphql_e2e_tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/core"
+)
+
+// TestGraphQL_CursorPaginationJSONPath uses bracket syntax in the pagination
+// paths, relative to "data" like root_path.
+func TestGraphQL_CursorPaginationJSONPath(t *testing.T) {
+	var cursors []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		cursor, _ := req.Variables["after"].(string)
+		cursors = append(cursors, cursor)
+
+		w.Header().Set("Content-Type", "application/json")
+		if cursor == "" {
+			w.Write([]byte(`{"data": {"users": {
+				"nodes": [{"id": "1"}, {"id": "2"}],
+				"edges": [{"cursor": "c1"}, {"cursor": "c2"}],
+				"page.info": {"more": true}
+			}}}`))
+			return
+		}
+		w.Write([]byte(`{"data": {"users": {
+			"nodes": [{"id": "3"}],
+			"edges": [{"cursor": "c3"}],
+			"page.info": {"more": false}
+		}}}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Pipeline{
+		Name: "graphql-paths-test",
+		Source: config.Source{
+			Type: config.SourceTypeGraphQL,
+			GraphQLConfig: &config.GraphQLSource{
+				Endpoint: server.URL,
+				Query:    `query($after: String) { users(after: $after) { nodes { id } } }`,
+				ResponseMapping: config.ResponseMapping{
+					RootPath: "users.nodes",
+					Fields:   []config.Field{{Name: "id", Path: "id"}},
+				},
+			},
+		},
+		Pagination: &config.Pagination{
+			Type:        config.PaginationTypeCursor,
+			CursorParam: "after",
+			CursorPath:  "users.edges[-1].cursor",
+			HasMorePath: "users['page.info'].more",
+		},
+	}
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	results, err := connector.Extract(context.Background())
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 records, got %d", len(results))
+	}
+	if len(cursors) != 2 || cursors[1] != "c2" {
+		t.Errorf("Expected second request with cursor c2, got %v", cursors)
+	}
+}
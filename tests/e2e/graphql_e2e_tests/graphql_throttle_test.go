@@ -0,0 +1,220 @@
+package graphql_e2e_tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/core"
+	"github.com/saturnines/nexus-core/pkg/errors"
+)
+
+// shopifyThrottled is a THROTTLED response that needs 0.1s of restore
+// before the 100 point query fits.
+const shopifyThrottled = `{
+	"errors": [{"message": "Throttled", "extensions": {"code": "THROTTLED"}}],
+	"extensions": {"cost": {
+		"requestedQueryCost": 100,
+		"throttleStatus": {"maximumAvailable": 1000, "currentlyAvailable": 95, "restoreRate": 50}
+	}}
+}`
+
+// throttleRetry retries quickly so tests measure the throttle delay alone.
+var throttleRetry = config.RetryConfig{
+	MaxAttempts:       3,
+	InitialBackoff:    0.001,
+	BackoffMultiplier: 1,
+}
+
+func TestGraphQL_RetryThrottledWithCostHint(t *testing.T) {
+	var mu sync.Mutex
+	var times []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		times = append(times, time.Now())
+		n := len(times)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if n == 1 {
+			w.Write([]byte(shopifyThrottled))
+			return
+		}
+		w.Write([]byte(`{"data": {"users": [{"id": "1"}]}}`))
+	}))
+	defer server.Close()
+
+	retry := throttleRetry
+	retry.RetryableGraphQLErrors = []string{"THROTTLED"}
+	connector, err := core.NewConnector(usersConfig(server.URL, `query { users { id } }`, retry))
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	results, err := connector.Extract(context.Background())
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("Expected 1 record, got %d", len(results))
+	}
+	if len(times) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(times))
+	}
+	if gap := times[1].Sub(times[0]); gap < 90*time.Millisecond {
+		t.Errorf("Expected retry to wait for the restore rate (~100ms), waited %v", gap)
+	}
+}
+
+func TestGraphQL_RetryMatchesTypeAndMessage(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		retryable []string
+		wantCalls int32
+	}{
+		{"github type", `{"errors": [{"type": "RATE_LIMITED", "message": "API rate limit exceeded"}]}`, []string{"rate_limited"}, 2},
+		{"message substring", `{"errors": [{"message": "Query cost exceeds budget, try again"}]}`, []string{"try again"}, 2},
+		{"not listed", `{"errors": [{"message": "Field 'x' doesn't exist"}]}`, []string{"THROTTLED"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if atomic.AddInt32(&calls, 1) == 1 {
+					w.Write([]byte(tt.body))
+					return
+				}
+				w.Write([]byte(`{"data": {"users": [{"id": "1"}]}}`))
+			}))
+			defer server.Close()
+
+			retry := throttleRetry
+			retry.RetryableGraphQLErrors = tt.retryable
+			connector, err := core.NewConnector(usersConfig(server.URL, `query { users { id } }`, retry))
+			if err != nil {
+				t.Fatalf("Failed to create connector: %v", err)
+			}
+			_, err = connector.Extract(context.Background())
+			if got := atomic.LoadInt32(&calls); got != tt.wantCalls {
+				t.Errorf("Expected %d requests, got %d", tt.wantCalls, got)
+			}
+			if tt.wantCalls == 1 && !errors.Is(err, errors.ErrGraphQL) {
+				t.Errorf("Expected ErrGraphQL, got %v", err)
+			}
+			if tt.wantCalls == 2 && err != nil {
+				t.Errorf("Expected retry to succeed, got %v", err)
+			}
+		})
+	}
+}
+
+func TestGraphQL_RetryExhaustedReportsGraphQLError(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"errors": [{"message": "Throttled", "extensions": {"code": "THROTTLED"}}]}`))
+	}))
+	defer server.Close()
+
+	retry := throttleRetry
+	retry.RetryableGraphQLErrors = []string{"THROTTLED"}
+	connector, err := core.NewConnector(usersConfig(server.URL, `query { users { id } }`, retry))
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	_, err = connector.Extract(context.Background())
+	if !errors.Is(err, errors.ErrGraphQL) {
+		t.Fatalf("Expected ErrGraphQL, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("Expected 3 attempts, got %d", got)
+	}
+}
+
+func TestGraphQL_PagerWaitsForRateLimitReset(t *testing.T) {
+	var mu sync.Mutex
+	var times []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		mu.Lock()
+		times = append(times, time.Now())
+		mu.Unlock()
+
+		resp := map[string]interface{}{
+			"data": map[string]interface{}{
+				"repos": map[string]interface{}{
+					"nodes":    []interface{}{map[string]interface{}{"id": "b"}},
+					"pageInfo": map[string]interface{}{"endCursor": nil, "hasNextPage": false},
+				},
+			},
+		}
+		if req.Variables["after"] == nil {
+			resp["data"] = map[string]interface{}{
+				"repos": map[string]interface{}{
+					"nodes":    []interface{}{map[string]interface{}{"id": "a"}},
+					"pageInfo": map[string]interface{}{"endCursor": "c1", "hasNextPage": true},
+				},
+				"rateLimit": map[string]interface{}{
+					"cost":      1,
+					"remaining": 0,
+					"resetAt":   time.Now().Add(150 * time.Millisecond).UTC().Format(time.RFC3339Nano),
+				},
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	cfg := &config.Pipeline{
+		Name: "graphql-ratelimit-test",
+		Source: config.Source{
+			Type: config.SourceTypeGraphQL,
+			GraphQLConfig: &config.GraphQLSource{
+				Endpoint: server.URL,
+				Query:    `query($after: String) { repos(after: $after) { nodes { id } pageInfo { endCursor hasNextPage } } rateLimit { cost remaining resetAt } }`,
+				ResponseMapping: config.ResponseMapping{
+					RootPath: "data.repos.nodes",
+					Fields:   []config.Field{{Name: "id", Path: "id"}},
+				},
+			},
+		},
+		Pagination: &config.Pagination{
+			Type:        config.PaginationTypeCursor,
+			CursorParam: "after",
+			CursorPath:  "data.repos.pageInfo.endCursor",
+			HasMorePath: "data.repos.pageInfo.hasNextPage",
+		},
+	}
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	results, err := connector.Extract(context.Background())
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("Expected 2 records, got %d", len(results))
+	}
+	if len(times) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(times))
+	}
+	if gap := times[1].Sub(times[0]); gap < 100*time.Millisecond {
+		t.Errorf("Expected the second page to wait for resetAt, waited %v", gap)
+	}
+}
@@ -0,0 +1,25 @@
+package graphql_e2e_tests
+
+import (
+	"github.com/saturnines/nexus-core/pkg/config"
+)
+
+// usersConfig returns a GraphQL pipeline that sends query to url, reads the
+// ids under data.users and retries as retry says.
+func usersConfig(url, query string, retry config.RetryConfig) *config.Pipeline {
+	return &config.Pipeline{
+		Name: "graphql-users-test",
+		Source: config.Source{
+			Type: config.SourceTypeGraphQL,
+			GraphQLConfig: &config.GraphQLSource{
+				Endpoint: url,
+				Query:    query,
+				ResponseMapping: config.ResponseMapping{
+					RootPath: "data.users",
+					Fields:   []config.Field{{Name: "id", Path: "id"}},
+				},
+			},
+		},
+		RetryConfig: &retry,
+	}
+}
@@ -0,0 +1,59 @@
+package graphql_e2e_tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/core"
+)
+
+func TestGraphQL_RetryQueriesNotMutations(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		wantCalls int32
+		wantErr   bool
+	}{
+		{"anonymous query", `{ users { id } }`, 2, false},
+		{"named query", `query Users { users { id } }`, 2, false},
+		{"commented mutation keyword", "# mutation\nquery { users { id } }", 2, false},
+		{"mutation", `mutation { users: createUser { id } }`, 1, true},
+		{"query then mutation", `query A { users { id } } mutation B { createUser { id } }`, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&calls, 1) == 1 {
+					w.WriteHeader(http.StatusBadGateway)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"data": {"users": [{"id": "1"}]}}`))
+			}))
+			defer server.Close()
+
+			connector, err := core.NewConnector(usersConfig(server.URL, tt.query, config.RetryConfig{
+				MaxAttempts:       3,
+				InitialBackoff:    0.01,
+				BackoffMultiplier: 1,
+				RetryableStatuses: []int{502},
+			}))
+			if err != nil {
+				t.Fatalf("Failed to create connector: %v", err)
+			}
+			_, err = connector.Extract(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if got := atomic.LoadInt32(&calls); got != tt.wantCalls {
+				t.Errorf("Expected %d requests, got %d", tt.wantCalls, got)
+			}
+		})
+	}
+}
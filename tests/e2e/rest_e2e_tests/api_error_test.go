@@ -0,0 +1,181 @@
+package rest_e2e_tests_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/core"
+	errors2 "github.com/saturnines/nexus-core/pkg/errors"
+)
+
+// slackMapping reads Slack-style bodies, which report failures through
+// "ok" and "error" members.
+var slackMapping = config.ResponseMapping{
+	RootPath:    "members",
+	SuccessPath: "ok",
+	ErrorPath:   "error",
+	Fields:      []config.Field{{Name: "id", Path: "id"}},
+}
+
+func TestConnector_APIError_SuccessFlagFalse(t *testing.T) {
+	server := jsonServer(`{"ok": false, "error": "invalid_auth"}`)
+	defer server.Close()
+
+	connector, err := core.NewConnector(restConfig("api-error-test", server.URL, slackMapping))
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	_, err = connector.Extract(context.Background())
+	if !errors2.Is(err, errors2.ErrAPI) {
+		t.Fatalf("Expected ErrAPI, got %v", err)
+	}
+	var apiErr *errors2.APIError
+	if !errors2.As(err, &apiErr) {
+		t.Fatalf("Expected *APIError, got %T", err)
+	}
+	if apiErr.Message != "invalid_auth" || apiErr.Retryable {
+		t.Errorf("Unexpected APIError: %+v", apiErr)
+	}
+}
+
+func TestConnector_APIError_SuccessFlagTrue(t *testing.T) {
+	server := jsonServer(`{"ok": true, "members": [{"id": "U1"}, {"id": "U2"}]}`)
+	defer server.Close()
+
+	connector, err := core.NewConnector(restConfig("api-error-test", server.URL, slackMapping))
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	results, err := connector.Extract(context.Background())
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("Expected 2 results, got %d", len(results))
+	}
+}
+
+func TestConnector_APIError_ErrorPathOnly(t *testing.T) {
+	server := jsonServer(`{"members": [], "error": {"code": 17, "message": "quota exceeded"}}`)
+	defer server.Close()
+
+	cfg := restConfig("api-error-test", server.URL, slackMapping)
+	cfg.Source.ResponseMapping.SuccessPath = ""
+	cfg.Source.ResponseMapping.ErrorPath = "error.message"
+	cfg.Source.ResponseMapping.ErrorCodePath = "error.code"
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	_, err = connector.Extract(context.Background())
+	var apiErr *errors2.APIError
+	if !errors2.As(err, &apiErr) {
+		t.Fatalf("Expected *APIError, got %v", err)
+	}
+	if apiErr.Message != "quota exceeded" || apiErr.Code != "17" {
+		t.Errorf("Unexpected APIError: %+v", apiErr)
+	}
+}
+
+func TestConnector_APIError_RetryableCode(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.AddInt32(&requests, 1) < 3 {
+			w.Write([]byte(`{"ok": false, "error": "ratelimited"}`))
+			return
+		}
+		w.Write([]byte(`{"ok": true, "members": [{"id": "U1"}]}`))
+	}))
+	defer server.Close()
+
+	cfg := restConfig("api-error-test", server.URL, slackMapping)
+	cfg.RetryConfig = &config.RetryConfig{
+		MaxAttempts:         3,
+		InitialBackoff:      0.01,
+		BackoffMultiplier:   1,
+		RetryableErrorCodes: []string{"ratelimited"},
+	}
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	results, err := connector.Extract(context.Background())
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("Expected 1 result, got %d", len(results))
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 requests, got %d", requests)
+	}
+}
+
+func TestConnector_APIError_RetriesExhausted(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(`{"ok": false, "error": "ratelimited"}`))
+	}))
+	defer server.Close()
+
+	cfg := restConfig("api-error-test", server.URL, slackMapping)
+	cfg.RetryConfig = &config.RetryConfig{
+		MaxAttempts:         2,
+		InitialBackoff:      0.01,
+		BackoffMultiplier:   1,
+		RetryableErrorCodes: []string{"ratelimited"},
+	}
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	_, err = connector.Extract(context.Background())
+	var apiErr *errors2.APIError
+	if !errors2.As(err, &apiErr) || !apiErr.Retryable {
+		t.Fatalf("Expected retryable *APIError, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
+
+func TestConnector_APIError_StopsPaginationBeforeAdvancing(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if atomic.AddInt32(&requests, 1) == 2 {
+			w.Write([]byte(`{"ok": false, "error": "internal_error"}`))
+			return
+		}
+		w.Write([]byte(`{"ok": true, "members": [{"id": "U1"}], "next": "abc"}`))
+	}))
+	defer server.Close()
+
+	cfg := restConfig("api-error-test", server.URL, slackMapping)
+	cfg.Pagination = &config.Pagination{
+		Type:        config.PaginationTypeCursor,
+		CursorParam: "cursor",
+		CursorPath:  "next",
+	}
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	_, err = connector.Extract(context.Background())
+	if !errors2.Is(err, errors2.ErrAPI) {
+		t.Fatalf("Expected ErrAPI, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected pagination to stop at the failing page, got %d requests", requests)
+	}
+}
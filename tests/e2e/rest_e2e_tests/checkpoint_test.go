@@ -0,0 +1,128 @@
+package rest_e2e_tests_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/saturnines/nexus-core/pkg/checkpoint"
+	"github.com/saturnines/nexus-core/pkg/core"
+)
+
+// newFlakyPageServer serves four pages of two items and fails page failPage
+// while *failing is set.
+func newFlakyPageServer(failPage int, failing *atomic.Bool, pages *[]int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		*pages = append(*pages, page)
+		if page == failPage && failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": []interface{}{
+				map[string]interface{}{"id": fmt.Sprintf("%d-a", page)},
+				map[string]interface{}{"id": fmt.Sprintf("%d-b", page)},
+			},
+			"has_more": page < 4,
+		})
+	}))
+}
+
+func TestConnector_Checkpoint_ResumesAfterFailure(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	var pages []int
+	server := newFlakyPageServer(3, &failing, &pages)
+	defer server.Close()
+
+	store, err := checkpoint.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	cfg := restConfig("stream-test", server.URL, streamPageMapping)
+	pagination := streamPagePagination
+	cfg.Pagination = &pagination
+
+	connector, err := core.NewConnector(cfg, core.WithCheckpoint(store, ""))
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	if _, err := connector.Extract(context.Background()); err == nil {
+		t.Fatal("Expected first run to fail on page 3")
+	}
+
+	state, err := store.Load(context.Background(), cfg.Name)
+	if err != nil || state == nil {
+		t.Fatalf("Expected a saved checkpoint, got %v, %v", state, err)
+	}
+	if state.Page != 3 {
+		t.Errorf("Expected checkpoint to point at page 3, got %+v", state)
+	}
+
+	failing.Store(false)
+	pages = nil
+
+	connector, err = core.NewConnector(cfg, core.WithCheckpoint(store, ""))
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	results, err := connector.Extract(context.Background())
+	if err != nil {
+		t.Fatalf("Resumed run failed: %v", err)
+	}
+	if fmt.Sprint(pages) != "[3 4]" {
+		t.Errorf("Expected resumed run to fetch pages [3 4], got %v", pages)
+	}
+	if len(results) != 4 || results[0]["id"] != "3-a" {
+		t.Errorf("Expected records from pages 3 and 4, got %v", results)
+	}
+
+	if state, _ := store.Load(context.Background(), cfg.Name); state != nil {
+		t.Errorf("Expected checkpoint to be cleared after completion, got %+v", state)
+	}
+}
+
+func TestConnector_Checkpoint_PartialPageIsNotCommitted(t *testing.T) {
+	var failing atomic.Bool
+	var pages []int
+	server := newFlakyPageServer(0, &failing, &pages)
+	defer server.Close()
+
+	store, err := checkpoint.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	cfg := restConfig("stream-test", server.URL, streamPageMapping)
+	pagination := streamPagePagination
+	cfg.Pagination = &pagination
+
+	connector, err := core.NewConnector(cfg, core.WithCheckpoint(store, "partial"))
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	seen := 0
+	for _, err := range connector.Stream(context.Background()) {
+		if err != nil {
+			t.Fatalf("Stream failed: %v", err)
+		}
+		seen++
+		if seen == 3 { // first record of page 2
+			break
+		}
+	}
+
+	state, err := store.Load(context.Background(), "partial")
+	if err != nil || state == nil {
+		t.Fatalf("Expected a saved checkpoint, got %v, %v", state, err)
+	}
+	if state.Page != 2 {
+		t.Errorf("Expected page 2 to be fetched again, got %+v", state)
+	}
+}
@@ -0,0 +1,355 @@
+package rest_e2e_tests_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/core"
+	errors2 "github.com/saturnines/nexus-core/pkg/errors"
+)
+
+// newReposServer serves /repos with n repositories and
+// /repos/<owner>/<name>/issues with two pages of issues per repo.
+func newReposServer(t *testing.T, n int, inFlight, maxInFlight *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Expected parent auth on %s, got %q", r.URL.Path, got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/repos" {
+			var repos []interface{}
+			for i := 1; i <= n; i++ {
+				repos = append(repos, map[string]interface{}{"id": i, "full_name": fmt.Sprintf("acme/repo%d", i)})
+			}
+			json.NewEncoder(w).Encode(repos)
+			return
+		}
+
+		if inFlight != nil {
+			cur := atomic.AddInt32(inFlight, 1)
+			defer atomic.AddInt32(inFlight, -1)
+			for {
+				old := atomic.LoadInt32(maxInFlight)
+				if cur <= old || atomic.CompareAndSwapInt32(maxInFlight, old, cur) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		repo := strings.TrimSuffix(strings.TrimPrefix(r.URL.EscapedPath(), "/repos/"), "/issues")
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": []interface{}{
+				map[string]interface{}{"number": page, "title": fmt.Sprintf("%s#%d", repo, page)},
+			},
+			"has_more": page < 2,
+		})
+	}))
+}
+
+// reposMapping and issuesChild read newReposServer. The child's endpoint
+// includes the server URL, so tests set it on a copy of issuesChild.
+var reposMapping = config.ResponseMapping{
+	Fields: []config.Field{
+		{Name: "id", Path: "id"},
+		{Name: "full_name", Path: "full_name"},
+	},
+}
+
+var issuesChild = config.Child{
+	Name: "issues",
+	Source: config.Source{
+		ResponseMapping: config.ResponseMapping{
+			RootPath: "data",
+			Fields: []config.Field{
+				{Name: "number", Path: "number"},
+				{Name: "title", Path: "title"},
+			},
+		},
+	},
+	Pagination: &config.Pagination{
+		Type:        config.PaginationTypePage,
+		PageParam:   "page",
+		SizeParam:   "per_page",
+		PageSize:    1,
+		HasMorePath: "has_more",
+	},
+	ParentKeys: []string{"id"},
+}
+
+func TestConnector_Children_FanOutWithPaginationAndParentKeys(t *testing.T) {
+	server := newReposServer(t, 3, nil, nil)
+	defer server.Close()
+
+	cfg := restConfig("repos", server.URL+"/repos", reposMapping)
+	cfg.Source.Auth = &config.Auth{Type: config.AuthTypeBearer, Bearer: &config.BearerAuth{Token: "secret"}}
+	issues := issuesChild
+	issues.Source.Endpoint = server.URL + "/repos/{{parent.full_name}}/issues"
+	cfg.Children = []config.Child{issues}
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	results, err := connector.Extract(context.Background())
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 parents, got %d", len(results))
+	}
+
+	for i, repo := range results {
+		issues, ok := repo["issues"].([]interface{})
+		if !ok || len(issues) != 2 {
+			t.Fatalf("Expected 2 issues on repo %d, got %v", i, repo["issues"])
+		}
+		for page, raw := range issues {
+			issue := raw.(map[string]interface{})
+			wantTitle := fmt.Sprintf("acme/repo%d#%d", i+1, page+1)
+			if issue["title"] != wantTitle {
+				t.Errorf("Expected title %q, got %v", wantTitle, issue["title"])
+			}
+			if issue["parent_id"] != float64(i+1) || issue["parent_full_name"] != repo["full_name"] {
+				t.Errorf("Expected parent keys on child record, got %v", issue)
+			}
+		}
+	}
+}
+
+func TestConnector_Children_BoundedConcurrency(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := newReposServer(t, 8, &inFlight, &maxInFlight)
+	defer server.Close()
+
+	cfg := restConfig("repos", server.URL+"/repos", reposMapping)
+	cfg.Source.Auth = &config.Auth{Type: config.AuthTypeBearer, Bearer: &config.BearerAuth{Token: "secret"}}
+	issues := issuesChild
+	issues.Source.Endpoint = server.URL + "/repos/{{parent.full_name}}/issues"
+	issues.Concurrency = 2
+	cfg.Children = []config.Child{issues}
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	results, err := connector.Extract(context.Background())
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(results) != 8 {
+		t.Errorf("Expected 8 parents, got %d", len(results))
+	}
+	if maxInFlight > 2 {
+		t.Errorf("Expected at most 2 child requests in flight, saw %d", maxInFlight)
+	}
+}
+
+func TestConnector_Children_MissingParentField(t *testing.T) {
+	server := newReposServer(t, 2, nil, nil)
+	defer server.Close()
+
+	cfg := restConfig("repos", server.URL+"/repos", reposMapping)
+	cfg.Source.Auth = &config.Auth{Type: config.AuthTypeBearer, Bearer: &config.BearerAuth{Token: "secret"}}
+	issues := issuesChild
+	issues.Source.Endpoint = server.URL + "/repos/{{parent.owner.login}}/issues"
+	cfg.Children = []config.Child{issues}
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	_, err = connector.Extract(context.Background())
+	if !errors2.Is(err, errors2.ErrExtraction) {
+		t.Fatalf("Expected ErrExtraction, got %v", err)
+	}
+	for _, want := range []string{`child "issues"`, `"owner.login"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to mention %s, got: %v", want, err)
+		}
+	}
+}
+
+func TestConnector_Children_InvalidConfig(t *testing.T) {
+	cfg := restConfig("repos", "http://example.invalid/repos", reposMapping)
+	issues := issuesChild
+	issues.Source.Endpoint = "http://example.invalid/repos/{{parent.full_name}}/issues"
+	cfg.Children = []config.Child{issues, issues}
+	if _, err := core.NewConnector(cfg); !errors2.Is(err, errors2.ErrConfiguration) {
+		t.Errorf("Expected ErrConfiguration for duplicate child, got %v", err)
+	}
+}
+
+func TestConnector_Children_GraphQLVariablesKeepType(t *testing.T) {
+	var gotVars []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/orders" {
+			w.Write([]byte(`[{"id": 101}, {"id": 102}]`))
+			return
+		}
+		var body struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		gotVars = append(gotVars, body.Variables)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"fulfillments": []interface{}{map[string]interface{}{"status": "shipped"}},
+			},
+		})
+	}))
+	defer server.Close()
+
+	cfg := &config.Pipeline{
+		Name: "orders",
+		Source: config.Source{
+			Type:            config.SourceTypeREST,
+			Endpoint:        server.URL + "/orders",
+			ResponseMapping: config.ResponseMapping{Fields: []config.Field{{Name: "id", Path: "id"}}},
+		},
+		Children: []config.Child{{
+			Name:        "fulfillments",
+			Concurrency: 1,
+			Source: config.Source{
+				Type: config.SourceTypeGraphQL,
+				GraphQLConfig: &config.GraphQLSource{
+					Endpoint: server.URL + "/graphql",
+					Query:    `query($order: Int!) { fulfillments(order: $order) { status } }`,
+					Variables: map[string]interface{}{
+						"order": "{{parent.id}}",
+						"label": "order-{{parent.id}}",
+					},
+					ResponseMapping: config.ResponseMapping{
+						RootPath: "fulfillments",
+						Fields:   []config.Field{{Name: "status", Path: "status"}},
+					},
+				},
+			},
+		}},
+	}
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	results, err := connector.Extract(context.Background())
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(gotVars) != 2 || gotVars[0]["order"] != float64(101) || gotVars[0]["label"] != "order-101" {
+		t.Errorf("Expected typed order variable and rendered label, got %v", gotVars)
+	}
+	child := results[1]["fulfillments"].([]interface{})[0].(map[string]interface{})
+	if child["status"] != "shipped" || child["parent_id"] != float64(102) {
+		t.Errorf("Unexpected child record %v", child)
+	}
+}
+
+func TestConnector_Children_EscapesEndpointValues(t *testing.T) {
+	var mu sync.Mutex
+	paths := map[string]string{} // escaped child path -> ref query param
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/items" {
+			w.Write([]byte(`[{"id": "a/b"}, {"id": "x?y=1"}, {"id": "#1"}]`))
+			return
+		}
+		mu.Lock()
+		paths[r.URL.EscapedPath()] = r.URL.Query().Get("ref")
+		mu.Unlock()
+		w.Write([]byte(`[{"ok": true}]`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Pipeline{
+		Name: "items",
+		Source: config.Source{
+			Type:            config.SourceTypeREST,
+			Endpoint:        server.URL + "/items",
+			ResponseMapping: config.ResponseMapping{Fields: []config.Field{{Name: "id", Path: "id"}}},
+		},
+		Children: []config.Child{{
+			Name: "details",
+			Source: config.Source{
+				Endpoint:        server.URL + "/items/{{parent.id}}/details",
+				QueryParams:     map[string]string{"ref": "{{parent.id}}"},
+				ResponseMapping: config.ResponseMapping{Fields: []config.Field{{Name: "ok", Path: "ok"}}},
+			},
+		}},
+	}
+
+	extractAll(t, cfg)
+	want := map[string]string{
+		"/items/a/b/details":     "a/b",
+		"/items/x%3Fy=1/details": "x?y=1",
+		"/items/%231/details":    "#1",
+	}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("Expected escaped paths with raw query values %v, got %v", want, paths)
+	}
+}
+
+func TestConnector_Children_ConcurrentRetries(t *testing.T) {
+	var mu sync.Mutex
+	failed := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/repos" {
+			w.Write([]byte(`[{"id": 1}, {"id": 2}, {"id": 3}, {"id": 4}, {"id": 5}, {"id": 6}]`))
+			return
+		}
+		mu.Lock()
+		first := !failed[r.URL.Path]
+		failed[r.URL.Path] = true
+		mu.Unlock()
+		if first {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`[{"number": 1}]`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Pipeline{
+		Name: "repos",
+		Source: config.Source{
+			Type:            config.SourceTypeREST,
+			Endpoint:        server.URL + "/repos",
+			ResponseMapping: config.ResponseMapping{Fields: []config.Field{{Name: "id", Path: "id"}}},
+		},
+		Children: []config.Child{{
+			Name:        "issues",
+			Concurrency: 4,
+			Source: config.Source{
+				Endpoint:        server.URL + "/repos/{{parent.id}}/issues",
+				ResponseMapping: config.ResponseMapping{Fields: []config.Field{{Name: "number", Path: "number"}}},
+			},
+		}},
+		RetryConfig: &config.RetryConfig{
+			MaxAttempts:       2,
+			InitialBackoff:    0.01,
+			BackoffMultiplier: 1,
+			RetryableStatuses: []int{http.StatusServiceUnavailable},
+		},
+	}
+
+	results := extractAll(t, cfg)
+	for _, repo := range results {
+		if issues, ok := repo["issues"].([]interface{}); !ok || len(issues) != 1 {
+			t.Errorf("Expected one issue after a retry, got %v", repo["issues"])
+		}
+	}
+}
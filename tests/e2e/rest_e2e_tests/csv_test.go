@@ -0,0 +1,233 @@
+package rest_e2e_tests_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/core"
+	errors2 "github.com/saturnines/nexus-core/pkg/errors"
+)
+
+const campaignReport = "Campaign performance report\n" +
+	"Generated 2024-05-01\n" +
+	"Campaign ID,Campaign Name,Clicks,Cost\n" +
+	"# totals are excluded\n" +
+	"101,\"Spring \"\"Sale\"\"\",42,12.50\n" +
+	"102,\"Multi\nline\",7,0.99\r\n" +
+	"\n" +
+	"103,Plain,0,0\n"
+
+func TestConnector_CSVReport(t *testing.T) {
+	server := jsonServer(campaignReport)
+	defer server.Close()
+
+	want := []map[string]interface{}{
+		{"id": 101, "name": `SPRING "SALE"`, "clicks": 42, "cost": 12.5},
+		{"id": 102, "name": "MULTI\nLINE", "clicks": 7, "cost": 0.99},
+		{"id": 103, "name": "PLAIN", "clicks": 0, "cost": 0.0},
+	}
+	for _, streaming := range []bool{false, true} {
+		t.Run(fmt.Sprintf("streaming=%t", streaming), func(t *testing.T) {
+			cfg := restConfig("csv-test", server.URL, config.ResponseMapping{
+				ResponseFormat: config.ResponseFormatCSV,
+				Streaming:      streaming,
+				CSV:            &config.CSVFormat{SkipRows: 2, Comment: "#"},
+				Fields: []config.Field{
+					{Name: "id", Path: "['Campaign ID']", Type: "integer"},
+					{Name: "name", Path: "['Campaign Name']", Transform: &config.FieldTransform{Type: "upper"}},
+					{Name: "clicks", Path: "Clicks", Type: "integer"},
+					{Name: "cost", Path: "Cost", Type: "float"},
+				},
+			})
+
+			records := extractAll(t, cfg)
+			if !reflect.DeepEqual(records, want) {
+				t.Errorf("Expected %v, got %v", want, records)
+			}
+		})
+	}
+}
+
+func TestConnector_CSVHeaderDetection(t *testing.T) {
+	testCases := []struct {
+		name   string
+		format config.ResponseFormat
+		body   string
+		opts   *config.CSVFormat
+		want   []map[string]interface{}
+	}{
+		{
+			name:   "tsv header with bare quotes",
+			format: config.ResponseFormatTSV,
+			body:   "sku\ttitle\n" + "A1\t12\" pizza\n",
+			want:   []map[string]interface{}{{"sku": "A1", "title": `12" pizza`}},
+		},
+		{
+			name:   "auto without a known column keeps the first row",
+			format: config.ResponseFormatCSV,
+			body:   "alice,bob\ncarol,dave\n",
+			opts:   &config.CSVFormat{Header: config.CSVHeaderAuto},
+			want: []map[string]interface{}{
+				{"column_1": "alice", "column_2": "bob"},
+				{"column_1": "carol", "column_2": "dave"},
+			},
+		},
+		{
+			name:   "columns detect their own header",
+			format: config.ResponseFormatCSV,
+			body:   "id,name\n1,alice\n",
+			opts:   &config.CSVFormat{Columns: []string{"id", "name"}},
+			want:   []map[string]interface{}{{"id": "1", "name": "alice"}},
+		},
+		{
+			name:   "columns without a header",
+			format: config.ResponseFormatCSV,
+			body:   "1,alice\n2,bob\n",
+			opts:   &config.CSVFormat{Columns: []string{"id", "name"}},
+			want: []map[string]interface{}{
+				{"id": "1", "name": "alice"},
+				{"id": "2", "name": "bob"},
+			},
+		},
+		{
+			name:   "configured columns, delimiter, quote and nulls",
+			format: config.ResponseFormatCSV,
+			body:   "1;'a;b';N/A\n",
+			opts: &config.CSVFormat{
+				Header:     config.CSVHeaderNone,
+				Columns:    []string{"id", "name", "note"},
+				Delimiter:  ";",
+				Quote:      "'",
+				NullValues: []string{"N/A"},
+			},
+			want: []map[string]interface{}{{"id": "1", "name": "a;b", "note": nil}},
+		},
+		{
+			name:   "forced header row",
+			format: config.ResponseFormatCSV,
+			body:   "2023,2024\n10,20\n",
+			opts:   &config.CSVFormat{Header: config.CSVHeaderFirstRow},
+			want:   []map[string]interface{}{{"2023": "10", "2024": "20"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := jsonServer(tc.body)
+			defer server.Close()
+
+			records := extractAll(t, restConfig("csv-test", server.URL, config.ResponseMapping{
+				ResponseFormat: tc.format,
+				Mode:           config.MappingModePassthrough,
+				CSV:            tc.opts,
+			}))
+			if !reflect.DeepEqual(records, tc.want) {
+				t.Errorf("Expected %v, got %v", tc.want, records)
+			}
+		})
+	}
+}
+
+func TestConnector_CSVBadRows(t *testing.T) {
+	body := "id,name\n" +
+		"1,a\n" +
+		"2,b,extra\n" +
+		"3,\"c\"x\n" +
+		"4,d\n"
+	server := jsonServer(body)
+	defer server.Close()
+
+	mapping := config.ResponseMapping{
+		ResponseFormat: config.ResponseFormatCSV,
+		Fields:         []config.Field{{Name: "id", Path: "id"}, {Name: "name", Path: "name"}},
+	}
+
+	t.Run("fail fast names the line", func(t *testing.T) {
+		connector, err := core.NewConnector(restConfig("csv-test", server.URL, mapping))
+		if err != nil {
+			t.Fatalf("Failed to create connector: %v", err)
+		}
+		_, err = connector.Extract(context.Background())
+		if !errors2.Is(err, errors2.ErrExtraction) || !strings.Contains(err.Error(), "line 3") {
+			t.Errorf("Expected ErrExtraction for line 3, got %v", err)
+		}
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		cfg := restConfig("csv-test", server.URL, mapping)
+		cfg.ErrorPolicy = &config.ErrorPolicy{Mode: config.ErrorPolicySkip}
+
+		dead := &memorySink{}
+		connector, err := core.NewConnector(cfg, core.WithDeadLetter(dead))
+		if err != nil {
+			t.Fatalf("Failed to create connector: %v", err)
+		}
+		records, err := connector.Extract(context.Background())
+		if err != nil {
+			t.Fatalf("Extract failed: %v", err)
+		}
+		if len(records) != 2 {
+			t.Errorf("Expected 2 good records, got %v", records)
+		}
+		if len(dead.records) != 2 || dead.records[0]["line"] != 3 || dead.records[1]["line"] != 4 {
+			t.Fatalf("Expected dead letters for lines 3 and 4, got %v", dead.records)
+		}
+		if dead.records[0]["raw"] != `"2,b,extra"` || dead.records[1]["raw"] != `"3,\"c\"x"` {
+			t.Errorf("Expected the raw rows in the dead letters, got %v and %v", dead.records[0]["raw"], dead.records[1]["raw"])
+		}
+	})
+}
+
+func TestConnector_CSVCursorFromLastRow(t *testing.T) {
+	for _, streaming := range []bool{false, true} {
+		t.Run(fmt.Sprintf("streaming=%t", streaming), func(t *testing.T) {
+			var cursors []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				after := r.URL.Query().Get("after")
+				cursors = append(cursors, after)
+				switch after {
+				case "":
+					w.Write([]byte("id,name\n1,a\n2,b\n"))
+				case "2":
+					w.Write([]byte("id,name\n3,c\n"))
+				}
+			}))
+			defer server.Close()
+
+			cfg := restConfig("csv-test", server.URL, config.ResponseMapping{
+				ResponseFormat: config.ResponseFormatCSV,
+				Streaming:      streaming,
+				Fields:         []config.Field{{Name: "id", Path: "id"}},
+			})
+			cfg.Pagination = &config.Pagination{
+				Type:        config.PaginationTypeCursor,
+				CursorParam: "after",
+				CursorPath:  "data[-1].id",
+			}
+
+			records := extractAll(t, cfg)
+			if len(records) != 3 {
+				t.Fatalf("Expected 3 records, got %d", len(records))
+			}
+			if want := []string{"", "2", "3"}; !reflect.DeepEqual(cursors, want) {
+				t.Errorf("Expected cursors %v, got %v", want, cursors)
+			}
+		})
+	}
+}
+
+func TestRestExtractor_CSVInvalidDelimiter(t *testing.T) {
+	_, err := core.NewRestExtractor(config.ResponseMapping{
+		ResponseFormat: config.ResponseFormatCSV,
+		CSV:            &config.CSVFormat{Delimiter: "::"},
+	}, nil)
+	if !errors2.Is(err, errors2.ErrConfiguration) {
+		t.Errorf("Expected ErrConfiguration, got %v", err)
+	}
+}
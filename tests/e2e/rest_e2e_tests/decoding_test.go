@@ -0,0 +1,329 @@
+package rest_e2e_tests_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/core"
+	errors2 "github.com/saturnines/nexus-core/pkg/errors"
+)
+
+// compressBody applies the codings in a Content-Encoding header to body.
+func compressBody(t *testing.T, codings string, body []byte) []byte {
+	t.Helper()
+	for _, coding := range strings.Split(codings, ",") {
+		var buf bytes.Buffer
+		var w io.WriteCloser
+		switch strings.TrimSpace(coding) {
+		case "gzip":
+			w = gzip.NewWriter(&buf)
+		case "deflate":
+			w = zlib.NewWriter(&buf)
+		case "raw-deflate":
+			w, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+		case "br":
+			w = brotli.NewWriter(&buf)
+		case "zstd":
+			zw, err := zstd.NewWriter(&buf)
+			if err != nil {
+				t.Fatalf("zstd writer: %v", err)
+			}
+			w = zw
+		default:
+			t.Fatalf("unknown coding %q", coding)
+		}
+		w.Write(body)
+		if err := w.Close(); err != nil {
+			t.Fatalf("compress %s: %v", coding, err)
+		}
+		body = buf.Bytes()
+	}
+	return body
+}
+
+func TestConnector_CompressedResponses(t *testing.T) {
+	body := []byte(`{"data": [{"id": 1, "name": "alice"}, {"id": 2, "name": "bob"}]}`)
+	want := []map[string]interface{}{{"id": 1, "name": "alice"}, {"id": 2, "name": "bob"}}
+
+	testCases := []struct {
+		name     string
+		encoding string // Content-Encoding sent by the server
+		codings  string // codings actually applied
+	}{
+		{name: "gzip", encoding: "gzip", codings: "gzip"},
+		{name: "deflate", encoding: "deflate", codings: "deflate"},
+		{name: "raw deflate", encoding: "deflate", codings: "raw-deflate"},
+		{name: "brotli", encoding: "br", codings: "br"},
+		{name: "zstd", encoding: "zstd", codings: "zstd"},
+		{name: "stacked", encoding: "gzip, br", codings: "gzip, br"},
+	}
+
+	for _, tc := range testCases {
+		var accept string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			accept = r.Header.Get("Accept-Encoding")
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Content-Encoding", tc.encoding)
+			w.Write(compressBody(t, tc.codings, body))
+		}))
+
+		for _, streaming := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/streaming=%t", tc.name, streaming), func(t *testing.T) {
+				cfg := restConfig("streaming-test", server.URL, config.ResponseMapping{
+					RootPath:  "data",
+					Streaming: true,
+					Fields:    streamingFields,
+				})
+				cfg.Source.ResponseMapping.Streaming = streaming
+
+				records := extractAll(t, cfg)
+				if !reflect.DeepEqual(records, want) {
+					t.Errorf("Expected %v, got %v", want, records)
+				}
+				if accept != "gzip, deflate, br, zstd" {
+					t.Errorf("Unexpected Accept-Encoding %q", accept)
+				}
+			})
+		}
+		server.Close()
+	}
+}
+
+func TestConnector_GzipFileResponse(t *testing.T) {
+	gz := compressBody(t, "gzip", []byte(ordersNDJSON))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/gzip")
+		w.Write(gz)
+	}))
+	defer server.Close()
+
+	records := extractAll(t, restConfig("gzip-file-test", server.URL, ordersMapping))
+	if len(records) == 0 || records[0]["email"] != "a@example.com" {
+		t.Errorf("Expected the gzip file to be unpacked, got %v", records)
+	}
+}
+
+func TestConnector_CharsetConversion(t *testing.T) {
+	testCases := []struct {
+		name        string
+		contentType string
+		encoding    string
+		body        string
+		mapping     config.ResponseMapping
+		want        string
+	}{
+		{
+			name:        "latin-1 json",
+			contentType: "application/json; charset=ISO-8859-1",
+			body:        "{\"data\": [{\"id\": 1, \"name\": \"Caf\xe9 M\xfcller\"}]}",
+			mapping: config.ResponseMapping{
+				RootPath:  "data",
+				Streaming: true,
+				Fields:    streamingFields,
+			},
+			want: "Café Müller",
+		},
+		{
+			name:        "compressed windows-1252 csv",
+			contentType: "text/csv; charset=windows-1252",
+			encoding:    "gzip",
+			body:        "id,name\n1,\x80 price\n",
+			mapping: config.ResponseMapping{
+				ResponseFormat: config.ResponseFormatCSV,
+				Fields:         []config.Field{{Name: "name", Path: "name"}},
+			},
+			want: "€ price",
+		},
+		{
+			name:        "xml declaration only",
+			contentType: "text/xml",
+			body:        "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?><orders><order name=\"Caf\xe9\"/></orders>",
+			mapping:     xmlNameMapping,
+			want:        "Café",
+		},
+		{
+			name:        "xml declaration and content type",
+			contentType: "text/xml; charset=ISO-8859-1",
+			body:        "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?><orders><order name=\"Caf\xe9\"/></orders>",
+			mapping:     xmlNameMapping,
+			want:        "Café",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tc.contentType)
+				body := []byte(tc.body)
+				if tc.encoding != "" {
+					w.Header().Set("Content-Encoding", tc.encoding)
+					body = compressBody(t, tc.encoding, body)
+				}
+				w.Write(body)
+			}))
+			defer server.Close()
+
+			records := extractAll(t, restConfig("charset-test", server.URL, tc.mapping))
+			if len(records) != 1 || records[0]["name"] != tc.want {
+				t.Errorf("Expected name %q, got %v", tc.want, records)
+			}
+		})
+	}
+}
+
+func TestConnector_UnsupportedContentEncoding(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "compress")
+		w.Write([]byte{0x1f, 0x9d, 0x90})
+	}))
+	defer server.Close()
+
+	cfg := restConfig("streaming-test", server.URL, config.ResponseMapping{
+		RootPath:  "data",
+		Streaming: true,
+		Fields:    streamingFields,
+	})
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	_, err = connector.Extract(context.Background())
+	if !errors2.Is(err, errors2.ErrHTTPResponse) || !strings.Contains(err.Error(), `"compress"`) {
+		t.Errorf("Expected ErrHTTPResponse naming the encoding, got %v", err)
+	}
+}
+
+func TestConnector_UndecodableErrorResponses(t *testing.T) {
+	testCases := []struct {
+		name     string
+		encoding string
+		body     []byte
+	}{
+		{name: "corrupt gzip", encoding: "gzip", body: []byte("upstream unavailable")},
+		{name: "unknown encoding", encoding: "compress", body: []byte{0x1f, 0x9d, 0x90}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var hits int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&hits, 1) == 1 {
+					w.Header().Set("Content-Encoding", tc.encoding)
+					w.WriteHeader(http.StatusServiceUnavailable)
+					w.Write(tc.body)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"data": [{"id": 1}]}`))
+			}))
+			defer server.Close()
+
+			// Without retries the status reaches the caller.
+			cfg := restConfig("streaming-test", server.URL, config.ResponseMapping{
+				RootPath:  "data",
+				Streaming: true,
+				Fields:    streamingFields,
+			})
+			connector, err := core.NewConnector(cfg)
+			if err != nil {
+				t.Fatalf("Failed to create connector: %v", err)
+			}
+			_, err = connector.Extract(context.Background())
+			var apiErr *errors2.APIError
+			if !errors2.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+				t.Fatalf("Expected a 503 APIError, got %v", err)
+			}
+
+			// With retries the 503 is retried.
+			atomic.StoreInt32(&hits, 0)
+			cfg.RetryConfig = &config.RetryConfig{
+				MaxAttempts:       2,
+				InitialBackoff:    0.01,
+				BackoffMultiplier: 1,
+				RetryableStatuses: []int{http.StatusServiceUnavailable},
+			}
+			records := extractAll(t, cfg)
+			if len(records) != 1 || atomic.LoadInt32(&hits) != 2 {
+				t.Errorf("Expected 1 record after a retry, got %v after %d requests", records, hits)
+			}
+		})
+	}
+}
+
+func TestConnector_DecompressionCap(t *testing.T) {
+	body := []byte(`{"data": [{"id": 1, "note": "` + strings.Repeat("a", 4096) + `"}]}`)
+	gz := compressBody(t, "gzip", body)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(gz)
+	}))
+	defer server.Close()
+
+	for _, streaming := range []bool{false, true} {
+		t.Run(fmt.Sprintf("streaming=%t", streaming), func(t *testing.T) {
+			cfg := restConfig("streaming-test", server.URL, config.ResponseMapping{
+				RootPath:  "data",
+				Streaming: true,
+				Fields:    streamingFields,
+			})
+			cfg.Source.ResponseMapping.Streaming = streaming
+			extract := func(maxSize int64) ([]map[string]interface{}, error) {
+				client := &http.Client{Transport: &core.DecodingTransport{Base: http.DefaultTransport, MaxSize: maxSize}}
+				connector, err := core.NewConnector(cfg, core.WithCustomHTTPClient(client))
+				if err != nil {
+					t.Fatalf("Failed to create connector: %v", err)
+				}
+				return connector.Extract(context.Background())
+			}
+
+			if records, err := extract(int64(len(body))); err != nil || len(records) != 1 {
+				t.Errorf("Expected a body of exactly the cap to decode, got %v, %v", records, err)
+			}
+			_, err := extract(1024)
+			if !errors2.Is(err, errors2.ErrHTTPResponse) || !strings.Contains(err.Error(), "exceeds 1024 bytes") {
+				t.Errorf("Expected ErrHTTPResponse for a body past the cap, got %v", err)
+			}
+		})
+	}
+}
+
+func TestConnector_TimeoutThroughDecodingTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	cfg := restConfig("streaming-test", server.URL, config.ResponseMapping{
+		RootPath:  "data",
+		Streaming: true,
+		Fields:    streamingFields,
+	})
+	connector, err := core.NewConnector(cfg, core.WithTimeout(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	_, err = connector.Extract(context.Background())
+	if !errors2.Is(err, errors2.ErrHTTPRequest) || !strings.Contains(err.Error(), "timeout") {
+		t.Errorf("Expected ErrHTTPRequest naming the timeout, got %v", err)
+	}
+}
@@ -0,0 +1,256 @@
+package rest_e2e_tests_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/core"
+	errors2 "github.com/saturnines/nexus-core/pkg/errors"
+)
+
+// memorySink collects written records for assertions.
+type memorySink struct {
+	records []map[string]interface{}
+	inits   int
+}
+
+func (s *memorySink) Init(ctx context.Context) error { s.inits++; return nil }
+func (s *memorySink) Close() error                   { return nil }
+func (s *memorySink) Write(ctx context.Context, records []map[string]interface{}) error {
+	s.records = append(s.records, records...)
+	return nil
+}
+
+// newMixedPageServer serves pages of three items where the second item of
+// every page is not an object.
+func newMixedPageServer(totalPages int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page < 1 {
+			page = 1
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": []interface{}{
+				map[string]interface{}{"id": page*10 + 1, "name": "ok"},
+				fmt.Sprintf("broken-%d", page),
+				map[string]interface{}{"id": page*10 + 3, "name": "ok"},
+			},
+			"has_more": page < totalPages,
+		})
+	}))
+}
+
+func TestErrorPolicy_FailFastByDefault(t *testing.T) {
+	server := newMixedPageServer(2)
+	defer server.Close()
+
+	dead := &memorySink{}
+	cfg := restConfig("stream-test", server.URL, streamPageMapping)
+	pagination := streamPagePagination
+	cfg.Pagination = &pagination
+	connector, err := core.NewConnector(cfg, core.WithDeadLetter(dead))
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	results, err := connector.Extract(context.Background())
+	if !errors2.Is(err, errors2.ErrExtraction) {
+		t.Fatalf("Expected ErrExtraction, got %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("Expected no records, got %d", len(results))
+	}
+	if len(dead.records) != 1 || dead.records[0]["raw"] != `"broken-1"` {
+		t.Errorf("Expected the failing item in the dead letter, got %v", dead.records)
+	}
+}
+
+func TestErrorPolicy_SkipRoutesToDeadLetter(t *testing.T) {
+	server := newMixedPageServer(2)
+	defer server.Close()
+
+	cfg := restConfig("stream-test", server.URL, streamPageMapping)
+	pagination := streamPagePagination
+	cfg.Pagination = &pagination
+	cfg.ErrorPolicy = &config.ErrorPolicy{Mode: config.ErrorPolicySkip}
+
+	dead := &memorySink{}
+	connector, err := core.NewConnector(cfg, core.WithDeadLetter(dead))
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	results, err := connector.Extract(context.Background())
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(results) != 4 {
+		t.Errorf("Expected 4 good records, got %d", len(results))
+	}
+
+	if len(dead.records) != 2 || dead.inits != 1 {
+		t.Fatalf("Expected 2 dead letters and one Init, got %d and %d", len(dead.records), dead.inits)
+	}
+	for i, d := range dead.records {
+		if d["page"] != i+1 || d["index"] != 1 || d["pipeline"] != "stream-test" {
+			t.Errorf("Unexpected dead letter %d: %v", i, d)
+		}
+		if d["raw"] != fmt.Sprintf(`"broken-%d"`, i+1) || d["reason"] == "" {
+			t.Errorf("Unexpected dead letter %d: %v", i, d)
+		}
+	}
+}
+
+func TestErrorPolicy_MaxErrorsBudget(t *testing.T) {
+	server := newMixedPageServer(4)
+	defer server.Close()
+
+	cfg := restConfig("stream-test", server.URL, streamPageMapping)
+	pagination := streamPagePagination
+	cfg.Pagination = &pagination
+	cfg.ErrorPolicy = &config.ErrorPolicy{Mode: config.ErrorPolicySkip, MaxErrors: 2}
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	results, err := connector.Extract(context.Background())
+	if !errors2.Is(err, errors2.ErrExtraction) {
+		t.Fatalf("Expected ErrExtraction once the budget is exceeded, got %v", err)
+	}
+
+	var partial *errors2.PartialError
+	if !errors2.As(err, &partial) {
+		t.Fatalf("Expected *PartialError, got %T", err)
+	}
+	if partial.Pages != 2 || partial.Records != 4 || len(results) != 4 {
+		t.Errorf("Expected 4 records from 2 pages, got %+v and %d results", partial, len(results))
+	}
+}
+
+func TestErrorPolicy_MaxErrorPercent(t *testing.T) {
+	tests := []struct {
+		percent float64
+		wantErr bool
+	}{
+		{50, false},
+		{30, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.percent), func(t *testing.T) {
+			server := newMixedPageServer(2)
+			defer server.Close()
+
+			cfg := restConfig("stream-test", server.URL, streamPageMapping)
+			pagination := streamPagePagination
+			cfg.Pagination = &pagination
+			cfg.ErrorPolicy = &config.ErrorPolicy{Mode: config.ErrorPolicySkip, MaxErrorPercent: tt.percent}
+
+			connector, err := core.NewConnector(cfg)
+			if err != nil {
+				t.Fatalf("Failed to create connector: %v", err)
+			}
+			_, err = connector.Extract(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v with 33%% rejected, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestErrorPolicy_PartialResultsWhenPaginationFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": [{"id": 1}, {"id": 2}, {"id": 3}], "has_more": true}`))
+	}))
+	defer server.Close()
+
+	cfg := restConfig("stream-test", server.URL, streamPageMapping)
+	pagination := streamPagePagination
+	cfg.Pagination = &pagination
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	results, err := connector.Extract(context.Background())
+	if !errors2.Is(err, errors2.ErrHTTPResponse) {
+		t.Fatalf("Expected ErrHTTPResponse, got %v", err)
+	}
+	if len(results) != 3 {
+		t.Errorf("Expected the 3 records from page 1, got %d", len(results))
+	}
+	var partial *errors2.PartialError
+	if !errors2.As(err, &partial) || partial.Records != 3 || partial.Pages != 1 {
+		t.Errorf("Expected PartialError for 3 records from 1 page, got %v", err)
+	}
+	if errors2.StatusCode(err) != 500 {
+		t.Errorf("Expected status 500 through the chain, got %d", errors2.StatusCode(err))
+	}
+}
+
+func TestErrorPolicy_JSONLDeadLetterFromConfig(t *testing.T) {
+	server := newMixedPageServer(1)
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "rejects.jsonl")
+	cfg := restConfig("stream-test", server.URL, streamPageMapping)
+	pagination := streamPagePagination
+	cfg.Pagination = &pagination
+	cfg.ErrorPolicy = &config.ErrorPolicy{
+		Mode:       config.ErrorPolicySkip,
+		DeadLetter: &config.Destination{Type: config.DestinationJSONL, DSN: path},
+	}
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	if _, err := connector.Extract(context.Background()); err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Expected dead letter file: %v", err)
+	}
+	defer f.Close()
+
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var m map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &m); err != nil {
+			t.Fatalf("Invalid JSON line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, m)
+	}
+	if len(lines) != 1 || lines[0]["raw"] != `"broken-1"` {
+		t.Errorf("Unexpected dead letter lines: %v", lines)
+	}
+}
+
+func TestErrorPolicy_UnknownDeadLetterType(t *testing.T) {
+	cfg := restConfig("stream-test", "http://localhost", streamPageMapping)
+	pagination := streamPagePagination
+	cfg.Pagination = &pagination
+	cfg.ErrorPolicy = &config.ErrorPolicy{
+		Mode:       config.ErrorPolicySkip,
+		DeadLetter: &config.Destination{Type: "nosuchsink"},
+	}
+	if _, err := core.NewConnector(cfg); !errors2.Is(err, errors2.ErrConfiguration) {
+		t.Errorf("Expected ErrConfiguration, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package rest_e2e_tests_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/core"
+	errors2 "github.com/saturnines/nexus-core/pkg/errors"
+)
+
+const ordersBody = `{"orders": [
+	{"id": 1, "created_at": "2024-01-01", "line_items": [{"sku": "A", "qty": 2}, {"sku": "B", "qty": 1}]},
+	{"id": 2, "created_at": "2024-01-02", "line_items": []},
+	{"id": 3, "created_at": "2024-01-03", "line_items": [{"sku": "C", "qty": 5}]}
+]}`
+
+func TestConnector_Explode(t *testing.T) {
+	server := jsonServer(ordersBody)
+	defer server.Close()
+
+	cfg := restConfig("explode-test", server.URL, config.ResponseMapping{
+		RootPath: "orders",
+		Fields: []config.Field{
+			{Name: "order_id", Path: "id", Type: "integer"},
+			{Name: "created_at", Path: "created_at"},
+		},
+		Explode: &config.Explode{
+			Path:       "line_items[*]",
+			IndexField: "line",
+			Fields: []config.Field{
+				{Name: "sku", Path: "sku"},
+				{Name: "qty", Path: "qty", Type: "integer"},
+			},
+		},
+	})
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	records, err := connector.Extract(context.Background())
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	want := []map[string]interface{}{
+		{"order_id": 1, "created_at": "2024-01-01", "sku": "A", "qty": 2, "line": 0},
+		{"order_id": 1, "created_at": "2024-01-01", "sku": "B", "qty": 1, "line": 1},
+		{"order_id": 3, "created_at": "2024-01-03", "sku": "C", "qty": 5, "line": 0},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("Expected %v, got %v", want, records)
+	}
+}
+
+func TestConnector_ExplodeKeepEmpty(t *testing.T) {
+	server := jsonServer(ordersBody)
+	defer server.Close()
+
+	cfg := restConfig("explode-test", server.URL, config.ResponseMapping{
+		RootPath: "orders",
+		Fields: []config.Field{
+			{Name: "order_id", Path: "id", Type: "integer"},
+			{Name: "created_at", Path: "created_at"},
+		},
+		Explode: &config.Explode{
+			Path:       "line_items",
+			IndexField: "line",
+			OnEmpty:    config.ExplodeEmptyKeep,
+			Fields:     []config.Field{{Name: "sku", Path: "sku"}},
+		},
+	})
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	records, err := connector.Extract(context.Background())
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("Expected 4 records, got %d: %v", len(records), records)
+	}
+
+	want := map[string]interface{}{"order_id": 2, "created_at": "2024-01-02", "sku": nil, "line": nil}
+	if !reflect.DeepEqual(records[2], want) {
+		t.Errorf("Expected %v for the empty order, got %v", want, records[2])
+	}
+}
+
+func TestConnector_ExplodeNotArray(t *testing.T) {
+	server := jsonServer(`{"orders": [{"id": 1, "line_items": {"sku": "A"}}]}`)
+	defer server.Close()
+
+	cfg := restConfig("explode-test", server.URL, config.ResponseMapping{
+		RootPath: "orders",
+		Fields: []config.Field{
+			{Name: "order_id", Path: "id", Type: "integer"},
+			{Name: "created_at", Path: "created_at"},
+		},
+		Explode: &config.Explode{
+			Path:   "line_items",
+			Fields: []config.Field{{Name: "sku", Path: "sku"}},
+		},
+	})
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	if _, err := connector.Extract(context.Background()); !errors2.Is(err, errors2.ErrExtraction) {
+		t.Errorf("Expected ErrExtraction, got %v", err)
+	}
+}
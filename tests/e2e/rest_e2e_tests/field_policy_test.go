@@ -0,0 +1,150 @@
+package rest_e2e_tests_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/core"
+	errors2 "github.com/saturnines/nexus-core/pkg/errors"
+)
+
+func TestConnector_FieldPolicies(t *testing.T) {
+	server := jsonServer(`{"data": [
+		{"id": 1, "email": null},
+		{"id": 2, "email": "b@example.com", "phone": "555"}
+	]}`)
+	defer server.Close()
+
+	cfg := restConfig("field-policy-test", server.URL, config.ResponseMapping{
+		RootPath: "data",
+		Fields: []config.Field{
+			{Name: "id", Path: "id"},
+			{Name: "email", Path: "email", OnMissing: config.FieldPolicyOmit, OnNull: config.FieldPolicyNull},
+			{Name: "phone", Path: "phone", OnMissing: config.FieldPolicyDefault, DefaultValue: "n/a"},
+			{Name: "legacy", Path: "email", DefaultValue: "none"},
+			{Name: "nickname", Path: "nickname"},
+		},
+	})
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	records, err := connector.Extract(context.Background())
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	want := []map[string]interface{}{
+		{"id": 1.0, "email": nil, "phone": "n/a", "legacy": "none"},
+		{"id": 2.0, "email": "b@example.com", "phone": "555", "legacy": "b@example.com"},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("Expected %v, got %v", want, records)
+	}
+}
+
+func TestConnector_FieldPolicyError(t *testing.T) {
+	server := jsonServer(`{"data": [{"id": 1, "email": "a@example.com"}, {"id": 2, "email": null}]}`)
+	defer server.Close()
+
+	cfg := restConfig("field-policy-test", server.URL, config.ResponseMapping{
+		RootPath: "data",
+		Fields: []config.Field{
+			{Name: "id", Path: "id"},
+			{Name: "email", Path: "email", OnNull: config.FieldPolicyError},
+		},
+	})
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	_, err = connector.Extract(context.Background())
+	if err == nil {
+		t.Fatal("Expected an error for the null email")
+	}
+	if !errors2.Is(err, errors2.ErrExtraction) {
+		t.Errorf("Expected ErrExtraction, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `field "email" is null`) {
+		t.Errorf("Expected the error to name the null field, got %v", err)
+	}
+}
+
+func TestConnector_StrictMode(t *testing.T) {
+	server := jsonServer(`{"data": [{"id": 1, "name": "a"}, {"id": 2}]}`)
+	defer server.Close()
+
+	t.Run("sparse field resolves", func(t *testing.T) {
+		cfg := restConfig("field-policy-test", server.URL, config.ResponseMapping{
+			RootPath: "data",
+			Fields: []config.Field{
+				{Name: "id", Path: "id"},
+				{Name: "name", Path: "name"},
+			},
+		})
+		cfg.Strict = true
+
+		connector, err := core.NewConnector(cfg)
+		if err != nil {
+			t.Fatalf("Failed to create connector: %v", err)
+		}
+		records, err := connector.Extract(context.Background())
+		if err != nil {
+			t.Fatalf("Extract failed: %v", err)
+		}
+		if len(records) != 2 {
+			t.Errorf("Expected 2 records, got %d", len(records))
+		}
+	})
+
+	t.Run("wrong path fails", func(t *testing.T) {
+		cfg := restConfig("field-policy-test", server.URL, config.ResponseMapping{
+			RootPath: "data",
+			Fields: []config.Field{
+				{Name: "id", Path: "id"},
+				{Name: "name", Path: "user.name"},
+			},
+		})
+		cfg.Strict = true
+
+		connector, err := core.NewConnector(cfg)
+		if err != nil {
+			t.Fatalf("Failed to create connector: %v", err)
+		}
+		_, err = connector.Extract(context.Background())
+		if !errors2.Is(err, errors2.ErrExtraction) {
+			t.Fatalf("Expected ErrExtraction, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "name (user.name)") {
+			t.Errorf("Expected the error to name the field, got %v", err)
+		}
+	})
+
+	t.Run("declared optional field is exempt", func(t *testing.T) {
+		cfg := restConfig("field-policy-test", server.URL, config.ResponseMapping{
+			RootPath: "data",
+			Fields: []config.Field{
+				{Name: "id", Path: "id"},
+				{Name: "name", Path: "user.name", OnMissing: config.FieldPolicyNull},
+			},
+		})
+		cfg.Strict = true
+
+		connector, err := core.NewConnector(cfg)
+		if err != nil {
+			t.Fatalf("Failed to create connector: %v", err)
+		}
+		records, err := connector.Extract(context.Background())
+		if err != nil {
+			t.Fatalf("Extract failed: %v", err)
+		}
+		if v, ok := records[0]["name"]; !ok || v != nil {
+			t.Errorf("Expected name to be emitted as null, got %v", records[0])
+		}
+	})
+}
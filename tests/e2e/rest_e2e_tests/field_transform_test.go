@@ -0,0 +1,195 @@
+package rest_e2e_tests_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/core"
+	errors2 "github.com/saturnines/nexus-core/pkg/errors"
+	"github.com/saturnines/nexus-core/pkg/transform"
+)
+
+func TestConnector_FieldTransforms_Applied(t *testing.T) {
+	server := jsonServer(`{"data": [
+		{"id": "42", "name": "  alice  ", "created": 1609459200, "tags": "a,b,c", "active": "true"},
+		{"id": "7", "name": "bob", "created": 1609545600, "tags": "x", "active": "false"}
+	]}`)
+	defer server.Close()
+
+	cfg := &config.Pipeline{
+		Name: "transform-test",
+		Source: config.Source{
+			Type:     config.SourceTypeREST,
+			Endpoint: server.URL,
+			ResponseMapping: config.ResponseMapping{
+				RootPath: "data",
+				Fields: []config.Field{
+					{Name: "id", Path: "id", Type: "integer"},
+					{Name: "name", Path: "name", Transform: &config.FieldTransform{
+						Chain: []config.FieldTransform{{Type: "trim"}, {Type: "upper"}},
+					}},
+					{Name: "created", Path: "created", Transform: &config.FieldTransform{
+						Type:   "date",
+						Config: map[string]interface{}{"output_format": "Date"},
+					}},
+					{Name: "tags", Path: "tags", Transform: &config.FieldTransform{
+						Type:   "split",
+						Config: map[string]interface{}{"delimiter": ","},
+					}},
+					{Name: "active", Path: "active", Type: "boolean"},
+					{Name: "status", Path: "status", Type: "string", DefaultValue: "unknown"},
+				},
+			},
+		},
+	}
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	results, err := connector.Extract(context.Background())
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+
+	first := results[0]
+	if first["id"] != 42 {
+		t.Errorf("Expected id coerced to int 42, got %v (%T)", first["id"], first["id"])
+	}
+	if first["name"] != "ALICE" {
+		t.Errorf("Expected chained trim+upper 'ALICE', got %q", first["name"])
+	}
+	if first["created"] != "2021-01-01" {
+		t.Errorf("Expected created '2021-01-01', got %v", first["created"])
+	}
+	if fmt.Sprint(first["tags"]) != "[a b c]" {
+		t.Errorf("Expected split tags, got %v", first["tags"])
+	}
+	if first["active"] != true {
+		t.Errorf("Expected active true, got %v", first["active"])
+	}
+	if first["status"] != "unknown" {
+		t.Errorf("Expected default value to pass through untouched, got %v", first["status"])
+	}
+}
+
+func TestConnector_FieldTransforms_FailureReportsFieldAndIndex(t *testing.T) {
+	server := jsonServer(`[{"id": "1"}, {"id": "2"}, {"id": "three"}]`)
+	defer server.Close()
+
+	cfg := &config.Pipeline{
+		Name: "transform-failure-test",
+		Source: config.Source{
+			Type:     config.SourceTypeREST,
+			Endpoint: server.URL,
+			ResponseMapping: config.ResponseMapping{
+				Fields: []config.Field{{Name: "id", Path: "id", Type: "int"}},
+			},
+		},
+	}
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	_, err = connector.Extract(context.Background())
+	if err == nil {
+		t.Fatal("Expected transform error, got nil")
+	}
+	if !errors2.Is(err, errors2.ErrExtraction) {
+		t.Errorf("Expected ErrExtraction, got %v", err)
+	}
+	for _, want := range []string{`field "id"`, "index 2"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error to mention %q, got: %v", want, err)
+		}
+	}
+}
+
+func TestConnector_FieldTransforms_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name  string
+		field config.Field
+	}{
+		{"unknown transform", config.Field{Name: "a", Path: "a", Transform: &config.FieldTransform{Type: "rot13"}}},
+		{"unknown type", config.Field{Name: "a", Path: "a", Type: "uuid"}},
+		{"empty transform", config.Field{Name: "a", Path: "a", Transform: &config.FieldTransform{}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Pipeline{
+				Name: "transform-config-test",
+				Source: config.Source{
+					Type:            config.SourceTypeREST,
+					Endpoint:        "http://example.invalid",
+					ResponseMapping: config.ResponseMapping{Fields: []config.Field{tt.field}},
+				},
+			}
+			_, err := core.NewConnector(cfg)
+			if !errors2.Is(err, errors2.ErrConfiguration) {
+				t.Errorf("Expected ErrConfiguration from NewConnector, got %v", err)
+			}
+		})
+	}
+}
+
+type reverseTransform struct{}
+
+func (reverseTransform) Transform(value interface{}) (interface{}, error) {
+	s := fmt.Sprint(value)
+	out := make([]byte, len(s))
+	for i := range s {
+		out[len(s)-1-i] = s[i]
+	}
+	return string(out), nil
+}
+
+func TestConnector_FieldTransforms_InjectedRegistryGraphQL(t *testing.T) {
+	server := jsonServer(`{"data": {"users": [{"name": "abc"}]}}`)
+	defer server.Close()
+
+	registry := transform.NewRegistry()
+	registry.Register("reverse", func(map[string]interface{}) (transform.Transformer, error) {
+		return reverseTransform{}, nil
+	})
+
+	cfg := &config.Pipeline{
+		Name: "graphql-transform-test",
+		Source: config.Source{
+			Type: config.SourceTypeGraphQL,
+			GraphQLConfig: &config.GraphQLSource{
+				Endpoint: server.URL,
+				Query:    `{ users { name } }`,
+				ResponseMapping: config.ResponseMapping{
+					RootPath: "users",
+					Fields: []config.Field{{Name: "name", Path: "name", Transform: &config.FieldTransform{
+						Chain: []config.FieldTransform{{Type: "reverse"}, {Type: "upper"}},
+					}}},
+				},
+			},
+		},
+	}
+
+	if _, err := core.NewConnector(cfg); err == nil {
+		t.Fatal("Expected default registry to reject unknown 'reverse' transform")
+	}
+
+	connector, err := core.NewConnector(cfg, core.WithTransformRegistry(registry))
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	results, err := connector.Extract(context.Background())
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(results) != 1 || results[0]["name"] != "CBA" {
+		t.Errorf("Expected name 'CBA', got %v", results)
+	}
+}
@@ -0,0 +1,44 @@
+package rest_e2e_tests_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/core"
+)
+
+// restConfig returns a REST pipeline that reads url and maps responses with
+// rm. Tests set pagination, retries and other options on the result.
+func restConfig(name, url string, rm config.ResponseMapping) *config.Pipeline {
+	return &config.Pipeline{
+		Name: name,
+		Source: config.Source{
+			Type:            config.SourceTypeREST,
+			Endpoint:        url,
+			ResponseMapping: rm,
+		},
+	}
+}
+
+func jsonServer(body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+}
+
+func extractAll(t *testing.T, cfg *config.Pipeline) []map[string]interface{} {
+	t.Helper()
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	records, err := connector.Extract(context.Background())
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	return records
+}
@@ -0,0 +1,115 @@
+package rest_e2e_tests_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/core"
+	errors2 "github.com/saturnines/nexus-core/pkg/errors"
+)
+
+func TestConnector_HTTPErrorDetails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "abc-123")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error": "no such collection"}`))
+	}))
+	defer server.Close()
+
+	cfg := restConfig("http-error-test", server.URL+"/items", config.ResponseMapping{
+		Fields: []config.Field{{Name: "id", Path: "id"}},
+	})
+	cfg.Source.QueryParams = map[string]string{"api_key": "s3cret"}
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	_, err = connector.Extract(context.Background())
+
+	if !errors2.Is(err, errors2.ErrHTTPResponse) {
+		t.Fatalf("Expected ErrHTTPResponse, got %v", err)
+	}
+	var apiErr *errors2.APIError
+	if !errors2.As(err, &apiErr) {
+		t.Fatalf("Expected *APIError in chain, got %v", err)
+	}
+	if apiErr.StatusCode != 404 || apiErr.Method != "GET" || apiErr.RequestID != "abc-123" || apiErr.Attempt != 1 {
+		t.Errorf("Unexpected APIError: %+v", apiErr)
+	}
+	if !strings.Contains(apiErr.Body, "no such collection") {
+		t.Errorf("Expected body in error, got %q", apiErr.Body)
+	}
+	if strings.Contains(err.Error(), "s3cret") || !strings.Contains(apiErr.URL, "api_key=REDACTED") {
+		t.Errorf("Expected api_key to be redacted: %v", err)
+	}
+	if errors2.StatusCode(err) != 404 || errors2.IsRetryable(err) {
+		t.Errorf("Expected non-retryable 404, got status %d retryable %v", errors2.StatusCode(err), errors2.IsRetryable(err))
+	}
+}
+
+func TestConnector_HTTPErrorAfterRetries(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cfg := restConfig("http-error-test", server.URL, config.ResponseMapping{
+		Fields: []config.Field{{Name: "id", Path: "id"}},
+	})
+	cfg.RetryConfig = &config.RetryConfig{
+		MaxAttempts:       3,
+		InitialBackoff:    0.001,
+		BackoffMultiplier: 1,
+		RetryableStatuses: []int{503},
+	}
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	_, err = connector.Extract(context.Background())
+
+	var apiErr *errors2.APIError
+	if !errors2.As(err, &apiErr) {
+		t.Fatalf("Expected *APIError in chain, got %v", err)
+	}
+	if apiErr.Attempt != 3 || apiErr.StatusCode != 503 || !errors2.IsRetryable(err) {
+		t.Errorf("Unexpected APIError: %+v", apiErr)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("Expected 3 requests, got %d", got)
+	}
+}
+
+func TestConnector_NonRetryableStatusFromRetryTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("forbidden"))
+	}))
+	defer server.Close()
+
+	cfg := restConfig("http-error-test", server.URL, config.ResponseMapping{
+		Fields: []config.Field{{Name: "id", Path: "id"}},
+	})
+	cfg.RetryConfig = &config.RetryConfig{MaxAttempts: 3, BackoffMultiplier: 1, RetryableStatuses: []int{503}}
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	_, err = connector.Extract(context.Background())
+
+	if errors2.StatusCode(err) != 403 {
+		t.Fatalf("Expected status 403 through the chain, got %v", err)
+	}
+	var apiErr *errors2.APIError
+	errors2.As(err, &apiErr)
+	if apiErr.Body != "forbidden" || apiErr.Attempt != 1 || apiErr.Retryable {
+		t.Errorf("Unexpected APIError: %+v", apiErr)
+	}
+}
@@ -0,0 +1,320 @@
+package rest_e2e_tests_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/saturnines/nexus-core/pkg/checkpoint"
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/core"
+	errors2 "github.com/saturnines/nexus-core/pkg/errors"
+)
+
+// newIncrementalServer returns records updated after the "since" query
+// parameter and records every value of it that it receives.
+func newIncrementalServer(since *[]string) *httptest.Server {
+	records := []map[string]interface{}{
+		{"id": 1, "updated_at": "2024-01-01T00:00:00Z"},
+		{"id": 2, "updated_at": "2024-03-01T00:00:00Z"},
+		{"id": 3, "updated_at": "2024-02-01T00:00:00Z"},
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s := r.URL.Query().Get("since")
+		*since = append(*since, s)
+
+		out := []map[string]interface{}{}
+		for _, rec := range records {
+			if s == "" || rec["updated_at"].(string) > s {
+				out = append(out, rec)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": out})
+	}))
+}
+
+var updatedMapping = config.ResponseMapping{
+	RootPath: "data",
+	Fields: []config.Field{
+		{Name: "id", Path: "id"},
+		{Name: "updated_at", Path: "updated_at"},
+	},
+}
+
+func TestConnector_Incremental_PersistsAndInjectsWatermark(t *testing.T) {
+	var since []string
+	server := newIncrementalServer(&since)
+	defer server.Close()
+
+	store, err := checkpoint.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	cfg := restConfig("incremental-test", server.URL, updatedMapping)
+	cfg.Source.QueryParams = map[string]string{"limit": "10"}
+	cfg.Incremental = &config.Incremental{CursorField: "updated_at", Param: "since"}
+
+	for run, wantRecords := range []int{3, 0} {
+		connector, err := core.NewConnector(cfg, core.WithWatermarkStore(store, ""))
+		if err != nil {
+			t.Fatalf("Failed to create connector: %v", err)
+		}
+		results, err := connector.Extract(context.Background())
+		if err != nil {
+			t.Fatalf("Run %d failed: %v", run+1, err)
+		}
+		if len(results) != wantRecords {
+			t.Errorf("Run %d: expected %d records, got %d", run+1, wantRecords, len(results))
+		}
+	}
+
+	if len(since) != 2 || since[0] != "" || since[1] != "2024-03-01T00:00:00Z" {
+		t.Errorf("Expected second run to send the max updated_at, got %q", since)
+	}
+	if cfg.Source.QueryParams["since"] != "" {
+		t.Error("Watermark should not be written into the pipeline config")
+	}
+
+	saved, err := store.LoadWatermark(context.Background(), cfg.Name)
+	if err != nil || saved != "2024-03-01T00:00:00Z" {
+		t.Errorf("Expected saved watermark, got %q, %v", saved, err)
+	}
+}
+
+func TestConnector_Incremental_InitialValueAndInMemoryWatermark(t *testing.T) {
+	var since []string
+	server := newIncrementalServer(&since)
+	defer server.Close()
+
+	cfg := restConfig("incremental-test", server.URL, updatedMapping)
+	cfg.Incremental = &config.Incremental{CursorField: "updated_at", Param: "since"}
+	cfg.Incremental.InitialValue = "2024-01-15T00:00:00Z"
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	results, err := connector.Extract(context.Background())
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("Expected 2 records after initial value, got %d", len(results))
+	}
+	if connector.Watermark() != "2024-03-01T00:00:00Z" {
+		t.Errorf("Expected watermark 2024-03-01T00:00:00Z, got %q", connector.Watermark())
+	}
+
+	if _, err := connector.Extract(context.Background()); err != nil {
+		t.Fatalf("Second extract failed: %v", err)
+	}
+	if since[1] != "2024-03-01T00:00:00Z" {
+		t.Errorf("Expected second run to reuse the in-memory watermark, got %q", since[1])
+	}
+}
+
+func TestConnector_Incremental_ClearedStoreRestarts(t *testing.T) {
+	var since []string
+	server := newIncrementalServer(&since)
+	defer server.Close()
+
+	store, err := checkpoint.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	cfg := restConfig("incremental-test", server.URL, updatedMapping)
+	cfg.Incremental = &config.Incremental{CursorField: "updated_at", Param: "since"}
+	connector, err := core.NewConnector(cfg, core.WithWatermarkStore(store, ""))
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+
+	if _, err := connector.Extract(context.Background()); err != nil {
+		t.Fatalf("First extract failed: %v", err)
+	}
+	if err := store.SaveWatermark(context.Background(), cfg.Name, ""); err != nil {
+		t.Fatalf("SaveWatermark: %v", err)
+	}
+	results, err := connector.Extract(context.Background())
+	if err != nil {
+		t.Fatalf("Second extract failed: %v", err)
+	}
+	if len(results) != 3 || len(since) != 2 || since[1] != "" {
+		t.Errorf("Expected a cleared store to restart the sync, got %d records with since %q", len(results), since)
+	}
+}
+
+func TestConnector_Incremental_ConcurrentRuns(t *testing.T) {
+	var mu sync.Mutex
+	var since []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		since = append(since, r.URL.Query().Get("since"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": [{"id": 1, "updated_at": "2024-03-01T00:00:00Z"}]}`))
+	}))
+	defer server.Close()
+
+	cfg := restConfig("incremental-test", server.URL, updatedMapping)
+	cfg.Source.QueryParams = map[string]string{"limit": "10"}
+	cfg.Incremental = &config.Incremental{CursorField: "updated_at", Param: "since"}
+	cfg.Incremental.InitialValue = "2024-01-01T00:00:00Z"
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := connector.Extract(context.Background()); err != nil {
+				t.Errorf("Extract failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, s := range since {
+		if s != "2024-01-01T00:00:00Z" && s != "2024-03-01T00:00:00Z" {
+			t.Errorf("Unexpected since %q", s)
+		}
+	}
+	if cfg.Source.QueryParams["since"] != "" {
+		t.Error("Watermark should not be written into the pipeline config")
+	}
+}
+
+func TestConnector_Incremental_SlowerRunKeepsNewerWatermark(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		updated := "2024-03-01T00:00:00Z"
+		if atomic.AddInt32(&calls, 1) == 1 {
+			<-release
+			updated = "2024-02-01T00:00:00Z"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": []map[string]interface{}{{"id": 1, "updated_at": updated}},
+		})
+	}))
+	defer server.Close()
+
+	store, err := checkpoint.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	cfg := restConfig("incremental-test", server.URL, updatedMapping)
+	cfg.Incremental = &config.Incremental{CursorField: "updated_at", Param: "since"}
+	connector, err := core.NewConnector(cfg, core.WithWatermarkStore(store, ""))
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+
+	slow := make(chan error)
+	go func() {
+		_, err := connector.Extract(context.Background())
+		slow <- err
+	}()
+	for atomic.LoadInt32(&calls) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := connector.Extract(context.Background()); err != nil {
+		t.Fatalf("Fast extract failed: %v", err)
+	}
+	close(release)
+	if err := <-slow; err != nil {
+		t.Fatalf("Slow extract failed: %v", err)
+	}
+
+	if wm := connector.Watermark(); wm != "2024-03-01T00:00:00Z" {
+		t.Errorf("Expected the newer watermark to be kept, got %q", wm)
+	}
+	stored, err := store.LoadWatermark(context.Background(), cfg.Name)
+	if err != nil {
+		t.Fatalf("LoadWatermark: %v", err)
+	}
+	if stored != "2024-03-01T00:00:00Z" {
+		t.Errorf("Expected the newer watermark to stay stored, got %q", stored)
+	}
+}
+
+func TestConnector_Incremental_AbandonedRunDoesNotCommit(t *testing.T) {
+	var since []string
+	server := newIncrementalServer(&since)
+	defer server.Close()
+
+	cfg := restConfig("incremental-test", server.URL, updatedMapping)
+	cfg.Incremental = &config.Incremental{CursorField: "updated_at", Param: "since"}
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	for range connector.Stream(context.Background()) {
+		break
+	}
+	if wm := connector.Watermark(); wm != "" {
+		t.Errorf("Expected no watermark after an abandoned run, got %q", wm)
+	}
+}
+
+func TestConnector_Incremental_NumericCursorGraphQL(t *testing.T) {
+	var since []interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		since = append(since, body.Variables["since"])
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"events": [{"seq": 9}, {"seq": 10}, {"seq": 2}]}}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Pipeline{
+		Name: "incremental-graphql",
+		Source: config.Source{
+			Type: config.SourceTypeGraphQL,
+			GraphQLConfig: &config.GraphQLSource{
+				Endpoint: server.URL,
+				Query:    `query($since: String) { events(since: $since) { seq } }`,
+				ResponseMapping: config.ResponseMapping{
+					RootPath: "events",
+					Fields:   []config.Field{{Name: "seq", Path: "seq"}},
+				},
+			},
+		},
+		Incremental: &config.Incremental{CursorField: "seq", Variable: "since"},
+	}
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := connector.Extract(context.Background()); err != nil {
+			t.Fatalf("Extract failed: %v", err)
+		}
+	}
+	// 10 > 9 numerically even though "9" > "10" lexically.
+	if len(since) != 2 || since[0] != nil || since[1] != "10" {
+		t.Errorf("Expected since variable to be unset then \"10\", got %v", since)
+	}
+}
+
+func TestConnector_Incremental_InvalidConfig(t *testing.T) {
+	cfg := restConfig("incremental-test", "http://example.invalid", updatedMapping)
+	cfg.Incremental = &config.Incremental{CursorField: "updated_at"}
+	if _, err := core.NewConnector(cfg); !errors2.Is(err, errors2.ErrConfiguration) {
+		t.Errorf("Expected ErrConfiguration for missing param, got %v", err)
+	}
+}
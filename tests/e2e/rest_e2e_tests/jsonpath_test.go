@@ -0,0 +1,192 @@
+package rest_e2e_tests_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/core"
+	errors2 "github.com/saturnines/nexus-core/pkg/errors"
+	"github.com/saturnines/nexus-core/pkg/jsonpath"
+)
+
+func TestConnector_JSONPathFieldsAndRoot(t *testing.T) {
+	server := jsonServer(`{"result": {"accounts": [
+		{"id": 1, "status": "active", "emails": [{"type": "work", "value": "a@work"}, {"type": "primary", "value": "a@home"}], "meta": {"x.y": "dotted"}},
+		{"id": 2, "status": "closed", "emails": [{"type": "primary", "value": "b@home"}]},
+		{"id": 3, "status": "active", "emails": [], "meta": {"x.y": "other"}}
+	]}}`)
+	defer server.Close()
+
+	cfg := &config.Pipeline{
+		Name: "jsonpath-test",
+		Source: config.Source{
+			Type:     config.SourceTypeREST,
+			Endpoint: server.URL,
+			ResponseMapping: config.ResponseMapping{
+				RootPath: "$.result.accounts[?(@.status == 'active')]",
+				Fields: []config.Field{
+					{Name: "id", Path: "id"},
+					{Name: "primary_email", Path: "emails[?(@.type=='primary')].value"},
+					{Name: "dotted", Path: "meta['x.y']"},
+				},
+			},
+		},
+	}
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	records, err := connector.Extract(context.Background())
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 active accounts, got %d: %v", len(records), records)
+	}
+	if records[0]["id"] != 1.0 || records[1]["id"] != 3.0 {
+		t.Errorf("Unexpected ids: %v", records)
+	}
+	if got := records[0]["primary_email"]; !reflect.DeepEqual(got, []interface{}{"a@home"}) {
+		t.Errorf("Expected primary email list, got %#v", got)
+	}
+	if _, ok := records[1]["primary_email"]; ok {
+		t.Errorf("Expected no primary_email for account 3, got %v", records[1])
+	}
+	if records[0]["dotted"] != "dotted" || records[1]["dotted"] != "other" {
+		t.Errorf("Unexpected quoted-key values: %v", records)
+	}
+}
+
+func TestConnector_JSONPathCursorPath(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("after") == "" {
+			w.Write([]byte(`{"data": [{"id": "a"}, {"id": "b"}]}`))
+			return
+		}
+		w.Write([]byte(`{"data": []}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Pipeline{
+		Name: "jsonpath-cursor-test",
+		Source: config.Source{
+			Type:     config.SourceTypeREST,
+			Endpoint: server.URL,
+			ResponseMapping: config.ResponseMapping{
+				RootPath: "data",
+				Fields:   []config.Field{{Name: "id", Path: "id"}},
+			},
+		},
+		Pagination: &config.Pagination{
+			Type:        config.PaginationTypeCursor,
+			CursorParam: "after",
+			CursorPath:  "data[-1].id",
+		},
+	}
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	records, err := connector.Extract(context.Background())
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+}
+
+func TestConnector_JSONPathPagePaths(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		page := r.URL.Query().Get("page")
+		w.Write([]byte(`{"data": [{"id": "` + page + `", "name": "n"}], "meta": {"stats": [{"has.more": ` +
+			strconv.FormatBool(page == "1") + `}]}}`))
+	}))
+	defer server.Close()
+
+	cfg := restConfig("stream-test", server.URL, streamPageMapping)
+	pagination := streamPagePagination
+	cfg.Pagination = &pagination
+	cfg.Pagination.HasMorePath = "meta.stats[0]['has.more']"
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	records, err := connector.Extract(context.Background())
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(records) != 2 || records[1]["id"] != "2" {
+		t.Fatalf("Expected records from pages 1 and 2, got %v", records)
+	}
+}
+
+func TestConnector_JSONPathSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*config.Pipeline)
+	}{
+		{"field path", func(c *config.Pipeline) {
+			c.Source.ResponseMapping.Fields[0].Path = "items[?(@.id ==)]"
+		}},
+		{"root path", func(c *config.Pipeline) {
+			c.Source.ResponseMapping.RootPath = "data['unterminated"
+		}},
+		{"pagination path", func(c *config.Pipeline) {
+			c.Pagination = &config.Pagination{
+				Type:        config.PaginationTypeCursor,
+				CursorParam: "after",
+				CursorPath:  "meta.[",
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := restConfig("jsonpath-test", "http://localhost", config.ResponseMapping{
+				Fields: []config.Field{{Name: "id", Path: "id"}},
+			})
+			tt.modify(cfg)
+
+			_, err := core.NewConnector(cfg)
+			if !errors2.Is(err, errors2.ErrConfiguration) {
+				t.Fatalf("Expected configuration error, got %v", err)
+			}
+			var syn *jsonpath.SyntaxError
+			if !errors2.As(err, &syn) {
+				t.Errorf("Expected *jsonpath.SyntaxError in chain, got %v", err)
+			}
+		})
+	}
+}
+
+func TestConnector_LegacyWildcardRootOnEmptyPage(t *testing.T) {
+	server := jsonServer(`{"data": {"items": []}}`)
+	defer server.Close()
+
+	records := extractAll(t, &config.Pipeline{
+		Name: "legacy-root",
+		Source: config.Source{
+			Type:     config.SourceTypeREST,
+			Endpoint: server.URL,
+			ResponseMapping: config.ResponseMapping{
+				RootPath: "data.items[*]",
+				Fields:   []config.Field{{Name: "id", Path: "id"}},
+			},
+		},
+	})
+	if len(records) != 0 {
+		t.Errorf("Expected no records for an empty page, got %v", records)
+	}
+}
@@ -0,0 +1,172 @@
+package rest_e2e_tests_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/core"
+	errors2 "github.com/saturnines/nexus-core/pkg/errors"
+)
+
+const ordersNDJSON = "{\"id\": 1, \"customer\": {\"email\": \"a@example.com\"}}\r\n" +
+	"\n" +
+	"{\"id\": 2, \"customer\": {\"email\": \"b@example.com\"}}\n" +
+	"{\"id\": 3, \"customer\": {\"email\": \"c@example.com\"}}"
+
+var ordersMapping = config.ResponseMapping{
+	ResponseFormat: config.ResponseFormatNDJSON,
+	Fields: []config.Field{
+		{Name: "id", Path: "id", Type: "integer"},
+		{Name: "email", Path: "customer.email"},
+	},
+}
+
+func TestConnector_NDJSON(t *testing.T) {
+	server := jsonServer(ordersNDJSON)
+	defer server.Close()
+
+	want := []map[string]interface{}{
+		{"id": 1, "email": "a@example.com"},
+		{"id": 2, "email": "b@example.com"},
+		{"id": 3, "email": "c@example.com"},
+	}
+	for _, streaming := range []bool{false, true} {
+		t.Run(fmt.Sprintf("streaming=%t", streaming), func(t *testing.T) {
+			cfg := restConfig("ndjson-test", server.URL, ordersMapping)
+			cfg.Source.ResponseMapping.Streaming = streaming
+			records := extractAll(t, cfg)
+			if !reflect.DeepEqual(records, want) {
+				t.Errorf("Expected %v, got %v", want, records)
+			}
+		})
+	}
+}
+
+func TestConnector_NDJSONExactNumbers(t *testing.T) {
+	server := jsonServer("{\"id\": 1234567890123456789}\n")
+	defer server.Close()
+
+	cfg := restConfig("ndjson-test", server.URL, ordersMapping)
+	cfg.Source.ResponseMapping.NumberMode = config.NumberModeExact
+	cfg.Source.ResponseMapping.Fields = []config.Field{{Name: "id", Path: "id"}}
+
+	records := extractAll(t, cfg)
+	if len(records) != 1 || records[0]["id"] != json.Number("1234567890123456789") {
+		t.Errorf("Expected the exact id, got %v", records)
+	}
+}
+
+func TestConnector_NDJSONCursorFromLastLine(t *testing.T) {
+	for _, streaming := range []bool{false, true} {
+		t.Run(fmt.Sprintf("streaming=%t", streaming), func(t *testing.T) {
+			var cursors []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				after := r.URL.Query().Get("after")
+				cursors = append(cursors, after)
+				switch after {
+				case "":
+					w.Write([]byte("{\"id\": 1}\n{\"id\": 2}\n"))
+				case "2":
+					w.Write([]byte("{\"id\": 3}\n"))
+				}
+			}))
+			defer server.Close()
+
+			cfg := restConfig("ndjson-test", server.URL, ordersMapping)
+			cfg.Source.ResponseMapping.Streaming = streaming
+			cfg.Source.ResponseMapping.Fields = []config.Field{{Name: "id", Path: "id"}}
+			cfg.Pagination = &config.Pagination{
+				Type:        config.PaginationTypeCursor,
+				CursorParam: "after",
+				CursorPath:  "data[-1].id",
+			}
+
+			records := extractAll(t, cfg)
+			if len(records) != 3 {
+				t.Fatalf("Expected 3 records, got %d", len(records))
+			}
+			if want := []string{"", "2", "3"}; !reflect.DeepEqual(cursors, want) {
+				t.Errorf("Expected cursors %v, got %v", want, cursors)
+			}
+		})
+	}
+}
+
+func TestConnector_NDJSONBadLines(t *testing.T) {
+	body := "{\"id\": 1, \"customer\": {\"email\": \"a@example.com\"}}\n" +
+		"{\"id\": 2, \"customer\": \n" +
+		"\n" +
+		"{\"id\": \"x\", \"customer\": {\"email\": \"c@example.com\"}}\n" +
+		"{\"id\": 5, \"customer\": {\"email\": \"e@example.com\"}}\n"
+	server := jsonServer(body)
+	defer server.Close()
+
+	t.Run("fail fast names the line", func(t *testing.T) {
+		connector, err := core.NewConnector(restConfig("ndjson-test", server.URL, ordersMapping))
+		if err != nil {
+			t.Fatalf("Failed to create connector: %v", err)
+		}
+		_, err = connector.Extract(context.Background())
+		if !errors2.Is(err, errors2.ErrExtraction) || !strings.Contains(err.Error(), "line 2") {
+			t.Errorf("Expected ErrExtraction for line 2, got %v", err)
+		}
+	})
+
+	for _, streaming := range []bool{false, true} {
+		t.Run(fmt.Sprintf("skip streaming=%t", streaming), func(t *testing.T) {
+			cfg := restConfig("ndjson-test", server.URL, ordersMapping)
+			cfg.Source.ResponseMapping.Streaming = streaming
+			cfg.ErrorPolicy = &config.ErrorPolicy{Mode: config.ErrorPolicySkip}
+
+			dead := &memorySink{}
+			connector, err := core.NewConnector(cfg, core.WithDeadLetter(dead))
+			if err != nil {
+				t.Fatalf("Failed to create connector: %v", err)
+			}
+			records, err := connector.Extract(context.Background())
+			if err != nil {
+				t.Fatalf("Extract failed: %v", err)
+			}
+			if len(records) != 2 {
+				t.Errorf("Expected 2 good records, got %v", records)
+			}
+
+			if len(dead.records) != 2 {
+				t.Fatalf("Expected 2 dead letters, got %v", dead.records)
+			}
+			if d := dead.records[0]; d["line"] != 2 || d["raw"] != `"{\"id\": 2, \"customer\":"` {
+				t.Errorf("Unexpected dead letter for the malformed line: %v", d)
+			}
+			if d := dead.records[1]; d["line"] != 4 || !strings.Contains(d["reason"].(string), "line 4") {
+				t.Errorf("Unexpected dead letter for the unmappable line: %v", d)
+			}
+		})
+	}
+}
+
+func TestRestExtractor_NDJSONItems(t *testing.T) {
+	e, err := core.NewRestExtractor(config.ResponseMapping{
+		ResponseFormat: config.ResponseFormatNDJSON,
+		Fields:         []config.Field{{Name: "id", Path: "id"}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to create extractor: %v", err)
+	}
+
+	items, err := e.Items([]byte("{\"id\": 1}\n{\"id\": 2}\n"))
+	if err != nil || len(items) != 2 {
+		t.Fatalf("Expected 2 items, got %v, %v", items, err)
+	}
+
+	_, err = e.Items([]byte("{\"id\": 1}\n\n[1, 2\n"))
+	if !errors2.Is(err, errors2.ErrExtraction) || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("Expected ErrExtraction for line 3, got %v", err)
+	}
+}
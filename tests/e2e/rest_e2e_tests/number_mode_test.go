@@ -0,0 +1,117 @@
+package rest_e2e_tests_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/core"
+	"github.com/saturnines/nexus-core/pkg/transform"
+)
+
+func TestConnector_ExactNumberMode(t *testing.T) {
+	server := jsonServer(`{"data": [
+		{"id": 1234567890123456789, "amount": 19.990, "qty": 3, "nested": {"ref": 9007199254740993}}
+	]}`)
+	defer server.Close()
+
+	cfg := &config.Pipeline{
+		Name: "number-mode-test",
+		Source: config.Source{
+			Type:     config.SourceTypeREST,
+			Endpoint: server.URL,
+			ResponseMapping: config.ResponseMapping{
+				RootPath:   "data",
+				NumberMode: config.NumberModeExact,
+				Fields: []config.Field{
+					{Name: "id", Path: "id"},
+					{Name: "id_string", Path: "id", Type: "string"},
+					{Name: "amount", Path: "amount", Type: "decimal"},
+					{Name: "qty", Path: "qty", Type: "integer"},
+					{Name: "ref", Path: "nested.ref"},
+				},
+			},
+		},
+	}
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	records, err := connector.Extract(context.Background())
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+
+	r := records[0]
+	if r["id"] != json.Number("1234567890123456789") {
+		t.Errorf("Expected exact id, got %#v", r["id"])
+	}
+	if r["id_string"] != "1234567890123456789" {
+		t.Errorf("Expected exact string id, got %#v", r["id_string"])
+	}
+	if r["amount"] != transform.Decimal("19.990") {
+		t.Errorf("Expected decimal amount, got %#v", r["amount"])
+	}
+	if r["qty"] != 3 {
+		t.Errorf("Expected int qty, got %#v", r["qty"])
+	}
+
+	out, err := json.Marshal(r)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	want := `{"amount":19.990,"id":1234567890123456789,"id_string":"1234567890123456789","qty":3,"ref":9007199254740993}`
+	if string(out) != want {
+		t.Errorf("Expected %s, got %s", want, out)
+	}
+}
+
+func TestConnector_NumericCursorIsExact(t *testing.T) {
+	var cursors []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cursors = append(cursors, r.URL.Query().Get("max_id"))
+		w.Header().Set("Content-Type", "application/json")
+		if len(cursors) == 1 {
+			w.Write([]byte(`{"data": [{"id": 1}], "next_max_id": 1234567890123456789}`))
+			return
+		}
+		w.Write([]byte(`{"data": [{"id": 2}], "next_max_id": null}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Pipeline{
+		Name: "numeric-cursor-test",
+		Source: config.Source{
+			Type:     config.SourceTypeREST,
+			Endpoint: server.URL,
+			ResponseMapping: config.ResponseMapping{
+				RootPath: "data",
+				Fields:   []config.Field{{Name: "id", Path: "id"}},
+			},
+		},
+		Pagination: &config.Pagination{
+			Type:        config.PaginationTypeCursor,
+			CursorParam: "max_id",
+			CursorPath:  "next_max_id",
+		},
+	}
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	if _, err := connector.Extract(context.Background()); err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if len(cursors) != 2 || cursors[1] != "1234567890123456789" {
+		t.Errorf("Expected the numeric cursor to be sent verbatim, got %v", cursors)
+	}
+}
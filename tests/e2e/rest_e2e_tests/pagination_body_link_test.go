@@ -0,0 +1,191 @@
+package rest_e2e_tests_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/core"
+)
+
+// newBodyLinkServer serves three pages; next(page, baseURL) returns the
+// body fields that point at the following page, or nil on the last page.
+func newBodyLinkServer(rootPath string, next func(page int, baseURL string) map[string]interface{}) (*httptest.Server, *[]string) {
+	var requested []string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.RequestURI())
+
+		page := 1
+		if p := r.URL.Query().Get("page"); p != "" {
+			page, _ = strconv.Atoi(p)
+		}
+
+		body := map[string]interface{}{
+			rootPath: []interface{}{
+				map[string]interface{}{"id": fmt.Sprintf("p%d-1", page)},
+				map[string]interface{}{"id": fmt.Sprintf("p%d-2", page)},
+			},
+		}
+		if page < 3 {
+			for k, v := range next(page, server.URL) {
+				body[k] = v
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(body)
+	}))
+	return server, &requested
+}
+
+func extractIDs(t *testing.T, cfg *config.Pipeline) []interface{} {
+	t.Helper()
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	results, err := connector.Extract(context.Background())
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	ids := make([]interface{}, len(results))
+	for i, r := range results {
+		ids[i] = r["id"]
+	}
+	return ids
+}
+
+func TestConnector_BodyLinkPagination(t *testing.T) {
+	tests := []struct {
+		name         string
+		rootPath     string
+		nextLinkPath string
+		next         func(page int, baseURL string) map[string]interface{}
+	}{
+		{
+			name:         "salesforce absolute path",
+			rootPath:     "records",
+			nextLinkPath: "nextRecordsUrl",
+			next: func(page int, _ string) map[string]interface{} {
+				return map[string]interface{}{"nextRecordsUrl": fmt.Sprintf("/records?page=%d", page+1)}
+			},
+		},
+		{
+			name:         "odata dotted key absolute url",
+			rootPath:     "value",
+			nextLinkPath: "@odata.nextLink",
+			next: func(page int, baseURL string) map[string]interface{} {
+				return map[string]interface{}{"@odata.nextLink": fmt.Sprintf("%s/records?page=%d", baseURL, page+1)}
+			},
+		},
+		{
+			name:         "hal nested relative link",
+			rootPath:     "items",
+			nextLinkPath: "_links.next.href",
+			next: func(page int, _ string) map[string]interface{} {
+				return map[string]interface{}{"_links": map[string]interface{}{
+					"next": map[string]interface{}{"href": fmt.Sprintf("records?page=%d", page+1)},
+				}}
+			},
+		},
+		{
+			name:         "github style scheme relative",
+			rootPath:     "items",
+			nextLinkPath: "next",
+			next: func(page int, baseURL string) map[string]interface{} {
+				return map[string]interface{}{"next": fmt.Sprintf("%s/records?page=%d", baseURL[len("http:"):], page+1)}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requested := newBodyLinkServer(tt.rootPath, tt.next)
+			defer server.Close()
+
+			cfg := restConfig("body-link-test", server.URL+"/records", config.ResponseMapping{
+				RootPath: tt.rootPath,
+				Fields:   []config.Field{{Name: "id", Path: "id"}},
+			})
+			cfg.Pagination = &config.Pagination{
+				Type:         config.PaginationTypeLink,
+				NextLinkPath: tt.nextLinkPath,
+			}
+			ids := extractIDs(t, cfg)
+
+			want := "[p1-1 p1-2 p2-1 p2-2 p3-1 p3-2]"
+			if fmt.Sprint(ids) != want {
+				t.Errorf("Expected ids %s, got %v", want, ids)
+			}
+			wantReqs := "[/records /records?page=2 /records?page=3]"
+			if fmt.Sprint(*requested) != wantReqs {
+				t.Errorf("Expected requests %s, got %v", wantReqs, *requested)
+			}
+		})
+	}
+}
+
+func TestConnector_BodyLinkPagination_NullStops(t *testing.T) {
+	server, requested := newBodyLinkServer("records", func(page int, _ string) map[string]interface{} {
+		if page == 1 {
+			return map[string]interface{}{"nextRecordsUrl": "/records?page=2"}
+		}
+		return map[string]interface{}{"nextRecordsUrl": nil}
+	})
+	defer server.Close()
+
+	cfg := restConfig("body-link-test", server.URL+"/records", config.ResponseMapping{
+		RootPath: "records",
+		Fields:   []config.Field{{Name: "id", Path: "id"}},
+	})
+	cfg.Pagination = &config.Pagination{
+		Type:         config.PaginationTypeLink,
+		NextLinkPath: "nextRecordsUrl",
+	}
+	ids := extractIDs(t, cfg)
+	if len(ids) != 4 {
+		t.Errorf("Expected 4 records, got %d", len(ids))
+	}
+	if len(*requested) != 2 {
+		t.Errorf("Expected pagination to stop after 2 requests, got %v", *requested)
+	}
+}
+
+func TestConnector_BodyLinkPagination_FallsBackToLinkHeader(t *testing.T) {
+	var server *httptest.Server
+	requests := 0
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/records?page=2>; rel="next"`, server.URL))
+			w.Write([]byte(`{"records": [{"id": "a"}]}`))
+			return
+		}
+		w.Write([]byte(`{"records": [{"id": "b"}]}`))
+	}))
+	defer server.Close()
+
+	cfg := restConfig("body-link-test", server.URL+"/records", config.ResponseMapping{
+		RootPath: "records",
+		Fields:   []config.Field{{Name: "id", Path: "id"}},
+	})
+	cfg.Pagination = &config.Pagination{
+		Type:         config.PaginationTypeLink,
+		NextLinkPath: "nextRecordsUrl",
+	}
+
+	ids := extractIDs(t, cfg)
+	if fmt.Sprint(ids) != "[a b]" {
+		t.Errorf("Expected ids [a b], got %v", ids)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
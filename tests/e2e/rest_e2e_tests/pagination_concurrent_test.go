@@ -0,0 +1,265 @@
+package rest_e2e_tests_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/saturnines/nexus-core/pkg/checkpoint"
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/core"
+	errors2 "github.com/saturnines/nexus-core/pkg/errors"
+)
+
+// concurrentServer serves totalItems ids through page/size or offset/limit
+// params. Earlier pages answer slower so out-of-order completion is likely.
+type concurrentServer struct {
+	*httptest.Server
+	mu          sync.Mutex
+	hits        map[string]int
+	inFlight    int32
+	maxInFlight int32
+	failOnce    map[string]int // request key -> status to return once
+}
+
+func newConcurrentServer(totalItems int) *concurrentServer {
+	s := &concurrentServer{hits: map[string]int{}, failOnce: map[string]int{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cur := atomic.AddInt32(&s.inFlight, 1)
+		defer atomic.AddInt32(&s.inFlight, -1)
+		for {
+			old := atomic.LoadInt32(&s.maxInFlight)
+			if cur <= old || atomic.CompareAndSwapInt32(&s.maxInFlight, old, cur) {
+				break
+			}
+		}
+
+		q := r.URL.Query()
+		size, _ := strconv.Atoi(q.Get("size"))
+		start := 0
+		key := "offset=" + q.Get("offset")
+		if p := q.Get("page"); p != "" {
+			page, _ := strconv.Atoi(p)
+			start = (page - 1) * size
+			key = "page=" + p
+		} else {
+			start, _ = strconv.Atoi(q.Get("offset"))
+		}
+
+		s.mu.Lock()
+		s.hits[key]++
+		status, fail := s.failOnce[key]
+		delete(s.failOnce, key)
+		s.mu.Unlock()
+		if fail {
+			w.WriteHeader(status)
+			return
+		}
+
+		time.Sleep(time.Duration(20-start/size) * time.Millisecond)
+
+		items := []interface{}{}
+		for i := start; i < start+size && i < totalItems; i++ {
+			items = append(items, map[string]interface{}{"id": i + 1})
+		}
+		totalPages := (totalItems + size - 1) / size
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": items,
+			"meta": map[string]interface{}{"total_pages": totalPages, "total_count": totalItems},
+		})
+	}))
+	return s
+}
+
+// concurrentMapping and concurrentPagination read newConcurrentServer.
+// Tests copy concurrentPagination and set the concurrency.
+var concurrentMapping = config.ResponseMapping{
+	RootPath: "data",
+	Fields:   []config.Field{{Name: "id", Path: "id"}},
+}
+
+var concurrentPagination = config.Pagination{
+	Type:           config.PaginationTypePage,
+	PageParam:      "page",
+	SizeParam:      "size",
+	PageSize:       5,
+	TotalPagesPath: "meta.total_pages",
+}
+
+func assertSequentialIDs(t *testing.T, results []map[string]interface{}, n int) {
+	t.Helper()
+	if len(results) != n {
+		t.Fatalf("Expected %d records, got %d", n, len(results))
+	}
+	for i, r := range results {
+		if r["id"] != float64(i+1) {
+			t.Fatalf("Expected record %d to have id %d, got %v", i, i+1, r["id"])
+		}
+	}
+}
+
+func TestConnector_ConcurrentPages_KeepsPageOrder(t *testing.T) {
+	server := newConcurrentServer(48)
+	defer server.Close()
+
+	cfg := restConfig("concurrent-pages", server.URL, concurrentMapping)
+	pagination := concurrentPagination
+	pagination.Concurrency = 4
+	cfg.Pagination = &pagination
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	results, err := connector.Extract(context.Background())
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	assertSequentialIDs(t, results, 48)
+	if len(server.hits) != 10 {
+		t.Errorf("Expected 10 distinct pages, got %d", len(server.hits))
+	}
+	for key, n := range server.hits {
+		if n != 1 {
+			t.Errorf("Expected %s to be fetched once, got %d", key, n)
+		}
+	}
+	if server.maxInFlight < 2 || server.maxInFlight > 4 {
+		t.Errorf("Expected between 2 and 4 requests in flight, saw %d", server.maxInFlight)
+	}
+}
+
+func TestConnector_ConcurrentOffsets_KeepsOrder(t *testing.T) {
+	server := newConcurrentServer(23)
+	defer server.Close()
+
+	cfg := restConfig("concurrent-pages", server.URL, concurrentMapping)
+	cfg.Pagination = &config.Pagination{
+		Type:            config.PaginationTypeOffset,
+		Concurrency:     3,
+		OffsetParam:     "offset",
+		LimitParam:      "size",
+		OffsetIncrement: 5,
+		TotalCountPath:  "meta.total_count",
+	}
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	results, err := connector.Extract(context.Background())
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	assertSequentialIDs(t, results, 23)
+	if server.maxInFlight > 3 {
+		t.Errorf("Expected at most 3 requests in flight, saw %d", server.maxInFlight)
+	}
+}
+
+func TestConnector_ConcurrentPages_RetriesAndErrors(t *testing.T) {
+	t.Run("retry config applies to workers", func(t *testing.T) {
+		server := newConcurrentServer(30)
+		defer server.Close()
+		server.failOnce["page=4"] = http.StatusServiceUnavailable
+
+		cfg := restConfig("concurrent-pages", server.URL, concurrentMapping)
+		pagination := concurrentPagination
+		pagination.Concurrency = 4
+		cfg.Pagination = &pagination
+		cfg.RetryConfig = &config.RetryConfig{
+			MaxAttempts:       2,
+			InitialBackoff:    0.01,
+			BackoffMultiplier: 1,
+			RetryableStatuses: []int{http.StatusServiceUnavailable},
+		}
+		connector, err := core.NewConnector(cfg)
+		if err != nil {
+			t.Fatalf("Failed to create connector: %v", err)
+		}
+		results, err := connector.Extract(context.Background())
+		if err != nil {
+			t.Fatalf("Extract failed: %v", err)
+		}
+		assertSequentialIDs(t, results, 30)
+	})
+
+	t.Run("workers back off at the same time", func(t *testing.T) {
+		server := newConcurrentServer(40)
+		defer server.Close()
+		for _, page := range []string{"2", "3", "4", "5", "6", "7"} {
+			server.failOnce["page="+page] = http.StatusServiceUnavailable
+		}
+
+		cfg := restConfig("concurrent-pages", server.URL, concurrentMapping)
+		pagination := concurrentPagination
+		pagination.Concurrency = 4
+		cfg.Pagination = &pagination
+		cfg.RetryConfig = &config.RetryConfig{
+			MaxAttempts:       3,
+			InitialBackoff:    0.01,
+			BackoffMultiplier: 2,
+			RetryableStatuses: []int{http.StatusServiceUnavailable},
+		}
+		connector, err := core.NewConnector(cfg)
+		if err != nil {
+			t.Fatalf("Failed to create connector: %v", err)
+		}
+		results, err := connector.Extract(context.Background())
+		if err != nil {
+			t.Fatalf("Extract failed: %v", err)
+		}
+		assertSequentialIDs(t, results, 40)
+		for _, page := range []string{"2", "3", "4", "5", "6", "7"} {
+			if server.hits["page="+page] != 2 {
+				t.Errorf("Expected page %s to be retried once, got %d requests", page, server.hits["page="+page])
+			}
+		}
+	})
+
+	t.Run("failed page stops extraction and keeps checkpoint", func(t *testing.T) {
+		server := newConcurrentServer(30)
+		defer server.Close()
+		server.failOnce["page=4"] = http.StatusInternalServerError
+
+		store, err := checkpoint.NewFileStore(t.TempDir())
+		if err != nil {
+			t.Fatalf("NewFileStore: %v", err)
+		}
+		cfg := restConfig("concurrent-pages", server.URL, concurrentMapping)
+		pagination := concurrentPagination
+		pagination.Concurrency = 4
+		cfg.Pagination = &pagination
+		connector, err := core.NewConnector(cfg, core.WithCheckpoint(store, ""))
+		if err != nil {
+			t.Fatalf("Failed to create connector: %v", err)
+		}
+		_, err = connector.Extract(context.Background())
+		if !errors2.Is(err, errors2.ErrHTTPResponse) {
+			t.Fatalf("Expected ErrHTTPResponse, got %v", err)
+		}
+		state, _ := store.Load(context.Background(), cfg.Name)
+		if state == nil || state.Page != 4 {
+			t.Fatalf("Expected checkpoint at page 4, got %+v", state)
+		}
+
+		connector, err = core.NewConnector(cfg, core.WithCheckpoint(store, ""))
+		if err != nil {
+			t.Fatalf("Failed to create connector: %v", err)
+		}
+		results, err := connector.Extract(context.Background())
+		if err != nil {
+			t.Fatalf("Resumed run failed: %v", err)
+		}
+		if len(results) != 15 || results[0]["id"] != float64(16) {
+			t.Errorf("Expected resumed run to return pages 4-6, got %d records starting %v", len(results), results[0]["id"])
+		}
+	})
+}
@@ -383,3 +383,43 @@ func TestConnector_PagePagination_EmptyFirstPage(t *testing.T) {
 
 	t.Logf("Correctly handled empty first page")
 }
+
+// TestConnector_PagePagination_StringHasMore checks that a JSON flag sent as
+// a string is not read as a bool, so pagination stops after the first page.
+func TestConnector_PagePagination_StringHasMore(t *testing.T) {
+	requests := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items": [{"id": 1}], "has_more": "true"}`))
+	}))
+	defer mockServer.Close()
+
+	cfg := &config.Pipeline{
+		Name: "page-pagination-string-flag",
+		Source: config.Source{
+			Type:     config.SourceTypeREST,
+			Endpoint: mockServer.URL,
+			ResponseMapping: config.ResponseMapping{
+				RootPath: "items",
+				Fields:   []config.Field{{Name: "id", Path: "id"}},
+			},
+		},
+		Pagination: &config.Pagination{
+			Type:        config.PaginationTypePage,
+			PageParam:   "page",
+			HasMorePath: "has_more",
+		},
+	}
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	if _, err := connector.Extract(context.Background()); err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d", requests)
+	}
+}
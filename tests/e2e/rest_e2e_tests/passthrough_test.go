@@ -0,0 +1,91 @@
+package rest_e2e_tests_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+)
+
+const accountsBody = `{"data": [{
+	"Id": "001",
+	"Name": "Acme",
+	"Secret": "s3cr3t",
+	"BillingAddress": {"City": "Berlin", "Geo": {"Lat": 52.5}},
+	"Tags": ["a", "b"]
+}]}`
+
+func TestConnector_Passthrough(t *testing.T) {
+	server := jsonServer(accountsBody)
+	defer server.Close()
+
+	records := extractAll(t, restConfig("passthrough-test", server.URL, config.ResponseMapping{
+		RootPath: "data",
+		Mode:     config.MappingModePassthrough,
+		Exclude:  []string{"Secret"},
+		Fields:   []config.Field{{Name: "Name", Path: "Name", Transform: &config.FieldTransform{Type: "upper"}}},
+	}))
+
+	want := []map[string]interface{}{{
+		"Id":             "001",
+		"Name":           "ACME",
+		"BillingAddress": map[string]interface{}{"City": "Berlin", "Geo": map[string]interface{}{"Lat": 52.5}},
+		"Tags":           []interface{}{"a", "b"},
+	}}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("Expected %v, got %v", want, records)
+	}
+}
+
+func TestConnector_PassthroughFlatten(t *testing.T) {
+	server := jsonServer(accountsBody)
+	defer server.Close()
+
+	testCases := []struct {
+		name    string
+		flatten config.Flatten
+		want    []map[string]interface{}
+	}{
+		{
+			name:    "json arrays",
+			flatten: config.Flatten{Arrays: config.FlattenArraysJSON},
+			want: []map[string]interface{}{{
+				"Id": "001", "Name": "Acme",
+				"BillingAddress_City": "Berlin", "BillingAddress_Geo_Lat": 52.5,
+				"Tags": `["a","b"]`,
+			}},
+		},
+		{
+			name:    "index arrays with max depth",
+			flatten: config.Flatten{Separator: ".", MaxDepth: 1, Arrays: config.FlattenArraysIndex},
+			want: []map[string]interface{}{{
+				"Id": "001", "Name": "Acme",
+				"BillingAddress.City": "Berlin", "BillingAddress.Geo": map[string]interface{}{"Lat": 52.5},
+				"Tags.0": "a", "Tags.1": "b",
+			}},
+		},
+		{
+			name:    "exploded arrays",
+			flatten: config.Flatten{Arrays: config.FlattenArraysExplode},
+			want: []map[string]interface{}{
+				{"Id": "001", "Name": "Acme", "BillingAddress_City": "Berlin", "BillingAddress_Geo_Lat": 52.5, "Tags": "a"},
+				{"Id": "001", "Name": "Acme", "BillingAddress_City": "Berlin", "BillingAddress_Geo_Lat": 52.5, "Tags": "b"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			flatten := tc.flatten
+			records := extractAll(t, restConfig("passthrough-test", server.URL, config.ResponseMapping{
+				RootPath: "data",
+				Mode:     config.MappingModePassthrough,
+				Flatten:  &flatten,
+				Exclude:  []string{"Secret"},
+			}))
+			if !reflect.DeepEqual(records, tc.want) {
+				t.Errorf("Expected %v, got %v", tc.want, records)
+			}
+		})
+	}
+}
@@ -134,10 +134,14 @@ func TestConnector_RateLimit_429Response(t *testing.T) {
 					return
 				}
 
-				// Verify error type and message
-				// Rate limits during pagination are caught by the pager, so expect ErrPagination
-				if !errors2.Is(err, errors2.ErrPagination) {
-					t.Errorf("Expected ErrPagination, got error type: %T", err)
+				// Verify error type and message. A 429 is an HTTP response
+				// error like any other status, and is marked retryable.
+				if !errors2.Is(err, errors2.ErrHTTPResponse) || errors2.Is(err, errors2.ErrPagination) {
+					t.Errorf("Expected ErrHTTPResponse, got: %v", err)
+				}
+				var apiErr *errors2.APIError
+				if !errors2.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests || !errors2.IsRetryable(err) {
+					t.Errorf("Expected a retryable APIError with status 429, got: %v", err)
 				}
 
 				if !strings.Contains(err.Error(), tt.errorContains) {
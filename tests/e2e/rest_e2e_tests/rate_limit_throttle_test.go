@@ -0,0 +1,210 @@
+package rest_e2e_tests_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/core"
+)
+
+// newThrottleServer serves pages 1..pages and lets headers(page, w) add
+// rate limit headers or write an error status. It records request times.
+func newThrottleServer(pages int, headers func(page int, w http.ResponseWriter) bool) (*httptest.Server, func() []time.Time) {
+	var mu sync.Mutex
+	var times []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		times = append(times, time.Now())
+		mu.Unlock()
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if headers != nil && headers(page, w) {
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data":     []interface{}{map[string]interface{}{"id": page}},
+			"has_more": page < pages,
+		})
+	}))
+	return server, func() []time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]time.Time(nil), times...)
+	}
+}
+
+func extractCount(t *testing.T, cfg *config.Pipeline) int {
+	t.Helper()
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	results, err := connector.Extract(context.Background())
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	return len(results)
+}
+
+func TestConnector_RateLimit_TokenBucket(t *testing.T) {
+	server, times := newThrottleServer(6, nil)
+	defer server.Close()
+
+	start := time.Now()
+	cfg := restConfig("stream-test", server.URL, streamPageMapping)
+	pagination := streamPagePagination
+	pagination.PageSize = 1
+	cfg.Pagination = &pagination
+	cfg.RateLimit = &config.RateLimit{RequestsPerSecond: 20, Burst: 2}
+	n := extractCount(t, cfg)
+	elapsed := time.Since(start)
+
+	if n != 6 {
+		t.Errorf("Expected 6 records, got %d", n)
+	}
+	// Two requests go out immediately; the other four wait 50ms each.
+	if elapsed < 180*time.Millisecond {
+		t.Errorf("Expected throttling to take at least ~200ms, took %v", elapsed)
+	}
+	if ts := times(); ts[1].Sub(ts[0]) > 40*time.Millisecond {
+		t.Errorf("Expected burst of 2 to go out together, gap was %v", ts[1].Sub(ts[0]))
+	}
+}
+
+func TestConnector_RateLimit_AdaptsToHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers func(w http.ResponseWriter)
+	}{
+		{"ietf draft headers", func(w http.ResponseWriter) {
+			w.Header().Set("RateLimit-Remaining", "0")
+			w.Header().Set("RateLimit-Reset", "0.3")
+		}},
+		{"structured RateLimit header", func(w http.ResponseWriter) {
+			w.Header().Set("RateLimit", `"default";r=0;t=0.3`)
+		}},
+		{"x-ratelimit headers", func(w http.ResponseWriter) {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", "0.3")
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, times := newThrottleServer(2, func(page int, w http.ResponseWriter) bool {
+				if page == 1 {
+					tt.headers(w)
+				}
+				return false
+			})
+			defer server.Close()
+
+			cfg := restConfig("stream-test", server.URL, streamPageMapping)
+			pagination := streamPagePagination
+			pagination.PageSize = 1
+			cfg.Pagination = &pagination
+			cfg.RateLimit = &config.RateLimit{}
+			if n := extractCount(t, cfg); n != 2 {
+				t.Errorf("Expected 2 records, got %d", n)
+			}
+			ts := times()
+			if gap := ts[1].Sub(ts[0]); gap < 250*time.Millisecond {
+				t.Errorf("Expected to wait for the window to reset, gap was %v", gap)
+			}
+		})
+	}
+}
+
+func TestConnector_RateLimit_RetryAfterWithRetry(t *testing.T) {
+	var once sync.Once
+	server, times := newThrottleServer(2, func(page int, w http.ResponseWriter) bool {
+		throttled := false
+		if page == 2 {
+			once.Do(func() {
+				w.Header().Set("Retry-After", "60")
+				w.WriteHeader(http.StatusTooManyRequests)
+				throttled = true
+			})
+		}
+		return throttled
+	})
+	defer server.Close()
+
+	cfg := restConfig("stream-test", server.URL, streamPageMapping)
+	pagination := streamPagePagination
+	pagination.PageSize = 1
+	cfg.Pagination = &pagination
+	cfg.RateLimit = &config.RateLimit{MaxWait: 0.2}
+	cfg.RetryConfig = &config.RetryConfig{
+		MaxAttempts:       2,
+		InitialBackoff:    0.001,
+		BackoffMultiplier: 1,
+		RetryableStatuses: []int{http.StatusTooManyRequests},
+	}
+
+	if n := extractCount(t, cfg); n != 2 {
+		t.Errorf("Expected 2 records, got %d", n)
+	}
+	ts := times()
+	if len(ts) != 3 {
+		t.Fatalf("Expected 3 requests, got %d", len(ts))
+	}
+	gap := ts[2].Sub(ts[1])
+	if gap < 180*time.Millisecond || gap > 2*time.Second {
+		t.Errorf("Expected retry to wait for Retry-After capped by max_wait (~200ms), gap was %v", gap)
+	}
+}
+
+func TestConnector_RateLimit_IgnoreHeaders(t *testing.T) {
+	server, times := newThrottleServer(2, func(page int, w http.ResponseWriter) bool {
+		w.Header().Set("RateLimit-Remaining", "0")
+		w.Header().Set("RateLimit-Reset", "5")
+		return false
+	})
+	defer server.Close()
+
+	cfg := restConfig("stream-test", server.URL, streamPageMapping)
+	pagination := streamPagePagination
+	pagination.PageSize = 1
+	cfg.Pagination = &pagination
+	cfg.RateLimit = &config.RateLimit{IgnoreHeaders: true}
+	if n := extractCount(t, cfg); n != 2 {
+		t.Errorf("Expected 2 records, got %d", n)
+	}
+	if gap := times()[1].Sub(times()[0]); gap > time.Second {
+		t.Errorf("Expected headers to be ignored, gap was %v", gap)
+	}
+}
+
+func TestConnector_RateLimit_ContextCancelledWhileWaiting(t *testing.T) {
+	server, _ := newThrottleServer(5, nil)
+	defer server.Close()
+
+	cfg := restConfig("stream-test", server.URL, streamPageMapping)
+	pagination := streamPagePagination
+	pagination.PageSize = 1
+	cfg.Pagination = &pagination
+	cfg.RateLimit = &config.RateLimit{RequestsPerMinute: 1}
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := connector.Extract(ctx); err == nil {
+		t.Fatal("Expected an error when the context ends while throttled")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected cancellation to interrupt the wait, took %v", elapsed)
+	}
+}
@@ -0,0 +1,154 @@
+package rest_e2e_tests_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/core"
+)
+
+// newFlakyServer fails the first request with 502 and records the
+// Idempotency-Key header of every request.
+func newFlakyServer() (*httptest.Server, func() []string) {
+	var mu sync.Mutex
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		n := len(keys)
+		mu.Unlock()
+
+		if n == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items": [{"id": 1}]}`))
+	}))
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), keys...)
+	}
+}
+
+// flakyRetry retries the 502 newFlakyServer answers first. Tests copy it
+// and add the method options under test.
+var flakyRetry = config.RetryConfig{
+	MaxAttempts:       3,
+	InitialBackoff:    0.01,
+	BackoffMultiplier: 1,
+	RetryableStatuses: []int{502},
+}
+
+var flakyMapping = config.ResponseMapping{
+	RootPath: "items",
+	Fields:   []config.Field{{Name: "id", Path: "id"}},
+}
+
+func TestRetry_PostNotRetriedByDefault(t *testing.T) {
+	server, keys := newFlakyServer()
+	defer server.Close()
+
+	cfg := restConfig("retry-method-test", server.URL, flakyMapping)
+	cfg.Source.Method = http.MethodPost
+	retry := flakyRetry
+	cfg.RetryConfig = &retry
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	if _, err := connector.Extract(context.Background()); err == nil {
+		t.Fatal("Expected POST to fail without retry")
+	}
+	if n := len(keys()); n != 1 {
+		t.Errorf("Expected 1 request, got %d", n)
+	}
+}
+
+func TestRetry_PostRetriedWhen(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(r *config.RetryConfig)
+	}{
+		{"retry_non_idempotent", func(r *config.RetryConfig) { r.RetryNonIdempotent = true }},
+		{"retry_methods", func(r *config.RetryConfig) { r.RetryMethods = []string{"get", "post"} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, keys := newFlakyServer()
+			defer server.Close()
+
+			cfg := restConfig("retry-method-test", server.URL, flakyMapping)
+			cfg.Source.Method = http.MethodPost
+			retry := flakyRetry
+			tt.modify(&retry)
+			cfg.RetryConfig = &retry
+			connector, err := core.NewConnector(cfg)
+			if err != nil {
+				t.Fatalf("Failed to create connector: %v", err)
+			}
+			results, err := connector.Extract(context.Background())
+			if err != nil {
+				t.Fatalf("Extract failed: %v", err)
+			}
+			if len(results) != 1 {
+				t.Errorf("Expected 1 record, got %d", len(results))
+			}
+			if n := len(keys()); n != 2 {
+				t.Errorf("Expected 2 requests, got %d", n)
+			}
+		})
+	}
+}
+
+func TestRetry_MethodAllowListExcludesGet(t *testing.T) {
+	server, keys := newFlakyServer()
+	defer server.Close()
+
+	cfg := restConfig("retry-method-test", server.URL, flakyMapping)
+	retry := flakyRetry
+	retry.RetryMethods = []string{"POST"}
+	cfg.RetryConfig = &retry
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	if _, err := connector.Extract(context.Background()); err == nil {
+		t.Fatal("Expected GET to fail when not in retry_methods")
+	}
+	if n := len(keys()); n != 1 {
+		t.Errorf("Expected 1 request, got %d", n)
+	}
+}
+
+func TestRetry_IdempotencyKeyReusedAcrossAttempts(t *testing.T) {
+	server, keys := newFlakyServer()
+	defer server.Close()
+
+	cfg := restConfig("retry-method-test", server.URL, flakyMapping)
+	cfg.Source.Method = http.MethodPost
+	retry := flakyRetry
+	retry.IdempotencyKey = true
+	cfg.RetryConfig = &retry
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	if _, err := connector.Extract(context.Background()); err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	got := keys()
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(got))
+	}
+	if got[0] == "" || got[0] != got[1] {
+		t.Errorf("Expected the same non-empty Idempotency-Key on both attempts, got %q", got)
+	}
+}
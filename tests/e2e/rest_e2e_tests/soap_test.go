@@ -0,0 +1,335 @@
+package rest_e2e_tests_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/core"
+	errors2 "github.com/saturnines/nexus-core/pkg/errors"
+)
+
+const ordersEnvelope = `<?xml version="1.0" encoding="UTF-8"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"
+               xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+  <soap:Body>
+    <m:GetOrdersResponse xmlns:m="urn:erp:orders">
+      <m:Order id="1001" status="open">
+        <m:Customer><m:Name>Acme &amp; Co</m:Name></m:Customer>
+        <m:Total currency="EUR">99.50</m:Total>
+        <m:Lines><m:Line sku="A-1"/></m:Lines>
+        <m:Note xsi:nil="true"/>
+      </m:Order>
+      <m:Order id="1002" status="closed">
+        <m:Customer><m:Name>Globex</m:Name></m:Customer>
+        <m:Total currency="EUR">10</m:Total>
+        <m:Lines><m:Line sku="B-1"/><m:Line sku="B-2"/></m:Lines>
+        <m:Note>rush</m:Note>
+      </m:Order>
+    </m:GetOrdersResponse>
+  </soap:Body>
+</soap:Envelope>`
+
+const ordersRootPath = "Envelope.Body.GetOrdersResponse.Order"
+
+// getOrders is the SOAP request the tests send. Copy it before changing it.
+var getOrders = config.SOAPSource{
+	Action: "urn:erp:orders/GetOrders",
+	Header: `<auth:Token xmlns:auth="urn:auth">{{SOAP_TEST_TOKEN}}</auth:Token>`,
+	Body:   `<m:GetOrders xmlns:m="urn:erp:orders"><m:Since>2024-01-01</m:Since></m:GetOrders>`,
+}
+
+func TestConnector_SOAPRequestAndXMLMapping(t *testing.T) {
+	t.Setenv("SOAP_TEST_TOKEN", "s3cr<et>&")
+
+	var req *http.Request
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		req, body = r, string(b)
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		w.Write([]byte(ordersEnvelope))
+	}))
+	defer server.Close()
+
+	cfg := restConfig("soap-test", server.URL, config.ResponseMapping{
+		RootPath: ordersRootPath,
+		XML:      &config.XMLFormat{Arrays: []string{"Line"}},
+		Fields: []config.Field{
+			{Name: "id", Path: "@id", Type: "integer"},
+			{Name: "customer", Path: "Customer.Name"},
+			{Name: "total", Path: "Total.#text", Type: "float"},
+			{Name: "currency", Path: "Total.@currency"},
+			{Name: "skus", Path: "Lines.Line[*].@sku"},
+			{Name: "note", Path: "Note", OnNull: config.FieldPolicyNull},
+		},
+	})
+	soap := getOrders
+	cfg.Source.SOAP = &soap
+	records := extractAll(t, cfg)
+
+	if req.Method != http.MethodPost {
+		t.Errorf("Expected POST, got %s", req.Method)
+	}
+	if got := req.Header.Get("SOAPAction"); got != `"urn:erp:orders/GetOrders"` {
+		t.Errorf("Unexpected SOAPAction %q", got)
+	}
+	if got := req.Header.Get("Content-Type"); !strings.HasPrefix(got, "text/xml") {
+		t.Errorf("Unexpected Content-Type %q", got)
+	}
+	for _, want := range []string{
+		`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">`,
+		`<soap:Header><auth:Token xmlns:auth="urn:auth">s3cr&lt;et&gt;&amp;</auth:Token></soap:Header>`,
+		`<soap:Body><m:GetOrders xmlns:m="urn:erp:orders">`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Envelope %s does not contain %s", body, want)
+		}
+	}
+
+	want := []map[string]interface{}{
+		{"id": 1001, "customer": "Acme & Co", "total": 99.5, "currency": "EUR", "skus": []interface{}{"A-1"}, "note": nil},
+		{"id": 1002, "customer": "Globex", "total": 10.0, "currency": "EUR", "skus": []interface{}{"B-1", "B-2"}, "note": "rush"},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("Expected %v, got %v", want, records)
+	}
+}
+
+func TestConnector_SOAP12ContentType(t *testing.T) {
+	var contentType, action string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType, action = r.Header.Get("Content-Type"), r.Header.Get("SOAPAction")
+		w.Write([]byte(ordersEnvelope))
+	}))
+	defer server.Close()
+
+	cfg := restConfig("soap-test", server.URL, config.ResponseMapping{
+		RootPath: ordersRootPath,
+		Fields:   []config.Field{{Name: "id", Path: "@id"}},
+	})
+	soap := getOrders
+	soap.Version = config.SOAPVersion12
+	cfg.Source.SOAP = &soap
+	extractAll(t, cfg)
+
+	if contentType != `application/soap+xml; charset=utf-8; action="urn:erp:orders/GetOrders"` {
+		t.Errorf("Unexpected Content-Type %q", contentType)
+	}
+	if action != "" {
+		t.Errorf("Expected no SOAPAction header for SOAP 1.2, got %q", action)
+	}
+}
+
+func TestConnector_XMLNamespaces(t *testing.T) {
+	server := jsonServer(ordersEnvelope)
+	defer server.Close()
+
+	cfg := restConfig("soap-test", server.URL, config.ResponseMapping{
+		RootPath: "Envelope.Body.m:GetOrdersResponse.m:Order",
+		XML:      &config.XMLFormat{Namespaces: map[string]string{"m": "urn:erp:orders"}},
+		Fields:   []config.Field{{Name: "name", Path: "m:Customer.m:Name"}},
+	})
+	soap := getOrders
+	cfg.Source.SOAP = &soap
+
+	records := extractAll(t, cfg)
+	if len(records) != 2 || records[0]["name"] != "Acme & Co" {
+		t.Errorf("Expected names keyed by prefix, got %v", records)
+	}
+}
+
+func TestConnector_XMLRootPath(t *testing.T) {
+	t.Run("single element is one item", func(t *testing.T) {
+		server := jsonServer(`<orders><order id="1"/></orders>`)
+		defer server.Close()
+
+		cfg := restConfig("xml-test", server.URL, config.ResponseMapping{
+			ResponseFormat: config.ResponseFormatXML,
+			RootPath:       "orders.order",
+			Fields:         []config.Field{{Name: "id", Path: "@id"}},
+		})
+
+		records := extractAll(t, cfg)
+		if len(records) != 1 || records[0]["id"] != "1" {
+			t.Errorf("Expected one record, got %v", records)
+		}
+	})
+
+	t.Run("empty list", func(t *testing.T) {
+		server := jsonServer(`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><GetOrdersResponse/></soap:Body></soap:Envelope>`)
+		defer server.Close()
+
+		cfg := restConfig("soap-test", server.URL, config.ResponseMapping{
+			RootPath: ordersRootPath,
+			Fields:   []config.Field{{Name: "id", Path: "@id"}},
+		})
+		soap := getOrders
+		cfg.Source.SOAP = &soap
+		records := extractAll(t, cfg)
+		if len(records) != 0 {
+			t.Errorf("Expected no records, got %v", records)
+		}
+	})
+
+	t.Run("wrong root path", func(t *testing.T) {
+		server := jsonServer(ordersEnvelope)
+		defer server.Close()
+
+		cfg := restConfig("soap-test", server.URL, config.ResponseMapping{
+			RootPath: "Envelope.Body.GetOrdersResult.Order",
+			Fields:   []config.Field{{Name: "id", Path: "@id"}},
+		})
+		soap := getOrders
+		cfg.Source.SOAP = &soap
+		connector, err := core.NewConnector(cfg)
+		if err != nil {
+			t.Fatalf("Failed to create connector: %v", err)
+		}
+		if _, err := connector.Extract(context.Background()); !errors2.Is(err, errors2.ErrExtraction) {
+			t.Errorf("Expected ErrExtraction, got %v", err)
+		}
+	})
+
+	t.Run("malformed document", func(t *testing.T) {
+		server := jsonServer(`<orders><order id="1"></orders>`)
+		defer server.Close()
+
+		cfg := restConfig("soap-test", server.URL, config.ResponseMapping{
+			RootPath: ordersRootPath,
+			Fields:   []config.Field{{Name: "id", Path: "@id"}},
+		})
+		soap := getOrders
+		cfg.Source.SOAP = &soap
+		connector, err := core.NewConnector(cfg)
+		if err != nil {
+			t.Fatalf("Failed to create connector: %v", err)
+		}
+		if _, err := connector.Extract(context.Background()); !errors2.Is(err, errors2.ErrHTTPResponse) {
+			t.Errorf("Expected ErrHTTPResponse, got %v", err)
+		}
+	})
+}
+
+func TestConnector_SOAPPagination(t *testing.T) {
+	var tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if b, _ := io.ReadAll(r.Body); !strings.Contains(string(b), "<m:GetOrders") {
+			t.Errorf("Request %d was sent without its envelope", len(tokens)+1)
+		}
+		token := r.URL.Query().Get("token")
+		tokens = append(tokens, token)
+
+		next := ""
+		if token == "" {
+			next = "<NextToken>p2</NextToken>"
+		}
+		w.Write([]byte(`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body>
+			<GetOrdersResponse><Order id="` + token + `"/>` + next + `</GetOrdersResponse>
+		</soap:Body></soap:Envelope>`))
+	}))
+	defer server.Close()
+
+	cfg := restConfig("soap-test", server.URL, config.ResponseMapping{
+		RootPath: ordersRootPath,
+		Fields:   []config.Field{{Name: "id", Path: "@id"}},
+	})
+	soap := getOrders
+	cfg.Source.SOAP = &soap
+	cfg.Pagination = &config.Pagination{
+		Type:        config.PaginationTypeCursor,
+		CursorParam: "token",
+		CursorPath:  "Envelope.Body.GetOrdersResponse.NextToken",
+	}
+	// The retry transport copies each request's body before sending it.
+	cfg.RetryConfig = &config.RetryConfig{MaxAttempts: 2, RetryMethods: []string{"POST"}}
+
+	records := extractAll(t, cfg)
+	if len(records) != 2 || !reflect.DeepEqual(tokens, []string{"", "p2"}) {
+		t.Errorf("Expected two pages, got records %v and tokens %v", records, tokens)
+	}
+}
+
+func TestConnector_SOAPFaultErrorPath(t *testing.T) {
+	server := jsonServer(`<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body>
+		<soap:Fault><faultcode>soap:Client</faultcode><faultstring>Invalid date</faultstring></soap:Fault>
+	</soap:Body></soap:Envelope>`)
+	defer server.Close()
+
+	cfg := restConfig("soap-test", server.URL, config.ResponseMapping{
+		RootPath:  ordersRootPath,
+		ErrorPath: "Envelope.Body.Fault.faultstring",
+		Fields:    []config.Field{{Name: "id", Path: "@id"}},
+	})
+	soap := getOrders
+	cfg.Source.SOAP = &soap
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	_, err = connector.Extract(context.Background())
+	var apiErr *errors2.APIError
+	if !errors2.As(err, &apiErr) || apiErr.Message != "Invalid date" {
+		t.Errorf("Expected an APIError with the fault string, got %v", err)
+	}
+}
+
+// xmlNameMapping reads the name attribute of each order in <orders>.
+var xmlNameMapping = config.ResponseMapping{
+	ResponseFormat: config.ResponseFormatXML,
+	RootPath:       "orders.order",
+	Fields:         []config.Field{{Name: "name", Path: "@name"}},
+}
+
+func TestConnector_XMLPaginationFlags(t *testing.T) {
+	testCases := []struct {
+		name       string
+		pagination *config.Pagination
+		meta       func(page, last int) string
+	}{
+		{
+			name:       "has_more text",
+			pagination: &config.Pagination{HasMorePath: "orders.HasMore"},
+			meta: func(page, last int) string {
+				return fmt.Sprintf("<HasMore>%t</HasMore>", page < last)
+			},
+		},
+		{
+			name:       "total_pages text",
+			pagination: &config.Pagination{TotalPagesPath: "orders.TotalPages"},
+			meta: func(_, last int) string {
+				return fmt.Sprintf("<TotalPages> %d </TotalPages>", last)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+				fmt.Fprintf(w, `<orders>%s<order name="p%d"/></orders>`, tc.meta(page, 3), page)
+			}))
+			defer server.Close()
+
+			cfg := restConfig("xml-test", server.URL, xmlNameMapping)
+			cfg.Pagination = tc.pagination
+			cfg.Pagination.Type = config.PaginationTypePage
+			cfg.Pagination.PageParam = "page"
+
+			records := extractAll(t, cfg)
+			if len(records) != 3 || requests != 3 {
+				t.Errorf("Expected 3 pages, got %d records from %d requests", len(records), requests)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package rest_e2e_tests_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/core"
+	errors2 "github.com/saturnines/nexus-core/pkg/errors"
+)
+
+// newStreamPageServer serves totalPages pages of pageSize items using page/size params.
+func newStreamPageServer(t *testing.T, totalPages, pageSize int, requests *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page < 1 {
+			page = 1
+		}
+
+		var items []interface{}
+		for i := 0; i < pageSize; i++ {
+			id := (page-1)*pageSize + i + 1
+			items = append(items, map[string]interface{}{
+				"id":   id,
+				"name": fmt.Sprintf("Item %d", id),
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data":     items,
+			"has_more": page < totalPages,
+		})
+	}))
+}
+
+// streamPageMapping and streamPagePagination read newStreamPageServer.
+// Tests copy streamPagePagination before changing it.
+var streamPageMapping = config.ResponseMapping{
+	RootPath: "data",
+	Fields: []config.Field{
+		{Name: "id", Path: "id"},
+		{Name: "name", Path: "name"},
+	},
+}
+
+var streamPagePagination = config.Pagination{
+	Type:        config.PaginationTypePage,
+	PageParam:   "page",
+	SizeParam:   "size",
+	PageSize:    3,
+	HasMorePath: "has_more",
+}
+
+func TestConnector_Stream_YieldsAllRecordsInOrder(t *testing.T) {
+	var requests int32
+	server := newStreamPageServer(t, 4, 3, &requests)
+	defer server.Close()
+
+	cfg := restConfig("stream-test", server.URL, streamPageMapping)
+	pagination := streamPagePagination
+	cfg.Pagination = &pagination
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+
+	count := 0
+	for record, err := range connector.Stream(context.Background()) {
+		if err != nil {
+			t.Fatalf("Stream failed: %v", err)
+		}
+		count++
+		if record["id"] != float64(count) {
+			t.Errorf("Record %d: expected id %d, got %v", count, count, record["id"])
+		}
+	}
+
+	if count != 12 {
+		t.Errorf("Expected 12 records, got %d", count)
+	}
+	if requests != 4 {
+		t.Errorf("Expected 4 requests, got %d", requests)
+	}
+}
+
+func TestConnector_Stream_BreakStopsPagination(t *testing.T) {
+	var requests int32
+	server := newStreamPageServer(t, 100, 3, &requests)
+	defer server.Close()
+
+	cfg := restConfig("stream-test", server.URL, streamPageMapping)
+	pagination := streamPagePagination
+	cfg.Pagination = &pagination
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+
+	count := 0
+	for _, err := range connector.Stream(context.Background()) {
+		if err != nil {
+			t.Fatalf("Stream failed: %v", err)
+		}
+		count++
+		if count == 4 {
+			break
+		}
+	}
+
+	// The fourth record lives on page two, so exactly two pages are fetched.
+	if requests != 2 {
+		t.Errorf("Expected 2 requests after breaking early, got %d", requests)
+	}
+}
+
+func TestConnector_Stream_ErrorIsYieldedLast(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&requests, 1)
+		if n == 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data":     []interface{}{map[string]interface{}{"id": n, "name": "x"}},
+			"has_more": true,
+		})
+	}))
+	defer server.Close()
+
+	cfg := restConfig("stream-test", server.URL, streamPageMapping)
+	pagination := streamPagePagination
+	cfg.Pagination = &pagination
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+
+	var records int
+	var lastErr error
+	for record, err := range connector.Stream(context.Background()) {
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if lastErr != nil {
+			t.Fatalf("Record yielded after error: %v", record)
+		}
+		records++
+	}
+
+	if records != 1 {
+		t.Errorf("Expected 1 record before the failure, got %d", records)
+	}
+	if !errors2.Is(lastErr, errors2.ErrHTTPResponse) {
+		t.Errorf("Expected ErrHTTPResponse, got %v", lastErr)
+	}
+}
+
+func TestConnector_Stream_GraphQLCursor(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		var body struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		cursor, _ := body.Variables["after"].(string)
+
+		page := 1
+		if cursor != "" {
+			page, _ = strconv.Atoi(cursor)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"items": map[string]interface{}{
+					"nodes": []interface{}{
+						map[string]interface{}{"id": fmt.Sprintf("g%d", page)},
+					},
+					"pageInfo": map[string]interface{}{
+						"endCursor":   strconv.Itoa(page + 1),
+						"hasNextPage": page < 3,
+					},
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	cfg := &config.Pipeline{
+		Name: "stream-graphql-test",
+		Source: config.Source{
+			Type: config.SourceTypeGraphQL,
+			GraphQLConfig: &config.GraphQLSource{
+				Endpoint: server.URL,
+				Query:    `query($after: String) { items(after: $after) { nodes { id } pageInfo { endCursor hasNextPage } } }`,
+				ResponseMapping: config.ResponseMapping{
+					RootPath: "items.nodes",
+					Fields:   []config.Field{{Name: "id", Path: "id"}},
+				},
+			},
+		},
+		Pagination: &config.Pagination{
+			Type:        config.PaginationTypeCursor,
+			CursorParam: "after",
+			CursorPath:  "data.items.pageInfo.endCursor",
+			HasMorePath: "data.items.pageInfo.hasNextPage",
+		},
+	}
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+
+	var ids []interface{}
+	for record, err := range connector.Stream(context.Background()) {
+		if err != nil {
+			t.Fatalf("Stream failed: %v", err)
+		}
+		ids = append(ids, record["id"])
+	}
+
+	expected := []interface{}{"g1", "g2", "g3"}
+	if fmt.Sprint(ids) != fmt.Sprint(expected) {
+		t.Errorf("Expected ids %v, got %v", expected, ids)
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 requests, got %d", requests)
+	}
+}
@@ -0,0 +1,302 @@
+package rest_e2e_tests_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/core"
+	errors2 "github.com/saturnines/nexus-core/pkg/errors"
+)
+
+// exportBody builds {"meta": ..., "result": {"items": [...]}, "has_more": ...}
+// with n items whose ids start at first.
+func exportBody(first, n int, hasMore bool) string {
+	var b strings.Builder
+	b.WriteString(`{"meta": {"count": ` + fmt.Sprint(n) + `}, "result": {"items": [`)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `{"id": %d, "name": "item-%d"}`, first+i, first+i)
+	}
+	fmt.Fprintf(&b, `]}, "has_more": %t}`, hasMore)
+	return b.String()
+}
+
+var streamingFields = []config.Field{
+	{Name: "id", Path: "id", Type: "integer"},
+	{Name: "name", Path: "name"},
+}
+
+func TestConnector_StreamingPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "1" {
+			w.Write([]byte(exportBody(0, 1200, true)))
+			return
+		}
+		w.Write([]byte(exportBody(1200, 3, false)))
+	}))
+	defer server.Close()
+
+	cfg := restConfig("streaming-test", server.URL, config.ResponseMapping{
+		RootPath:  "result.items",
+		Streaming: true,
+		Fields:    streamingFields,
+	})
+	cfg.Pagination = &config.Pagination{
+		Type:        config.PaginationTypePage,
+		PageParam:   "page",
+		SizeParam:   "size",
+		PageSize:    1200,
+		HasMorePath: "has_more",
+	}
+
+	records := extractAll(t, cfg)
+	if len(records) != 1203 {
+		t.Fatalf("Expected 1203 records, got %d", len(records))
+	}
+	for i, r := range records {
+		if r["id"] != i {
+			t.Fatalf("Record %d out of order: %v", i, r)
+		}
+	}
+}
+
+func TestConnector_StreamingCursorFromLastItem(t *testing.T) {
+	var cursors []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		after := r.URL.Query().Get("starting_after")
+		cursors = append(cursors, after)
+		w.Header().Set("Content-Type", "application/json")
+		switch after {
+		case "":
+			w.Write([]byte(`{"data": [{"id": "a"}, {"id": "b"}]}`))
+		case "b":
+			w.Write([]byte(`{"data": [{"id": "c"}]}`))
+		default:
+			w.Write([]byte(`{"data": []}`))
+		}
+	}))
+	defer server.Close()
+
+	cfg := restConfig("streaming-test", server.URL, config.ResponseMapping{
+		Streaming: true,
+		Fields:    []config.Field{{Name: "id", Path: "id"}},
+	})
+	cfg.Pagination = &config.Pagination{
+		Type:        config.PaginationTypeCursor,
+		CursorParam: "starting_after",
+		CursorPath:  "data[-1].id",
+	}
+
+	records := extractAll(t, cfg)
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 records, got %d", len(records))
+	}
+	if len(cursors) != 3 || cursors[1] != "b" || cursors[2] != "c" {
+		t.Errorf("Expected cursors taken from the last item, got %v", cursors)
+	}
+}
+
+func TestConnector_StreamingTopLevelArray(t *testing.T) {
+	server := jsonServer(`[{"id": 1234567890123456789, "name": "big"}]`)
+	defer server.Close()
+
+	cfg := restConfig("streaming-test", server.URL, config.ResponseMapping{
+		Streaming:  true,
+		NumberMode: config.NumberModeExact,
+		Fields:     []config.Field{{Name: "id", Path: "id"}},
+	})
+
+	records := extractAll(t, cfg)
+	if len(records) != 1 || records[0]["id"] != json.Number("1234567890123456789") {
+		t.Errorf("Expected the exact id, got %v", records)
+	}
+}
+
+func TestConnector_StreamingStopsEarly(t *testing.T) {
+	server := jsonServer(exportBody(0, 2000, false))
+	defer server.Close()
+
+	cfg := restConfig("streaming-test", server.URL, config.ResponseMapping{
+		RootPath:  "result.items",
+		Streaming: true,
+		Fields:    streamingFields,
+	})
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create connector: %v", err)
+	}
+	n := 0
+	for _, err := range connector.Stream(context.Background()) {
+		if err != nil {
+			t.Fatalf("Stream failed: %v", err)
+		}
+		if n++; n == 10 {
+			break
+		}
+	}
+	if n != 10 {
+		t.Errorf("Expected to stop after 10 records, got %d", n)
+	}
+}
+
+func TestConnector_StreamingErrors(t *testing.T) {
+	t.Run("root path is not an array", func(t *testing.T) {
+		server := jsonServer(`{"result": {"items": {"id": 1}}}`)
+		defer server.Close()
+
+		cfg := restConfig("streaming-test", server.URL, config.ResponseMapping{
+			RootPath:  "result.items",
+			Streaming: true,
+			Fields:    streamingFields,
+		})
+		connector, err := core.NewConnector(cfg)
+		if err != nil {
+			t.Fatalf("Failed to create connector: %v", err)
+		}
+		if _, err := connector.Extract(context.Background()); !errors2.Is(err, errors2.ErrExtraction) {
+			t.Errorf("Expected ErrExtraction, got %v", err)
+		}
+	})
+
+	t.Run("truncated body", func(t *testing.T) {
+		server := jsonServer(`{"result": {"items": [{"id": 1}, {"id": 2}`)
+		defer server.Close()
+
+		cfg := restConfig("streaming-test", server.URL, config.ResponseMapping{
+			RootPath:  "result.items",
+			Streaming: true,
+			Fields:    streamingFields,
+		})
+		connector, err := core.NewConnector(cfg)
+		if err != nil {
+			t.Fatalf("Failed to create connector: %v", err)
+		}
+		records, err := connector.Extract(context.Background())
+		if !errors2.Is(err, errors2.ErrHTTPResponse) {
+			t.Errorf("Expected ErrHTTPResponse, got %v", err)
+		}
+		if len(records) != 0 {
+			t.Errorf("Expected no records from an unfinished batch, got %d", len(records))
+		}
+	})
+
+	t.Run("root path with wildcard", func(t *testing.T) {
+		cfg := restConfig("streaming-test", "http://localhost", config.ResponseMapping{
+			RootPath:  "result.items[*]",
+			Streaming: true,
+			Fields:    streamingFields,
+		})
+		_, err := core.NewConnector(cfg)
+		if !errors2.Is(err, errors2.ErrConfiguration) {
+			t.Errorf("Expected ErrConfiguration, got %v", err)
+		}
+	})
+}
+
+func TestConnector_StreamingAPIErrors(t *testing.T) {
+	// errorBody reports an error ahead of a full batch of items.
+	errorBody := func(code string) string {
+		body := exportBody(0, 600, false)
+		return `{"ok": false, "error": "` + code + `",` + body[1:]
+	}
+	apiErrorConfig := func(url string) *config.Pipeline {
+		cfg := restConfig("streaming-test", url, config.ResponseMapping{
+			RootPath:  "result.items",
+			Streaming: true,
+			Fields:    streamingFields,
+		})
+		cfg.Source.ResponseMapping.SuccessPath = "ok"
+		cfg.Source.ResponseMapping.ErrorPath = "error"
+		return cfg
+	}
+
+	t.Run("error before the items yields no records", func(t *testing.T) {
+		server := jsonServer(errorBody("invalid_auth"))
+		defer server.Close()
+
+		connector, err := core.NewConnector(apiErrorConfig(server.URL))
+		if err != nil {
+			t.Fatalf("Failed to create connector: %v", err)
+		}
+		n := 0
+		var streamErr error
+		for _, err := range connector.Stream(context.Background()) {
+			if err != nil {
+				streamErr = err
+				break
+			}
+			n++
+		}
+		var apiErr *errors2.APIError
+		if !errors2.As(streamErr, &apiErr) || apiErr.Code != "invalid_auth" {
+			t.Fatalf("Expected an APIError for invalid_auth, got %v", streamErr)
+		}
+		if n != 0 {
+			t.Errorf("Expected no records before the error, got %d", n)
+		}
+	})
+
+	t.Run("error nested beside the items", func(t *testing.T) {
+		server := jsonServer(`{"result": {"error": "gone", "items": [{"id": 1}]}}`)
+		defer server.Close()
+
+		cfg := restConfig("streaming-test", server.URL, config.ResponseMapping{
+			RootPath:  "result.items",
+			Streaming: true,
+			Fields:    streamingFields,
+		})
+		cfg.Source.ResponseMapping.ErrorPath = "result.error"
+		connector, err := core.NewConnector(cfg)
+		if err != nil {
+			t.Fatalf("Failed to create connector: %v", err)
+		}
+		records, err := connector.Extract(context.Background())
+		if !errors2.Is(err, errors2.ErrAPI) || len(records) != 0 {
+			t.Errorf("Expected ErrAPI and no records, got %d records and %v", len(records), err)
+		}
+	})
+
+	t.Run("success member after the items decides", func(t *testing.T) {
+		server := jsonServer(`{"error": "stale warning", "result": {"items": [{"id": 1}]}, "ok": true}`)
+		defer server.Close()
+
+		if records := extractAll(t, apiErrorConfig(server.URL)); len(records) != 1 {
+			t.Errorf("Expected 1 record, got %d", len(records))
+		}
+	})
+
+	t.Run("retryable error is retried", func(t *testing.T) {
+		calls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Header().Set("Content-Type", "application/json")
+			if calls == 1 {
+				w.Write([]byte(errorBody("ratelimited")))
+				return
+			}
+			w.Write([]byte(exportBody(0, 3, false)))
+		}))
+		defer server.Close()
+
+		cfg := apiErrorConfig(server.URL)
+		cfg.RetryConfig = &config.RetryConfig{
+			MaxAttempts:         2,
+			InitialBackoff:      0.001,
+			BackoffMultiplier:   1,
+			RetryableErrorCodes: []string{"ratelimited"},
+		}
+		records := extractAll(t, cfg)
+		if len(records) != 3 || calls != 2 {
+			t.Errorf("Expected 3 records from the second request, got %d after %d requests", len(records), calls)
+		}
+	})
+}
@@ -0,0 +1,249 @@
+package jsonpath_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/saturnines/nexus-core/pkg/errors"
+	"github.com/saturnines/nexus-core/pkg/jsonpath"
+)
+
+const doc = `{
+	"user": {"name": "Ada", "first name": "Ada L."},
+	"x.y": "dotted",
+	"items": [
+		{"id": 1, "type": "primary", "value": "a", "price": 5, "tags": ["x"]},
+		{"id": 2, "type": "secondary", "value": "b", "price": 15},
+		{"id": 3, "type": "primary", "value": "c", "price": 25, "tags": []}
+	],
+	"data": [{"id": "first"}, {"id": "last"}],
+	"meta": {"next": {"id": 99}}
+}`
+
+func decode(t *testing.T) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(doc), &v); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return v
+}
+
+func TestGet(t *testing.T) {
+	data := decode(t)
+
+	tests := []struct {
+		name string
+		path string
+		want interface{}
+	}{
+		// Existing dotted syntax
+		{"nested field", "user.name", "Ada"},
+		{"index", "items[0].id", 1.0},
+		{"negative index", "items[-1].value", "c"},
+		{"dotted negative index", "data.-1.id", "last"},
+		{"wildcard", "items[*].id", []interface{}{1.0, 2.0, 3.0}},
+		{"leading bracket", "[0]", nil},
+
+		// JSONPath additions
+		{"root", "$.user.name", "Ada"},
+		{"quoted key with dot", "['x.y']", "dotted"},
+		{"quoted key with space", `user["first name"]`, "Ada L."},
+		{"filter", "items[?(@.type=='primary')].value", []interface{}{"a", "c"}},
+		{"filter without parens", "items[?@.price > 10].id", []interface{}{2.0, 3.0}},
+		{"filter and", "items[?(@.price > 10 && @.type == 'primary')].id", []interface{}{3.0}},
+		{"filter or", "items[?(@.id == 1 || @.id == 3)].value", []interface{}{"a", "c"}},
+		{"filter not", "items[?(!@.tags)].id", []interface{}{2.0}},
+		{"filter exists", "items[?(@.tags)].id", []interface{}{1.0, 3.0}},
+		{"filter regex", "items[?(@.type =~ /^PRI/i)].id", []interface{}{1.0, 3.0}},
+		{"filter root", "items[?(@.id == $.meta.next.id)].id", nil},
+		{"descent", "$..next.id", []interface{}{99.0}},
+		{"slice", "items[0:2].id", []interface{}{1.0, 2.0}},
+		{"slice from end", "items[-2:].id", []interface{}{2.0, 3.0}},
+		{"slice step", "items[::2].id", []interface{}{1.0, 3.0}},
+		{"union indexes", "items[0,2].value", []interface{}{"a", "c"}},
+		{"union names", "user['name','first name']", []interface{}{"Ada", "Ada L."}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := jsonpath.Compile(tt.path)
+			if err != nil {
+				t.Fatalf("compile %q: %v", tt.path, err)
+			}
+			got, ok := p.Get(data)
+			if tt.want == nil {
+				if ok {
+					t.Fatalf("expected no match, got %v", got)
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("expected a match for %q", tt.path)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescentFindsAllMatches(t *testing.T) {
+	got := jsonpath.MustCompile("..id").Find(decode(t))
+	if len(got) != 6 {
+		t.Fatalf("expected 6 ids, got %d: %v", len(got), got)
+	}
+}
+
+// TestLegacyResults pins the results the dotted syntax had before JSONPath
+// support, which existing field and root paths rely on.
+func TestLegacyResults(t *testing.T) {
+	var data interface{}
+	json.Unmarshal([]byte(`{
+		"orders": [{"tags": ["a", "b"]}, {"tags": ["c"]}, {"id": 3}],
+		"empty": []
+	}`), &data)
+
+	tests := []struct {
+		path  string
+		want  interface{}
+		found bool
+	}{
+		{"orders[*].tags", []interface{}{"a", "b", "c"}, true},
+		{"orders[*].tags[*]", []interface{}{"a", "b", "c"}, true},
+		{"orders[*].tags[0]", []interface{}{"a", "c"}, true},
+		{"empty[*]", []interface{}{}, true},
+		{"empty[*].id", nil, false},
+		{"orders[*].missing", nil, false},
+		// JSONPath syntax keeps one result per match.
+		{"$.orders[*].tags", []interface{}{[]interface{}{"a", "b"}, []interface{}{"c"}}, true},
+		{"$.empty[*]", nil, false},
+	}
+	for _, tt := range tests {
+		got, ok := jsonpath.MustCompile(tt.path).Get(data)
+		if ok != tt.found || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Get(%q) = %#v, %v, want %#v, %v", tt.path, got, ok, tt.want, tt.found)
+		}
+	}
+
+	for path, want := range map[string]bool{
+		"orders[*].tags": true,
+		"data.-1.id":     true,
+		"[0]":            true,
+		"$.orders":       false,
+		"orders['tags']": false,
+		"orders[0:1]":    false,
+		"..tags":         false,
+	} {
+		if got := jsonpath.MustCompile(path).Legacy(); got != want {
+			t.Errorf("Legacy(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestDefinite(t *testing.T) {
+	for path, want := range map[string]bool{
+		"user.name":         true,
+		"$.items[0]['id']":  true,
+		"items[*]":          false,
+		"items[0,1]":        false,
+		"..id":              false,
+		"items[?(@.id)].id": false,
+	} {
+		if got := jsonpath.MustCompile(path).Definite(); got != want {
+			t.Errorf("Definite(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestNames(t *testing.T) {
+	for path, want := range map[string][]string{
+		"result.items":      {"result", "items"},
+		"$['page.info'].id": {"page.info", "id"},
+		"$":                 {},
+		"data.0":            nil,
+		"items[0]":          nil,
+		"items[*]":          nil,
+		"..id":              nil,
+	} {
+		got, ok := jsonpath.MustCompile(path).Names()
+		if ok != (want != nil) || !reflect.DeepEqual(got, want) && want != nil {
+			t.Errorf("Names(%q) = %v, %v, want %v", path, got, ok, want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	for _, path := range []string{
+		"",
+		"items[",
+		"items[0",
+		"items.",
+		"user['name]",
+		"items[?(@.id ==)]",
+		"items[?(@.type =~ /(/)]",
+		"items[?(@.id == 1]",
+		"items[abc]",
+		"items[1:2:3:4]",
+	} {
+		t.Run(path, func(t *testing.T) {
+			_, err := jsonpath.Compile(path)
+			if err == nil {
+				t.Fatalf("expected error for %q", path)
+			}
+			if !errors.Is(err, errors.ErrConfiguration) {
+				t.Errorf("expected configuration error, got %v", err)
+			}
+			var syn *jsonpath.SyntaxError
+			if !errors.As(err, &syn) {
+				t.Fatalf("expected *SyntaxError, got %T", err)
+			}
+			if syn.Path != path {
+				t.Errorf("SyntaxError.Path = %q, want %q", syn.Path, path)
+			}
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	data := decode(t)
+
+	if v, err := jsonpath.MustCompile("items[?(@.price > 10)].id").Lookup(data); err != nil || v != 2.0 {
+		t.Errorf("Lookup returned %v, %v; want first match 2", v, err)
+	}
+	if n, err := jsonpath.MustCompile("meta.next['id']").LookupInt(data); err != nil || n != 99 {
+		t.Errorf("LookupInt returned %d, %v", n, err)
+	}
+	if s, err := jsonpath.MustCompile("data[-1].id").LookupString(data); err != nil || s != "last" {
+		t.Errorf("LookupString returned %q, %v", s, err)
+	}
+
+	_, err := jsonpath.MustCompile("meta.missing").Lookup(data)
+	if !errors.Is(err, errors.ErrExtraction) {
+		t.Errorf("Expected ErrExtraction for a missing path, got %v", err)
+	}
+	_, err = jsonpath.MustCompile("user.name").LookupBool(data)
+	if !errors.Is(err, errors.ErrExtraction) {
+		t.Errorf("Expected ErrExtraction for a type mismatch, got %v", err)
+	}
+
+	// JSON pagers read typed values only; "false" is not a bool.
+	text := map[string]interface{}{"more": "false", "pages": "3"}
+	if _, err := jsonpath.MustCompile("more").LookupBool(text); !errors.Is(err, errors.ErrExtraction) {
+		t.Errorf("Expected LookupBool to reject a string, got %v", err)
+	}
+	if _, err := jsonpath.MustCompile("pages").LookupInt(text); !errors.Is(err, errors.ErrExtraction) {
+		t.Errorf("Expected LookupInt to reject a string, got %v", err)
+	}
+}
+
+func TestKeys(t *testing.T) {
+	p := jsonpath.Keys("x.y")
+	if v, ok := p.Get(decode(t)); !ok || v != "dotted" {
+		t.Errorf("Keys(%q) got %v, %v", "x.y", v, ok)
+	}
+	if _, err := jsonpath.Compile(p.String()); err != nil {
+		t.Errorf("Keys produced an expression that does not compile: %v", err)
+	}
+}
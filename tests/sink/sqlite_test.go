@@ -0,0 +1,198 @@
+package sink_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/saturnines/nexus-core/pkg/config"
+	"github.com/saturnines/nexus-core/pkg/core"
+	"github.com/saturnines/nexus-core/pkg/errors"
+	"github.com/saturnines/nexus-core/pkg/sink"
+	_ "github.com/saturnines/nexus-core/pkg/sink/sqlite"
+)
+
+func testDestination(dsn string) *config.Destination {
+	return &config.Destination{
+		Type:  config.DestinationSQLite,
+		DSN:   dsn,
+		Table: "customers",
+		Schema: []config.Schema{
+			{Name: "id", Type: "string", Source: "id", PrimaryKey: true},
+			{Name: "email", Type: "string", Source: "email", Unique: true},
+			{Name: "balance", Type: "integer", Source: "balance", Index: true},
+			{Name: "tags", Type: "json", Source: "tags"},
+		},
+	}
+}
+
+func TestSink_RegistryHasSQLite(t *testing.T) {
+	kinds := sink.DefaultRegistry.GetAvailableSinks()
+	found := false
+	for _, k := range kinds {
+		if k == string(config.DestinationSQLite) {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("sqlite sink not registered, got %v", kinds)
+	}
+
+	_, err := sink.Create(&config.Destination{Type: "nope"})
+	if !errors.Is(err, errors.ErrConfiguration) {
+		t.Errorf("expected ErrConfiguration for unknown sink, got %v", err)
+	}
+
+	err = sink.DefaultRegistry.Register(config.DestinationSQLite, nil)
+	if !errors.Is(err, errors.ErrConfiguration) {
+		t.Errorf("expected duplicate registration to fail, got %v", err)
+	}
+}
+
+func TestSQLSink_DDL(t *testing.T) {
+	s, err := sink.Create(testDestination(":memory:"))
+	if err != nil {
+		t.Fatalf("create sink: %v", err)
+	}
+	defer s.Close()
+
+	ddl := s.(*sink.SQLSink).DDL()
+	if len(ddl) != 2 {
+		t.Fatalf("expected table and one index statement, got %v", ddl)
+	}
+	for _, want := range []string{`"id" TEXT NOT NULL`, `"email" TEXT UNIQUE`, `"balance" INTEGER`, `PRIMARY KEY ("id")`} {
+		if !strings.Contains(ddl[0], want) {
+			t.Errorf("expected DDL to contain %q, got %s", want, ddl[0])
+		}
+	}
+	if !strings.Contains(ddl[1], `CREATE INDEX IF NOT EXISTS "idx_customers_balance"`) {
+		t.Errorf("unexpected index statement: %s", ddl[1])
+	}
+}
+
+func TestSQLSink_UpsertOnPrimaryKey(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "out.db")
+	s, err := sink.Create(testDestination(dsn))
+	if err != nil {
+		t.Fatalf("create sink: %v", err)
+	}
+	defer s.Close()
+
+	ctx := context.Background()
+	if err := s.Init(ctx); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+
+	first := []map[string]interface{}{
+		{"id": "cus_1", "email": "a@example.com", "balance": 10, "tags": []interface{}{"x"}},
+		{"id": "cus_2", "email": "b@example.com", "balance": 20},
+	}
+	if err := s.Write(ctx, first); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	second := []map[string]interface{}{
+		{"id": "cus_1", "email": "a@example.com", "balance": 99},
+	}
+	if err := s.Write(ctx, second); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer db.Close()
+
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM customers`).Scan(&count); err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 rows after upsert, got %d", count)
+	}
+
+	var balance int
+	var tags sql.NullString
+	if err := db.QueryRow(`SELECT balance, tags FROM customers WHERE id = 'cus_1'`).Scan(&balance, &tags); err != nil {
+		t.Fatalf("select: %v", err)
+	}
+	if balance != 99 {
+		t.Errorf("expected upserted balance 99, got %d", balance)
+	}
+	if tags.Valid {
+		t.Errorf("expected tags to be overwritten with NULL, got %q", tags.String)
+	}
+}
+
+func TestSQLSink_MissingPrimaryKey(t *testing.T) {
+	s, err := sink.Create(testDestination(":memory:"))
+	if err != nil {
+		t.Fatalf("create sink: %v", err)
+	}
+	defer s.Close()
+
+	ctx := context.Background()
+	if err := s.Init(ctx); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	err = s.Write(ctx, []map[string]interface{}{{"email": "no-id@example.com"}})
+	if !errors.Is(err, errors.ErrValidation) {
+		t.Errorf("expected ErrValidation, got %v", err)
+	}
+}
+
+func TestSQLSink_LoadFromConnector(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": []interface{}{
+				map[string]interface{}{"id": "cus_1", "email": "a@example.com", "balance": 1},
+				map[string]interface{}{"id": "cus_2", "email": "b@example.com", "balance": 2},
+				map[string]interface{}{"id": "cus_3", "email": "c@example.com", "balance": 3},
+			},
+		})
+	}))
+	defer server.Close()
+
+	dest := testDestination(filepath.Join(t.TempDir(), "load.db"))
+	cfg := &config.Pipeline{
+		Name: "sink-load",
+		Source: config.Source{
+			Type:     config.SourceTypeREST,
+			Endpoint: server.URL,
+			ResponseMapping: config.ResponseMapping{
+				RootPath: "data",
+				Fields: []config.Field{
+					{Name: "id", Path: "id"},
+					{Name: "email", Path: "email"},
+					{Name: "balance", Path: "balance"},
+				},
+			},
+		},
+		Destination: *dest,
+	}
+
+	connector, err := core.NewConnector(cfg)
+	if err != nil {
+		t.Fatalf("create connector: %v", err)
+	}
+	s, err := sink.Create(&cfg.Destination)
+	if err != nil {
+		t.Fatalf("create sink: %v", err)
+	}
+	defer s.Close()
+
+	ctx := context.Background()
+	n, err := sink.Load(ctx, s, connector.Stream(ctx), 2)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("expected 3 records written, got %d", n)
+	}
+}
@@ -1,6 +1,7 @@
 package transform_test
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -57,6 +58,8 @@ func TestIntTransform(t *testing.T) {
 		{"float", 3.14, 3, false},
 		{"string valid", "123", 123, false},
 		{"string invalid", "abc", 0, true},
+		{"json number snowflake", json.Number("1234567890123456789"), 1234567890123456789, false},
+		{"json number fraction", json.Number("7.9"), 7, false},
 	}
 
 	for _, tt := range tests {
@@ -286,6 +289,7 @@ func TestFloatTransform(t *testing.T) {
 		{"int", 42, 42.0, false},
 		{"string valid", "3.14", 3.14, false},
 		{"string invalid", "abc", 0.0, true},
+		{"json number", json.Number("2.5e3"), 2500.0, false},
 	}
 
 	for _, tt := range tests {
@@ -302,11 +306,55 @@ func TestFloatTransform(t *testing.T) {
 	}
 }
 
+func TestDecimalTransform(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   map[string]interface{}
+		input    interface{}
+		expected transform.Decimal
+		wantErr  bool
+	}{
+		{"json number", nil, json.Number("19.990"), "19.990", false},
+		{"large integer", nil, json.Number("1234567890123456789"), "1234567890123456789", false},
+		{"exponent", nil, json.Number("1.5e-3"), "0.0015", false},
+		{"string", nil, "-0.10", "-0.10", false},
+		{"float without artifacts", nil, 0.1, "0.1", false},
+		{"int", nil, 42, "42", false},
+		{"round half away from zero", map[string]interface{}{"scale": 2}, "2.345", "2.35", false},
+		{"round negative", map[string]interface{}{"scale": 1}, json.Number("-1.25"), "-1.3", false},
+		{"pad scale", map[string]interface{}{"scale": 2}, json.Number("3"), "3.00", false},
+		{"fraction rejected", nil, "1/3", "", true},
+		{"invalid", nil, "abc", "", true},
+	}
+
+	registry := transform.NewRegistry()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformer, err := registry.Create("decimal", tt.config)
+			if err != nil {
+				t.Fatalf("failed to create decimal transformer: %v", err)
+			}
+			result, err := transformer.Transform(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && result != tt.expected {
+				t.Errorf("expected %v, got %#v", tt.expected, result)
+			}
+		})
+	}
+
+	b, err := json.Marshal(map[string]interface{}{"amount": transform.Decimal("12.50")})
+	if err != nil || string(b) != `{"amount":12.50}` {
+		t.Errorf("expected decimal to marshal as a JSON number, got %s, %v", b, err)
+	}
+}
+
 func TestRegistry(t *testing.T) {
 	registry := transform.NewRegistry()
 
 	// Test all registered types exist
-	types := []string{"string", "int", "float", "bool", "date", "split", "join", "upper", "lower", "trim"}
+	types := []string{"string", "int", "float", "bool", "date", "split", "join", "upper", "lower", "trim", "decimal"}
 
 	for _, typ := range types {
 		_, err := registry.Create(typ, nil)